@@ -24,6 +24,8 @@ func (f FlagColumn) String() string {
 		return "ChgLength"
 	case ChgToArray:
 		return "ChgToArray"
+	case ChgIdentity:
+		return "ChgIdentity"
 	default:
 		return "Unknown"
 	}
@@ -36,12 +38,30 @@ const (
 	ChgDefault
 	ChgLength
 	ChgToArray
+	ChgIdentity
 )
 
+// kinds of identity columns
 const (
-	tplAlterColumnType = " ALTER COLUMN %s TYPE %s USING %[1]s::%s"
-	tplAlterNotNull    = " ALTER COLUMN %s SET not null"
-	tplAlterSetDefault = " ALTER COLUMN %s SET DEFAULT %s"
+	IdentityAlways    = "ALWAYS"
+	IdentityByDefault = "BY DEFAULT"
+)
+
+const (
+	tplAlterColumnType   = " ALTER COLUMN %s TYPE %s USING %[1]s::%s"
+	tplAlterNotNull      = " ALTER COLUMN %s SET not null"
+	tplAlterSetDefault   = " ALTER COLUMN %s SET DEFAULT %s"
+	tplAlterAddIdentity  = " ALTER COLUMN %s DROP DEFAULT, ALTER COLUMN %[1]s ADD GENERATED %s AS IDENTITY"
+	tplAlterSetIdentity  = " ALTER COLUMN %s SET GENERATED %s"
+	tplAlterDropIdentity = " ALTER COLUMN %s DROP IDENTITY IF EXISTS"
+	tplRestartIdentity   = ";\nSELECT setval(pg_get_serial_sequence('%s', '%s'), coalesce(max(%[2]s), 0) + 1, false) FROM %[1]s"
+)
+
+const (
+	tplAddConstraint     = "ALTER TABLE %s ADD %s"
+	tplReplaceConstraint = "ALTER TABLE %s DROP CONSTRAINT %s, ADD %s"
+	tplDropConstraint    = "ALTER TABLE %s DROP CONSTRAINT %s"
+	tplDropTrigger       = "DROP TRIGGER %s ON %s"
 )
 
 // const for DB config messages
@@ -52,23 +72,17 @@ const (
 
 // regex const's
 var (
-	regColumns       = regexp.MustCompile(`\(([^():]+)`)
-	regColumn        = regexp.MustCompile(`'?\b[^'():,]+\b`)
-	regExprSeparator = regexp.MustCompile(`[^(\s]*\([^)]*\)|[^'():,\s]+`)
+	regColumns  = regexp.MustCompile(`\(([^():]+)`)
+	regColumn   = regexp.MustCompile(`'?\b[^'():,]+\b`)
+	regExprCast = regexp.MustCompile(`::(?:character varying|double precision|timestamp with(?:out)? time zone|\w+)(?:\[])?`)
 )
 
 // regexp const for parsing DDL
 var (
-	regIndex            = regexp.MustCompile(`create\s+(?P<unique>unique)?\s*index(?:\s+if\s+not\s+exists)?\s+(?P<index>\w+)\s+on\s+(?P<table>\w+)(?:\s+using\s+\w+)?\s*\((?P<expr>(?:\s*(?:[^(,]*\([^)]*\)|[^'():,]+),?)+)\)\s*(?P<where>where\s+[^)]\))?`)
-	regForeignIndex     = regexp.MustCompile(`alter\s+table\s+(?P<table>\w+)\s+add\s+constraint\s+(?P<index>\w+)\s+foreign\s+key\s+\((?P<expr>[^;]+?)\)\s+references\s+(?P<fTable>\w+)[\S\s]+?on\s+update\s+(?P<onUpdate>[\s\w]+?)\s+on\s+delete\s+(?P<onDelete>[\s\w]+?)\s*`)
-	regCommentTable     = regexp.MustCompile(`^\s*(?i)COMMENT\s+ON\s+(?:TABLE|(?:MATERIALIZED\s)?VIEW)\s+(\w+)\s+IS\s+'(.+)'`)
-	regCommentColumn    = regexp.MustCompile(`^\s*(?i)COMMENT\s+ON\s+COLUMN\s+("[^"]+"|\w+)\.("[^"]+"|\w+)\s+IS\s+'(.+)'`)
-	regPartitionTable   = regexp.MustCompile(`create\s+table(?:\s+if\s+not\s+exists)?\s+(\w+)\s+partition`)
-	regTable            = regexp.MustCompile(`(?i)create\s+(?:or\s+replace\s+view|table)\s+(?P<name>\w+)\s*\((?P<builderOpts>(\s*(\S+)\s+(?P<define>[\w\[\]':\s]+(\(\s*\d+(,\s*\d+)?\s*\))?[\w.+\s]*)(?:[\s\w]*\(?[\w.+\s]*(?:'[^']*')?(?:\s*::\s*\w+)?\)?)?,?)*)\s*(primary\s+key\s*\([^)]+\))?\s*\)`)
-	regField            = regexp.MustCompile(`^\s*("[^"]+"|\w+)\s+((?:[\w\s]+(?:\(\s*\d+(?:,\s*\d+)?\))?(?:\[\d*])*)?(?:[\s\w]*\(*[\w.+\s]*(?:'[^']*')?\)?(?:\s*::\s*\w+)?\)?[^,]*)?)`)
-	regFieldName        = regexp.MustCompile(`^"[^"]+"|\w+$`)
+	regFieldName = regexp.MustCompile(`^"[^"]+"|\w+$`)
+	regIdentity  = regexp.MustCompile(`(?i)\s*generated\s+(always|by\s+default)\s+as\s+identity(?:\s*\([^)]*\))?`)
+	// Deprecated: RegDefault doesn't handle nested brackets, use DefaultDDL
 	RegDefault          = regexp.MustCompile(`(?i)default\s+(\(?[\w.+\s]*(?:'[^']*')?(?:\s*::\s*\w+)?\)?)`)
-	regView             = regexp.MustCompile(`create\s+or\s+replace\s+view\s+(?P<name>\w+)\s+(with\s*\([\w,=\s]+\)\s*)?as\s+select`)
 	regRelationNotExist = regexp.MustCompile(`relation\s+"(\w+)" does not exist`)
 	regTypeNotExist     = regexp.MustCompile(`type\s+"(\w+)"\s+does not exist`)
 )
@@ -77,6 +91,8 @@ var (
 const (
 	DB_SETTING              = TypeCfgDB("set of CfgDB")
 	RECREATE_MATERIAZE_VIEW = TypeCfgDB("drop materiaze view before create")
+	DROP_ABSENT_CONSTRAINTS = TypeCfgDB("drop constraints which DDL of table doesn't declare")
+	DROP_ABSENT_TRIGGERS    = TypeCfgDB("drop triggers which DDL doesn't declare")
 )
 
 // regexp const for parsing pgError. Examples:
@@ -96,4 +112,7 @@ var (
 	regErrNullValues = regexp.MustCompile(`[\s\S]+?column\s+"(\w+)"\s+of\s+relation\s+"(\w+)"\s+contains\s+null\s+values`)
 )
 
+// codeQueryCanceled is SQLSTATE of canceled query (statement_timeout or cancel request)
+const codeQueryCanceled = "57014"
+
 const ErrCannotAlterColumnUsedView = "cannot alter type of a column used by a view or rule"
@@ -0,0 +1,256 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ruslanBik4/gotools/typesExt"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// sampleRows is count of rows for inferring types of columns
+const sampleRows = 100
+
+// Column implement dbEngine.Column for field of CSV, its type is inferred from values
+type Column struct {
+	name       string
+	kind       types.BasicKind
+	isNullable bool
+	primary    bool
+	colDefault any
+	table      *Table
+}
+
+// NewColumn create new Column with text type
+func NewColumn(table *Table, name string) *Column {
+	return &Column{name: name, kind: types.String, table: table}
+}
+
+// BasicType return GoLangs type of column
+func (c *Column) BasicType() types.BasicKind {
+	return c.kind
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *Column) BasicTypeInfo() types.BasicInfo {
+	switch c.kind {
+	case types.Bool:
+		return types.IsBoolean
+	case types.Int64:
+		return types.IsInteger
+	case types.Float64:
+		return types.IsFloat
+	case types.String:
+		return types.IsString
+	default:
+		return types.IsUntyped
+	}
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *Column) CheckAttr(fieldDefine string) []dbEngine.FlagColumn {
+	return nil
+}
+
+// CharacterMaximumLength return max of length text columns, CSV hasn't limits
+func (c *Column) CharacterMaximumLength() int {
+	return 0
+}
+
+// Comment of column
+func (c *Column) Comment() string {
+	return ""
+}
+
+// Name of column
+func (c *Column) Name() string {
+	return c.name
+}
+
+// AutoIncrement return true if column is autoincrement
+func (c *Column) AutoIncrement() bool {
+	return false
+}
+
+// IsNullable return isNullable flag
+func (c *Column) IsNullable() bool {
+	return c.isNullable
+}
+
+// Default return default value of column
+func (c *Column) Default() any {
+	return c.colDefault
+}
+
+// SetDefault set default value into column
+func (c *Column) SetDefault(d any) {
+	c.colDefault = d
+}
+
+// Foreign return foreign key of column
+func (c *Column) Foreign() *dbEngine.ForeignKey {
+	return nil
+}
+
+// UserDefinedType return user defined type of column
+func (c *Column) UserDefinedType() *dbEngine.Types {
+	return nil
+}
+
+// Table of column
+func (c *Column) Table() dbEngine.Table {
+	return c.table
+}
+
+// Primary return true if column is primary key
+func (c *Column) Primary() bool {
+	return c.primary
+}
+
+// Type return name of PostgreSQL type according to inferred type
+func (c *Column) Type() string {
+	switch c.kind {
+	case types.Bool:
+		return "bool"
+	case types.Int64:
+		return "int8"
+	case types.Float64:
+		return "float8"
+	case typesExt.TStruct:
+		return "timestamptz"
+	default:
+		return "text"
+	}
+}
+
+// Required return true if column must have value
+func (c *Column) Required() bool {
+	return !c.isNullable && c.colDefault == nil
+}
+
+// SetNullable set nullable flag of column
+func (c *Column) SetNullable(f bool) {
+	c.isNullable = f
+}
+
+// inferType choose the narrowest type which parses all sampled values,
+// empty value means NULL
+func (c *Column) inferType(values []string) {
+	c.isNullable = false
+	hasValues := false
+	kinds := []types.BasicKind{types.Int64, types.Float64, types.Bool, typesExt.TStruct}
+	for _, value := range values {
+		if value == "" {
+			c.isNullable = true
+			continue
+		}
+
+		hasValues = true
+		kinds = slices.DeleteFunc(kinds, func(kind types.BasicKind) bool {
+			_, err := parseValue(kind, value)
+			return err != nil
+		})
+	}
+
+	if len(kinds) == 0 || !hasValues {
+		c.kind = types.String
+		return
+	}
+
+	c.kind = kinds[0]
+}
+
+// normalize converts value of argument into type of column
+func (c *Column) normalize(arg any) (any, error) {
+	if arg == nil {
+		return nil, nil
+	}
+
+	var err error
+	switch c.kind {
+	case types.Int64:
+		var v int64
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case types.Float64:
+		var v float64
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case types.Bool:
+		var v bool
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case typesExt.TStruct:
+		var v time.Time
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	default:
+		var v string
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, c.name)
+	}
+
+	return arg, nil
+}
+
+// format return text of value for CSV
+func (c *Column) format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// convert value of CSV according to type of column
+func (c *Column) convert(value string) (any, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	return parseValue(c.kind, value)
+}
+
+func parseValue(kind types.BasicKind, value string) (any, error) {
+	switch kind {
+	case types.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case types.Float64:
+		return strconv.ParseFloat(value, 64)
+	case types.Bool:
+		switch strings.ToLower(value) {
+		case "true", "t":
+			return true, nil
+		case "false", "f":
+			return false, nil
+		default:
+			return nil, dbEngine.NewErrWrongType("bool", value, "value")
+		}
+	case typesExt.TStruct:
+		return dbEngine.ParseTime(value)
+	default:
+		return value, nil
+	}
+}
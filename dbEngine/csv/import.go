@@ -2,11 +2,17 @@ package csv
 
 import (
 	"bufio"
+	"encoding/csv"
+	"fmt"
+	"go/types"
 	"io"
 	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
 
 	"github.com/ruslanBik4/dbEngine/dbEngine"
 	"github.com/ruslanBik4/gotools"
@@ -82,3 +88,284 @@ func NewCsvRows(f io.Reader, table dbEngine.Table) (c *CsvReader, err error) {
 
 	return
 }
+
+// ErrNullValue means that CSV has NULL for not-null column without default value
+var ErrNullValue = errors.New("null value for not-null column")
+
+// ErrWrongQuote means that quote isn't single-byte char
+var ErrWrongQuote = errors.New("quote must be single-byte char")
+
+// ImportOptions consists of parameters for ImportCSV
+type ImportOptions struct {
+	// Comma is delimiter of fields, ',' by default
+	Comma rune
+	// Quote is char for quoting of fields, '"' by default
+	Quote rune
+	// Null is string which means NULL value, empty string by default
+	Null string
+	// Rename maps names of CSV header into names of table columns
+	Rename map[string]string
+	// Columns are used instead of header if CSV hasn't it
+	Columns []string
+	// SkipUnknown ignores fields which are absent in table instead of error
+	SkipUnknown bool
+	// MaxErrors stops import after MaxErrors wrong rows, 0 means unlimited
+	MaxErrors int
+}
+
+// RowError consists of error of import one row of CSV
+type RowError struct {
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+// Error implement error interface
+func (e RowError) Error() string {
+	if e.Column > "" {
+		return fmt.Sprintf("line %d, column '%s' (%q): %v", e.Line, e.Column, e.Value, e.Err)
+	}
+
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap return origin error
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReport is result of ImportCSV
+type ImportReport struct {
+	Rows     int
+	Inserted int
+	Errors   []RowError
+}
+
+// ErrTooManyErrors means that import stopped after ImportOptions.MaxErrors wrong rows
+type ErrTooManyErrors struct {
+	Count int
+}
+
+// Error implement error interface
+func (e ErrTooManyErrors) Error() string {
+	return fmt.Sprintf("import stopped after %d wrong rows", e.Count)
+}
+
+// ImportCSV reads CSV from r, converts values according to types of table columns & inserts rows into table,
+// wrong rows don't stop import, they are collected into ImportReport.Errors
+func ImportCSV(ctx context.Context, r io.Reader, table dbEngine.Table, opts ImportOptions) (*ImportReport, error) {
+	quote := byte('"')
+	if opts.Quote != 0 && opts.Quote != '"' {
+		if opts.Quote >= utf8.RuneSelf {
+			return nil, ErrWrongQuote
+		}
+		quote = byte(opts.Quote)
+		r = quoteReader{Reader: r, quote: quote}
+	}
+
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+
+	names := opts.Columns
+	if len(names) == 0 {
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		names = make([]string, len(header))
+		for i, name := range header {
+			names[i] = strings.TrimSpace(swapQuote(name, quote))
+		}
+	}
+
+	columns, err := mapColumns(table, names, opts)
+	if err != nil {
+		return nil, err
+	}
+	// otherwise reader takes count of fields from first row when CSV hasn't header
+	reader.FieldsPerRecord = len(columns)
+
+	report := &ImportReport{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			return report, nil
+		}
+
+		report.Rows++
+		if err != nil {
+			parseErr, ok := err.(*csv.ParseError)
+			if !ok {
+				return report, err
+			}
+			report.Errors = append(report.Errors, RowError{Line: parseErr.StartLine, Err: parseErr.Err})
+		} else {
+			line, _ := reader.FieldPos(0)
+			if rowErr := insertRecord(ctx, table, columns, record, line, quote, opts.Null); rowErr != nil {
+				report.Errors = append(report.Errors, *rowErr)
+			} else {
+				report.Inserted++
+			}
+		}
+
+		if opts.MaxErrors > 0 && len(report.Errors) >= opts.MaxErrors {
+			return report, ErrTooManyErrors{Count: len(report.Errors)}
+		}
+	}
+}
+
+// mapColumns return columns of table for every field of CSV (nil for skipped)
+func mapColumns(table dbEngine.Table, names []string, opts ImportOptions) ([]dbEngine.Column, error) {
+	columns := make([]dbEngine.Column, len(names))
+	for i, name := range names {
+		if newName, ok := opts.Rename[name]; ok {
+			name = newName
+		}
+
+		col := table.FindColumn(name)
+		if col == nil {
+			col = table.FindColumn(strings.ToLower(name))
+		}
+
+		switch {
+		case col != nil:
+			columns[i] = col
+		case !opts.SkipUnknown:
+			return nil, fmt.Errorf("%w: %w", ErrWrongColumnName, dbEngine.NewErrNotFoundColumn(table.Name(), name))
+		}
+	}
+
+	return columns, nil
+}
+
+func insertRecord(ctx context.Context, table dbEngine.Table, columns []dbEngine.Column, record []string, line int, quote byte, null string) *RowError {
+	names := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns))
+	for i, col := range columns {
+		if col == nil {
+			continue
+		}
+
+		value := swapQuote(record[i], quote)
+		if value == null {
+			switch {
+			case col.IsNullable():
+				names = append(names, col.Name())
+				args = append(args, nil)
+			case col.Default() == nil:
+				return &RowError{Line: line, Column: col.Name(), Value: value, Err: ErrNullValue}
+			}
+			// otherwise DB sets default value
+			continue
+		}
+
+		arg, err := ConvertValue(col, value)
+		if err != nil {
+			return &RowError{Line: line, Column: col.Name(), Value: value, Err: err}
+		}
+
+		names = append(names, col.Name())
+		args = append(args, arg)
+	}
+
+	_, err := table.Insert(ctx, dbEngine.ColumnsForSelect(names...), dbEngine.ArgsForSelect(args...))
+	if err != nil {
+		return &RowError{Line: line, Err: err}
+	}
+
+	return nil
+}
+
+// ConvertValue converts text value of CSV into type of column according to its BasicType
+func ConvertValue(col dbEngine.Column, value string) (any, error) {
+	// arrays pass as text & DB parses them
+	if typ := col.Type(); strings.HasPrefix(typ, "_") || strings.HasSuffix(typ, "[]") {
+		return value, nil
+	}
+
+	switch kind := col.BasicType(); kind {
+	case types.Bool, types.UntypedBool:
+		return strconv.ParseBool(strings.TrimSpace(value))
+
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64, types.UntypedInt:
+		return strconv.ParseInt(strings.TrimSpace(value), 10, bitSize(kind))
+
+	case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+		return strconv.ParseUint(strings.TrimSpace(value), 10, bitSize(kind))
+
+	case types.Float32:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+		return float32(f), err
+
+	case types.Float64, types.UntypedFloat:
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+
+	case types.String:
+		if maxLen := col.CharacterMaximumLength(); maxLen > 0 && utf8.RuneCountInString(value) > maxLen {
+			return nil, fmt.Errorf("value is longer than %d chars", maxLen)
+		}
+		return value, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func bitSize(kind types.BasicKind) int {
+	switch kind {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// quoteReader replaces custom quote with '"' & vice versa, because encoding/csv supports only '"'
+type quoteReader struct {
+	io.Reader
+	quote byte
+}
+
+// Read implement io.Reader
+func (r quoteReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	for i, b := range p[:n] {
+		switch b {
+		case r.quote:
+			p[i] = '"'
+		case '"':
+			p[i] = r.quote
+		}
+	}
+
+	return n, err
+}
+
+// swapQuote restores chars which were replaced by quoteReader
+func swapQuote(s string, quote byte) string {
+	if quote == '"' {
+		return s
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case rune(quote):
+			return '"'
+		case '"':
+			return rune(quote)
+		default:
+			return r
+		}
+	}, s)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// insertTable stores arguments of Insert instead of DB
+type insertTable struct {
+	dbEngine.Table
+	columns []dbEngine.Column
+	rows    [][]any
+}
+
+func (t *insertTable) Name() string {
+	return "goods"
+}
+
+func (t *insertTable) Columns() []dbEngine.Column {
+	return t.columns
+}
+
+func (t *insertTable) FindColumn(name string) dbEngine.Column {
+	for _, col := range t.columns {
+		if col.Name() == name {
+			return col
+		}
+	}
+
+	return nil
+}
+
+func (t *insertTable) Insert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, err
+	}
+	if b.Args[0] == "duplicate" {
+		return 0, errors.New("duplicate key value")
+	}
+	t.rows = append(t.rows, b.Args)
+
+	return 1, nil
+}
+
+func newInsertTable() *insertTable {
+	return &insertTable{
+		columns: []dbEngine.Column{
+			dbEngine.NewStringColumn("name", "", true, 10),
+			dbEngine.NewNumberColumn("count", "", false),
+		},
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		opts     ImportOptions
+		rows     [][]any
+		inserted int
+		errLines []int
+	}{
+		{
+			name:     "default",
+			src:      "name,count\n\"a,b\",1\nc,2\n",
+			rows:     [][]any{{"a,b", int64(1)}, {"c", int64(2)}},
+			inserted: 2,
+		},
+		{
+			name: "delimiter, quote, null & renames",
+			src:  "Title;Qty;Note\n'it''s \"x\"';NULL;skip\n",
+			opts: ImportOptions{
+				Comma:       ';',
+				Quote:       '\'',
+				Null:        "NULL",
+				Rename:      map[string]string{"Title": "name", "Qty": "count"},
+				SkipUnknown: true,
+			},
+			// NULL of not-null column with default isn't inserted
+			rows:     [][]any{{`it's "x"`}},
+			inserted: 1,
+		},
+		{
+			name:     "columns without header & per-row errors",
+			src:      "ok,1\ntoo long name,2\nbad,x\nduplicate,3\nshort\nlast,4\n",
+			opts:     ImportOptions{Columns: []string{"name", "count"}},
+			rows:     [][]any{{"ok", int64(1)}, {"last", int64(4)}},
+			inserted: 2,
+			errLines: []int{2, 3, 4, 5},
+		},
+		{
+			name:     "short first row & long row without header",
+			src:      "short\nok,1\nlong,2,extra\n",
+			opts:     ImportOptions{Columns: []string{"name", "count"}},
+			rows:     [][]any{{"ok", int64(1)}},
+			inserted: 1,
+			errLines: []int{1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := newInsertTable()
+			report, err := ImportCSV(context.Background(), strings.NewReader(tt.src), table, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.inserted, report.Inserted)
+			if tt.rows != nil {
+				assert.Equal(t, tt.rows, table.rows)
+			}
+
+			lines := make([]int, 0)
+			for _, rowErr := range report.Errors {
+				lines = append(lines, rowErr.Line)
+			}
+			assert.ElementsMatch(t, tt.errLines, lines, report.Errors)
+		})
+	}
+}
+
+func TestImportCSV_Errors(t *testing.T) {
+	table := newInsertTable()
+	_, err := ImportCSV(context.Background(), strings.NewReader("name,price\n"), table, ImportOptions{})
+	assert.ErrorIs(t, err, ErrWrongColumnName)
+
+	_, err = ImportCSV(context.Background(), strings.NewReader("name\n"), table, ImportOptions{Quote: '«'})
+	assert.ErrorIs(t, err, ErrWrongQuote)
+
+	report, err := ImportCSV(context.Background(), strings.NewReader("name,count\na,x\nb,y\nc,z\n"), table, ImportOptions{MaxErrors: 2})
+	assert.Equal(t, ErrTooManyErrors{Count: 2}, err)
+	assert.Equal(t, 2, report.Rows)
+}
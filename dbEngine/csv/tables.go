@@ -6,10 +6,15 @@ package csv
 
 import (
 	"encoding/csv"
+	"fmt"
+	"go/types"
 	"os"
 	"path"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
@@ -20,16 +25,22 @@ import (
 type Conn struct {
 }
 
-// Table implement dbEngine interface Table for csv
+// Table implement dbEngine interface Table for csv,
+// it reads all rows into memory & performs Select* without DB
 type Table struct {
 	columns  []dbEngine.Column
 	indexes  dbEngine.Indexes
 	fileName string
+	filePath string
 	csv      *csv.Reader
+	records  [][]string
+	rows     [][]any
+	primary  []string
+	lock     sync.RWMutex
 }
 
-// NewTable open csv & init conn
-func NewTable(filePath string) (*Table, error) {
+// NewTable open csv & init conn, primary are names of columns which are keys for Upsert
+func NewTable(filePath string, primary ...string) (*Table, error) {
 	t := &Table{}
 	if filePath > "" {
 		err := t.InitConn(context.Background(), filePath)
@@ -38,9 +49,31 @@ func NewTable(filePath string) (*Table, error) {
 		}
 	}
 
+	if len(primary) > 0 {
+		if err := t.SetPrimary(primary...); err != nil {
+			return nil, err
+		}
+	}
+
 	return t, nil
 }
 
+// SetPrimary declares columns of primary key, they are keys for Upsert & must be unique
+func (t *Table) SetPrimary(names ...string) error {
+	for _, name := range names {
+		if dbEngine.ColumnIndex(t.columns, name) < 0 {
+			return dbEngine.NewErrNotFoundColumn(t.Name(), name)
+		}
+	}
+
+	for _, col := range t.columns {
+		col.(*Column).primary = slices.Contains(names, col.Name())
+	}
+	t.primary = names
+
+	return nil
+}
+
 // Indexes get indexex according to table
 func (t *Table) Indexes() dbEngine.Indexes {
 	return t.indexes
@@ -48,7 +81,7 @@ func (t *Table) Indexes() dbEngine.Indexes {
 
 // Comment of Table
 func (t *Table) Comment() string {
-	panic("implement me")
+	return "CSV file " + t.filePath
 }
 
 // InitConn create csv reader
@@ -59,8 +92,11 @@ func (t *Table) InitConn(ctx context.Context, filePath string) error {
 		return errors.Wrap(err, "os.Open "+filePath)
 	}
 
+	defer f.Close()
+
 	t.csv = csv.NewReader(f)
 	t.fileName = strings.Split(path.Base(filePath), ".")[0]
+	t.filePath = filePath
 
 	return t.GetColumns(ctx, nil)
 }
@@ -72,7 +108,7 @@ func (t *Table) GetSchema(ctx context.Context) (map[string]dbEngine.Table, map[s
 
 // GetStat return stats of conn
 func (t *Table) GetStat() string {
-	panic("implement me")
+	return fmt.Sprintf("%s: %d rows, %d columns", t.fileName, len(t.rows), len(t.columns))
 }
 
 // ExecDDL execute sql
@@ -106,7 +142,8 @@ func (t *Table) FindIndex(name string) *dbEngine.Index {
 	return nil
 }
 
-// GetColumns получение значений полей для форматирования данных
+// GetColumns получение значений полей для форматирования данных,
+// it reads all rows of CSV & infers types of columns
 func (t *Table) GetColumns(ctx context.Context, dbTypes map[string]dbEngine.Types) error {
 	rec, err := t.csv.Read()
 	if err != nil {
@@ -115,30 +152,197 @@ func (t *Table) GetColumns(ctx context.Context, dbTypes map[string]dbEngine.Type
 
 	t.columns = make([]dbEngine.Column, len(rec))
 	for i, name := range rec {
-		t.columns[i] = dbEngine.NewStringColumn(strings.TrimSpace(name), "", false)
+		t.columns[i] = NewColumn(t, strings.TrimSpace(name))
+	}
+
+	t.records, err = t.csv.ReadAll()
+	if err != nil {
+		return errors.Wrap(err, "csv.ReadAll")
+	}
+
+	t.rows = make([][]any, len(t.records))
+	for i := range t.rows {
+		t.rows[i] = make([]any, len(t.columns))
+	}
+
+	for i := range t.columns {
+		t.readColumn(i)
 	}
 
 	return nil
 }
 
-// Insert new row & return new ID or rowsAffected if there not autoinc field
+// readColumn infers type of column 'i' & converts its values
+func (t *Table) readColumn(i int) {
+	col := t.columns[i].(*Column)
+	values := make([]string, min(len(t.records), sampleRows))
+	for j := range values {
+		values[j] = t.records[j][i]
+	}
+	col.inferType(values)
+
+	for j, record := range t.records {
+		value, err := col.convert(record[i])
+		if err != nil {
+			// sampled rows don't present all values, so use text type
+			col.kind = types.String
+			t.readStrings(i)
+			return
+		}
+		t.rows[j][i] = value
+	}
+}
+
+// readStrings converts values of column 'i' without inferring type, text values are always converted
+func (t *Table) readStrings(i int) {
+	col := t.columns[i].(*Column)
+	for j, record := range t.records {
+		t.rows[j][i], _ = col.convert(record[i])
+	}
+}
+
+// Insert new row & return rowsAffected, file of table is rewritten
 func (t *Table) Insert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
-	panic("implement me")
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	if err := b.ValidateArgs(true); err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	row, err := t.newRow(b.ColumnNames(), b.Args)
+	if err != nil {
+		return 0, err
+	}
+
+	return 1, t.write(append(slices.Clone(t.rows), row))
 }
 
-// Update table according to Options
+// Update table according to Options, file of table is rewritten
 func (t *Table) Update(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
-	panic("implement me")
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	if err := b.ValidateArgs(false); err != nil {
+		return 0, err
+	}
+
+	names, values, conditions, err := b.UpdateValues()
+	if err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	rows := slices.Clone(t.rows)
+	count := int64(0)
+	for i, row := range rows {
+		ok, err := dbEngine.MatchConditions(conditions, func(name string) (any, error) {
+			return dbEngine.RowValue(t, row, name)
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			rows[i], err = t.setValues(row, names, values)
+			if err != nil {
+				return 0, err
+			}
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	return count, t.write(rows)
 }
 
-// Upsert preforms INSERT sql or UPDATE if record with primary keys exists
+// Upsert preforms INSERT or UPDATE if record with primary keys exists, file of table is rewritten
 func (t *Table) Upsert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
-	panic("implement me")
+	if len(t.primary) == 0 {
+		return 0, ErrNoPrimary
+	}
+
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	if err := b.ValidateArgs(true); err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	names := b.ColumnNames()
+	row, err := t.newRow(names, b.Args)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := slices.Clone(t.rows)
+	key := t.key(row)
+	i := slices.IndexFunc(rows, func(r []any) bool {
+		return t.key(r) == key
+	})
+	if i < 0 {
+		rows = append(rows, row)
+	} else {
+		rows[i], err = t.setValues(rows[i], names, b.Args)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return 1, t.write(rows)
 }
 
-// Delete row of table according to Options
+// Delete row of table according to Options, file of table is rewritten
 func (t *Table) Delete(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
-	panic("implement me")
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	conditions, err := b.Conditions()
+	if err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	rows := make([][]any, 0, len(t.rows))
+	for _, row := range t.rows {
+		ok, err := dbEngine.MatchConditions(conditions, func(name string) (any, error) {
+			return dbEngine.RowValue(t, row, name)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			rows = append(rows, row)
+		}
+	}
+
+	count := int64(len(t.rows) - len(rows))
+	if count == 0 {
+		return 0, nil
+	}
+
+	return count, t.write(rows)
 }
 
 // Name of Table
@@ -146,27 +350,128 @@ func (t *Table) Name() string {
 	return t.fileName
 }
 
+// PrimaryKey return columns of primary key of table
+func (t *Table) PrimaryKey() []dbEngine.Column {
+	return dbEngine.PrimaryColumns(t)
+}
+
+// UniqueConstraints return unique constraints of table
+func (t *Table) UniqueConstraints() dbEngine.Constraints {
+	return dbEngine.UniqueIndexes(t)
+}
+
+// Constraints return primary key & unique constraints of table
+func (t *Table) Constraints() dbEngine.Constraints {
+	return dbEngine.KeyConstraints(t)
+}
+
+// ForeignKeys return foreign keys of table
+func (t *Table) ForeignKeys() dbEngine.ForeignKeys {
+	return dbEngine.ColumnsForeignKeys(t)
+}
+
+// Triggers return nil, table doesn't support triggers
+func (t *Table) Triggers() dbEngine.Triggers {
+	return nil
+}
+
+// Validate check values of columns according to their metadata before writing
+func (t *Table) Validate(columns []string, values []any) error {
+	return dbEngine.ValidateValues(t, columns, values)
+}
+
 // ReReadColumn renew properties of column 'name'
 func (t *Table) ReReadColumn(ctx context.Context, name string) dbEngine.Column {
-	panic("implement me")
+	i := dbEngine.ColumnIndex(t.columns, name)
+	if i < 0 {
+		return nil
+	}
+
+	t.readColumn(i)
+
+	return t.columns[i]
 }
 
 // Select run sql with Options (deprecated)
 func (t *Table) Select(ctx context.Context, Options ...dbEngine.BuildSqlOptions) error {
-	panic("implement me")
+	_, _, err := t.selectRows(Options...)
+	return err
 }
 
 // SelectOneAndScan run sqlof table  with Options & return rows into rowValues
 func (t *Table) SelectOneAndScan(ctx context.Context, row interface{}, Options ...dbEngine.BuildSqlOptions) error {
-	panic("implement me")
+	columns, rows, err := t.selectRows(Options...)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return pgx.ErrNoRows
+	}
+
+	var dest []any
+	switch r := row.(type) {
+	case []any:
+		dest = r
+	case dbEngine.RowScanner:
+		dest = r.GetFields(columns)
+	default:
+		dest = []any{r}
+	}
+
+	return dbEngine.ScanRow(dest, rows[0], columns)
 }
 
 // SelectAndScanEach run sql of table with Options & return every row into rowValues & run each
 func (t *Table) SelectAndScanEach(ctx context.Context, each func() error, rowValue dbEngine.RowScanner, Options ...dbEngine.BuildSqlOptions) error {
-	panic("implement me")
+	columns, rows, err := t.selectRows(Options...)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := dbEngine.ScanRow(rowValue.GetFields(columns), row, columns); err != nil {
+			return err
+		}
+
+		if each != nil {
+			if err := each(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 // SelectAndRunEach run sql of table with Options & performs each every row of query results
 func (t *Table) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, Options ...dbEngine.BuildSqlOptions) error {
-	panic("implement me")
+	columns, rows, err := t.selectRows(Options...)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if each != nil {
+			if err := each(row, columns); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectRows return selected columns & their values of rows according to Options
+// (Where with prefix operators, OrderBy, Offset, FetchOnlyRows)
+func (t *Table) selectRows(Options ...dbEngine.BuildSqlOptions) ([]dbEngine.Column, [][]any, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "setOption")
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return dbEngine.SelectRows(b, t, t.rows)
 }
@@ -7,9 +7,16 @@ package csv
 import (
 	"bytes"
 	"encoding/csv"
+	"fmt"
+	"os"
+	"path"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 
 	"github.com/ruslanBik4/dbEngine/dbEngine"
@@ -589,3 +596,225 @@ func TestTable_Upsert(t1 *testing.T) {
 		})
 	}
 }
+
+const testCSV = `id,name,price,active,created
+1,apple,1.5,true,2024-01-02
+2,banana,0.25,false,2024-02-03
+3,cherry,,t,2024-03-04
+4,Apricot,3,f,
+`
+
+func newTestTable(t *testing.T) *Table {
+	table := &Table{fileName: "goods", csv: csv.NewReader(bytes.NewBufferString(testCSV))}
+	require.NoError(t, table.GetColumns(context.Background(), nil))
+
+	return table
+}
+
+func TestTable_GetColumns_infer(t *testing.T) {
+	table := newTestTable(t)
+	want := map[string]string{
+		"id":      "int8",
+		"name":    "text",
+		"price":   "float8",
+		"active":  "bool",
+		"created": "timestamptz",
+	}
+	for _, col := range table.Columns() {
+		assert.Equal(t, want[col.Name()], col.Type(), col.Name())
+	}
+	assert.True(t, table.FindColumn("price").IsNullable())
+	assert.False(t, table.FindColumn("id").IsNullable())
+	assert.Equal(t, "goods: 4 rows, 5 columns", table.GetStat())
+
+	table.records[3][0] = "four"
+	assert.Equal(t, "text", table.ReReadColumn(context.Background(), "id").Type())
+	assert.Nil(t, table.ReReadColumn(context.Background(), "unknown"))
+}
+
+func TestTable_GetColumns_lateText(t *testing.T) {
+	var buf strings.Builder
+	buf.WriteString("id,qty\n")
+	for i := 1; i <= sampleRows+50; i++ {
+		fmt.Fprintf(&buf, "%d,%d\n", i, i*10)
+	}
+	buf.WriteString("151,x\n")
+
+	table := &Table{fileName: "late", csv: csv.NewReader(strings.NewReader(buf.String()))}
+	require.NoError(t, table.GetColumns(context.Background(), nil))
+
+	assert.Equal(t, "int8", table.FindColumn("id").Type())
+	assert.Equal(t, "text", table.FindColumn("qty").Type())
+	assert.Equal(t, "10", table.rows[0][1])
+	assert.Equal(t, "x", table.rows[sampleRows+50][1])
+}
+
+func TestTable_selectRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []dbEngine.BuildSqlOptions
+		want    [][]any
+		wantErr bool
+	}{
+		{
+			name:    "equal",
+			options: []dbEngine.BuildSqlOptions{dbEngine.ColumnsForSelect("name"), dbEngine.WhereForSelect("id"), dbEngine.ArgsForSelect(2)},
+			want:    [][]any{{"banana"}},
+		},
+		{
+			name: "operators, order & paging",
+			options: []dbEngine.BuildSqlOptions{
+				dbEngine.ColumnsForSelect("id", "name"),
+				dbEngine.WhereForSelect(">=id", "active"),
+				dbEngine.ArgsForSelect(1, true),
+				dbEngine.OrderBy("id desc"),
+				dbEngine.Offset(1),
+				dbEngine.FetchOnlyRows(1),
+			},
+			want: [][]any{{int64(1), "apple"}},
+		},
+		{
+			name: "regexp & is null",
+			options: []dbEngine.BuildSqlOptions{
+				dbEngine.ColumnsForSelect("id"),
+				dbEngine.WhereForSelect("*name", "price"),
+				dbEngine.ArgsForSelect("^ap", nil),
+			},
+			want: [][]any{},
+		},
+		{
+			name: "any & case insensitive",
+			options: []dbEngine.BuildSqlOptions{
+				dbEngine.ColumnsForSelect("id"),
+				dbEngine.WhereForSelect("*name", "id"),
+				dbEngine.ArgsForSelect("ap", []int{1, 4}),
+				dbEngine.OrderBy("name"),
+			},
+			want: [][]any{{int64(4)}, {int64(1)}},
+		},
+		{
+			name: "date",
+			options: []dbEngine.BuildSqlOptions{
+				dbEngine.ColumnsForSelect("id"),
+				dbEngine.WhereForSelect("<created"),
+				dbEngine.ArgsForSelect("2024-02-10"),
+			},
+			want: [][]any{{int64(1)}, {int64(2)}},
+		},
+		{
+			name:    "unknown column",
+			options: []dbEngine.BuildSqlOptions{dbEngine.ColumnsForSelect("weight")},
+			wantErr: true,
+		},
+	}
+	table := newTestTable(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rows, err := table.selectRows(tt.options...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rows)
+		})
+	}
+}
+
+func TestTable_SelectOneAndScan_csv(t *testing.T) {
+	table := newTestTable(t)
+	var (
+		name  string
+		price *float64
+		id    int32
+	)
+	err := table.SelectOneAndScan(context.Background(), []any{&id, &name, &price},
+		dbEngine.ColumnsForSelect("id", "name", "price"),
+		dbEngine.WhereForSelect("name"),
+		dbEngine.ArgsForSelect("cherry"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), id)
+	assert.Equal(t, "cherry", name)
+	assert.Nil(t, price)
+
+	err = table.SelectOneAndScan(context.Background(), &name,
+		dbEngine.ColumnsForSelect("name"),
+		dbEngine.WhereForSelect("name"),
+		dbEngine.ArgsForSelect("kiwi"))
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	count := 0
+	err = table.SelectAndRunEach(context.Background(),
+		func(values []any, columns []dbEngine.Column) error {
+			count++
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
+
+func TestTable_write(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "goods.csv")
+	require.NoError(t, os.WriteFile(filePath, []byte(testCSV), 0o644))
+
+	table, err := NewTable(filePath, "id")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	i, err := table.Insert(ctx, dbEngine.ColumnsForSelect("id", "name", "price"), dbEngine.ArgsForSelect(5, "kiwi", "0.5"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), i)
+
+	_, err = table.Insert(ctx, dbEngine.ColumnsForSelect("id", "name"), dbEngine.ArgsForSelect(5, "lime"))
+	assert.ErrorAs(t, err, &ErrDuplicateKey{})
+
+	i, err = table.Update(ctx,
+		dbEngine.ColumnsForSelect("price"),
+		dbEngine.WhereForSelect(">id"),
+		dbEngine.ArgsForSelect(2.0, 3))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), i)
+
+	i, err = table.Upsert(ctx, dbEngine.ColumnsForSelect("id", "name"), dbEngine.ArgsForSelect(1, "green apple"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), i)
+	_, err = table.Upsert(ctx, dbEngine.ColumnsForSelect("id", "name"), dbEngine.ArgsForSelect(6, "plum"))
+	require.NoError(t, err)
+
+	i, err = table.Delete(ctx, dbEngine.WhereForSelect("name"), dbEngine.ArgsForSelect("banana"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), i)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, `id,name,price,active,created
+1,green apple,1.5,true,2024-01-02
+3,cherry,,true,2024-03-04
+4,Apricot,2,false,
+5,kiwi,2,,
+6,plum,,,
+`, string(data))
+
+	files, err := os.ReadDir(path.Dir(filePath))
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "temp file must be renamed")
+
+	table, err = NewTable(filePath)
+	require.NoError(t, err)
+	_, err = table.Upsert(ctx, dbEngine.ColumnsForSelect("id"), dbEngine.ArgsForSelect(1))
+	assert.ErrorIs(t, err, ErrNoPrimary)
+}
+
+func TestTable_key(t *testing.T) {
+	table := &Table{fileName: "pairs", csv: csv.NewReader(strings.NewReader("a,b\n"))}
+	require.NoError(t, table.GetColumns(context.Background(), nil))
+	require.NoError(t, table.SetPrimary("a", "b"))
+
+	assert.NotEqual(t, table.key([]any{"a,b", "c"}), table.key([]any{"a", "b,c"}))
+	assert.NotEqual(t, table.key([]any{nil, "c"}), table.key([]any{"", "c"}))
+	assert.Equal(t, table.key([]any{"a", nil}), table.key([]any{"a", nil}))
+
+	assert.NoError(t, table.write([][]any{{"a,b", "c"}, {"a", "b,c"}, {nil, "c"}, {"", "c"}}))
+	err := table.write([][]any{{"a,b", "c"}, {"a,b", "c"}})
+	assert.Equal(t, ErrDuplicateKey{Table: "pairs", Key: "a,b,c"}, err)
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// ErrNoPrimary means that Upsert needs declared primary columns (see SetPrimary)
+var ErrNoPrimary = errors.New("primary columns aren't declared")
+
+// ErrDuplicateKey if table has row with same values of primary columns {Key}
+type ErrDuplicateKey struct {
+	Table string
+	Key   string
+}
+
+// Error implement error interface
+func (err ErrDuplicateKey) Error() string {
+	return fmt.Sprintf("duplicate key (%s) in table `%s`", err.Key, err.Table)
+}
+
+// newRow create row from values of columns 'names'
+func (t *Table) newRow(names []string, values []any) ([]any, error) {
+	return t.setValues(make([]any, len(t.columns)), names, values)
+}
+
+// setValues return copy of row with new values of columns 'names'
+func (t *Table) setValues(row []any, names []string, values []any) ([]any, error) {
+	if len(names) != len(values) {
+		return nil, dbEngine.NewErrWrongArgsLen(t.Name(), names, values)
+	}
+
+	newRow := make([]any, len(row))
+	copy(newRow, row)
+	for i, name := range names {
+		ind := dbEngine.ColumnIndex(t.columns, name)
+		if ind < 0 {
+			return nil, dbEngine.NewErrNotFoundColumn(t.Name(), name)
+		}
+
+		value, err := t.columns[ind].(*Column).normalize(values[i])
+		if err != nil {
+			return nil, err
+		}
+		newRow[ind] = value
+	}
+
+	return newRow, nil
+}
+
+// key return values of primary columns of row with their lengths,
+// so values with commas don't collide & NULL differs from empty string
+func (t *Table) key(row []any) string {
+	key := &strings.Builder{}
+	for _, name := range t.primary {
+		ind := dbEngine.ColumnIndex(t.columns, name)
+		if row[ind] == nil {
+			key.WriteString("-;")
+			continue
+		}
+
+		value := t.columns[ind].(*Column).format(row[ind])
+		_, _ = fmt.Fprintf(key, "%d:%s;", len(value), value)
+	}
+
+	return key.String()
+}
+
+// keyText return values of primary columns of row for messages
+func (t *Table) keyText(row []any) string {
+	key := make([]string, len(t.primary))
+	for i, name := range t.primary {
+		ind := dbEngine.ColumnIndex(t.columns, name)
+		key[i] = t.columns[ind].(*Column).format(row[ind])
+	}
+
+	return strings.Join(key, ",")
+}
+
+// write checks primary keys of rows, rewrites file of table atomically & replace rows in memory
+func (t *Table) write(rows [][]any) error {
+	if len(t.primary) > 0 {
+		keys := make(map[string]struct{}, len(rows))
+		for _, row := range rows {
+			key := t.key(row)
+			if _, ok := keys[key]; ok {
+				return ErrDuplicateKey{Table: t.Name(), Key: t.keyText(row)}
+			}
+			keys[key] = struct{}{}
+		}
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = make([]string, len(t.columns))
+		for j, col := range t.columns {
+			records[i][j] = col.(*Column).format(row[j])
+		}
+	}
+
+	// table without file (read from io.Reader) lives only in memory
+	if t.filePath > "" {
+		if err := t.writeFile(records); err != nil {
+			return err
+		}
+	}
+
+	t.rows, t.records = rows, records
+
+	return nil
+}
+
+// writeFile writes records into temp file & renames it into file of table
+func (t *Table) writeFile(records [][]string) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(t.filePath), filepath.Base(t.filePath)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "os.CreateTemp")
+	}
+
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	header := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		header[i] = col.Name()
+	}
+
+	w := csv.NewWriter(f)
+	if err = w.Write(header); err != nil {
+		return err
+	}
+	if err = w.WriteAll(records); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return errors.Wrap(os.Rename(f.Name(), t.filePath), "os.Rename")
+}
@@ -9,10 +9,10 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
-	"regexp"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
@@ -23,6 +23,10 @@ import (
 
 type CfgCreatorDB struct {
 	RecreateMaterView *struct{}
+	// DropAbsentConstraints drops constraints of table which its DDL doesn't declare
+	DropAbsentConstraints *struct{}
+	// DropAbsentTriggers drops triggers of table which DDL files don't declare
+	DropAbsentTriggers *struct{}
 }
 
 // CfgDB consist of setting for creating new DB
@@ -30,6 +34,18 @@ type CfgDB struct {
 	Url        string
 	GetSchema  *struct{}
 	CfgCreator *CfgCreatorDB
+	// settings of connections pool, zero values keep defaults of driver
+	MinConns          int32
+	MaxConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// AcquireTimeout limits waiting of free connection, zero value limits only SelectOneAndScan (5s),
+	// negative value disables limit
+	AcquireTimeout   time.Duration
+	StatementTimeout time.Duration
+	ApplicationName  string
+	SearchPath       string
 	// obsolete - change on CfgCreatorDB properties
 	Excluded []string
 	Included []string
@@ -54,12 +70,15 @@ type DB struct {
 	FuncsReplaced  []string
 	FuncsAdded     []string
 	relationTables map[string][]string
-	DbSet          map[string]*string
+	// names of triggers which DDL files of tables declare, phase of triggers doesn't drop them
+	tableTriggers map[string][]string
+	DbSet         map[string]*string
 }
 
 // NewDB create new DB instance & performs something migrations
 func NewDB(ctx context.Context, conn Connection) (*DB, error) {
 	db := &DB{
+		Cfg:            map[string]any{},
 		Conn:           conn,
 		ctx:            ctx,
 		relationTables: map[string][]string{},
@@ -85,6 +104,12 @@ func NewDB(ctx context.Context, conn Connection) (*DB, error) {
 			if cfg.CfgCreator.RecreateMaterView != nil {
 				db.Cfg[string(RECREATE_MATERIAZE_VIEW)] = true
 			}
+			if cfg.CfgCreator.DropAbsentConstraints != nil {
+				db.Cfg[string(DROP_ABSENT_CONSTRAINTS)] = true
+			}
+			if cfg.CfgCreator.DropAbsentTriggers != nil {
+				db.Cfg[string(DROP_ABSENT_TRIGGERS)] = true
+			}
 		}
 		if cfg.PathCfg != nil {
 
@@ -106,22 +131,36 @@ func NewDB(ctx context.Context, conn Connection) (*DB, error) {
 func (db *DB) readCfg(ctx context.Context, cfg *CfgDB) error {
 	var (
 		migrationOrder = []string{
-			"roles", "types", "table", "view", "func",
+			migrationRoles, migrationTypes, migrationTable, migrationView, migrationFunc, migrationTrigger,
 		}
 	)
 
 	migrationParts := map[string]fs.WalkDirFunc{
-		"roles": db.readAndReplaceRoles,
-		"types": db.readAndReplaceTypes,
-		"table": db.ReadTableSQL,
-		"view":  db.ReadViewSQL,
-		"func":  db.readAndReplaceFunc,
+		migrationRoles:   db.readAndReplaceRoles,
+		migrationTypes:   db.readAndReplaceTypes,
+		migrationTable:   db.ReadTableSQL,
+		migrationView:    db.ReadViewSQL,
+		migrationFunc:    db.readAndReplaceFunc,
+		migrationTrigger: db.readTriggerSQL,
+	}
+
+	migrations, err := readMigrations(*cfg.PathCfg, migrationOrder)
+	if err != nil {
+		return err
+	}
+
+	db.tableTriggers = tableTriggers(migrations)
+
+	// migrations aren't performed if they have cyclic dependencies
+	migrations, err = sortMigrations(migrations)
+	if err != nil {
+		return err
 	}
 
-	for _, name := range migrationOrder {
-		err := filepath.WalkDir(filepath.Join(*cfg.PathCfg, name), migrationParts[name])
+	for _, m := range migrations {
+		err := migrationParts[m.phase](m.path, nil, nil)
 		if err != nil {
-			return errors.Wrap(err, "migration "+name)
+			return errors.Wrap(err, "migration "+m.phase)
 		}
 	}
 
@@ -130,7 +169,6 @@ func (db *DB) readCfg(ctx context.Context, cfg *CfgDB) error {
 		logInfo(preDB_CONFIG, "Modify func on DB", strings.Join(db.FuncsReplaced, ","), len(db.FuncsReplaced))
 	}
 
-	var err error
 	_, db.Tables, db.Routines, db.Types, err = db.Conn.GetSchema(ctx, cfg)
 	if err != nil {
 		return err
@@ -181,16 +219,8 @@ func (db *DB) syncTableDDL(path string, tType string) error {
 			return NewParserCfgDDL(db, table).Parse(ddl)
 		}
 
-		//check tables dependencies
-		lower := strings.ToLower(ddl)
-		if submatch := regForeignIndex.FindStringSubmatch(lower); len(submatch) > 0 {
-			parent := submatch[regForeignIndex.SubexpIndex("fTable")]
-			if _, ok := db.Tables[parent]; !ok {
-				db.addRelationTable(path, parent)
-				logWarning("TABLES", path, "wait for parent:"+parent, 0)
-				return nil
-			}
-		}
+		// parents of table are created before according to order of migrations,
+		// createTable waits for ones which are absent
 		return db.createTable(path, ddl, tableName, tType)
 
 	default:
@@ -198,6 +228,37 @@ func (db *DB) syncTableDDL(path string, tType string) error {
 	}
 }
 
+// readTriggerSQL syncs triggers of table with DDL file, which has name of table,
+// triggers of table which neither the file nor DDL file of table declares are dropped if setting DropAbsentTriggers is on
+func (db *DB) readTriggerSQL(path string, info os.DirEntry, err error) error {
+	if (err != nil) || ((info != nil) && info.IsDir()) {
+		return nil
+	}
+
+	ext := filepath.Ext(path)
+	if ext != ".ddl" {
+		return nil
+	}
+
+	tableName := strings.TrimSuffix(filepath.Base(path), ext)
+	table, ok := db.Tables[tableName]
+	if !ok {
+		logError(NewErrNotFoundTable(tableName), "", filepath.Base(path))
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	p := NewParserCfgDDL(db, table)
+	p.manageTriggers = true
+	p.declaredTriggers = slices.Clone(db.tableTriggers[tableName])
+
+	return p.Parse(gotools.BytesToString(b))
+}
+
 func (db *DB) createTable(path, ddl, tableName, tType string) error {
 
 	fileName := filepath.Base(path)
@@ -240,6 +301,21 @@ func (db *DB) createTable(path, ddl, tableName, tType string) error {
 	return nil
 }
 
+// ReferencingTables return sorted names of tables which have foreign keys referenced to table 'name'
+func (db *DB) ReferencingTables(name string) []string {
+	res := make([]string, 0)
+	for tableName, table := range db.Tables {
+		if slices.ContainsFunc(table.ForeignKeys(), func(fk *ForeignKey) bool {
+			return fk.Parent == name
+		}) {
+			res = append(res, tableName)
+		}
+	}
+	slices.Sort(res)
+
+	return res
+}
+
 func (db *DB) addRelationTable(path string, tableName string) {
 	if val, ok := db.relationTables[tableName]; ok {
 		db.relationTables[tableName] = append(val, path)
@@ -249,6 +325,17 @@ func (db *DB) addRelationTable(path string, tableName string) {
 	logs.DebugLog(db.relationTables)
 }
 
+// RefreshMaterializedView refreshes data of materialized view 'name',
+// concurrent refresh doesn't lock selects of view but needs unique index on it
+func (db *DB) RefreshMaterializedView(ctx context.Context, name string, concurrently bool) error {
+	sql := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		sql += "CONCURRENTLY "
+	}
+
+	return errors.Wrap(db.Conn.ExecDDL(ctx, sql+name), "refresh "+name)
+}
+
 // ReadViewSQL performs ddl script for view
 func (db *DB) ReadViewSQL(path string, info os.DirEntry, err error) error {
 	if (err != nil) || ((info != nil) && info.IsDir()) {
@@ -300,7 +387,7 @@ func (db *DB) readAndReplaceTypes(path string, info os.DirEntry, err error) erro
 		fileName := filepath.Base(path)
 		typeName := strings.ToLower(strings.TrimSuffix(fileName, ext))
 		if t, ok := db.Types[typeName]; ok {
-			return db.alterType(&t, fileName, typeName, strings.Replace(ddlType, "\n", "", -1))
+			return db.alterType(&t, fileName, typeName, ddlType)
 		}
 
 		// this local err - not return for parent method
@@ -317,7 +404,7 @@ func (db *DB) readAndReplaceTypes(path string, info os.DirEntry, err error) erro
 			}
 
 		case IsErrorAlreadyExists(err):
-			return db.alterType(nil, fileName, typeName, strings.ToLower(strings.Replace(ddlType, "\n", "", -1)))
+			return db.alterType(nil, fileName, typeName, ddlType)
 		case IsErrorForReplace(err):
 			logError(err, ddlType, fileName)
 		case err != nil:
@@ -331,11 +418,6 @@ func (db *DB) readAndReplaceTypes(path string, info os.DirEntry, err error) erro
 	}
 }
 
-var regTypeAttr = regexp.MustCompile(`(?i)create\s+type\s+\w+\s+as\s*\((?P<builderOpts>(\s*\w+\s+\w*\s*[\w\[\]()]*,?)+)\s*\);`)
-var regTypeEnum = regexp.MustCompile(`(?i)create\s+type\s+\w+\s+as\s*enum\s*\(\s*(?P<builderOpts>('[^,)]+',?\s*)+)\s*\);`)
-
-// var regFieldAttr = regexp.MustCompile(`(\w+)\s+([\w()\[\]\s]+)`)
-
 func (db *DB) alterType(t *Types, fileName, typeName, ddl string) error {
 
 	if t == nil {
@@ -343,10 +425,23 @@ func (db *DB) alterType(t *Types, fileName, typeName, ddl string) error {
 		return nil
 	}
 
-	if fields := regTypeAttr.FindStringSubmatch(ddl); len(fields) > 0 {
-		return db.alterCompositeType(t, fileName, typeName, fields)
-	} else if enumerates := regTypeEnum.FindStringSubmatch(ddl); len(enumerates) > 0 {
-		return db.alterEnumType(t, fileName, typeName, enumerates)
+	list, err := ParseDDL(ddl)
+	if err != nil {
+		if e, ok := err.(*ErrDDLSyntax); ok {
+			e.File = fileName
+		}
+		logError(err, ddl, fileName)
+		return nil
+	}
+
+	for _, stmt := range list {
+		if s, ok := stmt.(*CreateTypeStmt); ok {
+			if len(s.Attributes) > 0 {
+				return db.alterCompositeType(t, fileName, typeName, s.Attributes)
+			} else if len(s.Enumerates) > 0 {
+				return db.alterEnumType(t, fileName, typeName, s.Enumerates)
+			}
+		}
 	}
 
 	return nil
@@ -357,153 +452,95 @@ const addEnumAfter = ` ADD VALUE %s AFTER '%s'`
 
 func (db *DB) alterEnumType(t *Types, fileName, typeName string, enumerates []string) error {
 	ddlType := "alter type " + typeName
-	for i, name := range regTypeAttr.SubexpNames() {
-		if name == "builderOpts" && (i < len(enumerates)) {
-			nameFields := strings.Split(enumerates[i], ",")
-			offset := 0
-			for ord, enum := range nameFields {
-				name := strings.ReplaceAll(strings.TrimSpace(enum), "''", "'")
-				if slices.Index(t.Enumerates, strings.Trim(name, "'")) < 0 {
-					if ord == 0 {
-						ddlAddAttr := ddlType + fmt.Sprintf(addEnumBefore, name, t.Enumerates[0])
-						if err := db.Conn.ExecDDL(db.ctx, ddlAddAttr); err != nil {
-							return err
-						}
-						logInfo(preDB_CONFIG, fileName, ddlAddAttr, 1)
-					} else if ord-offset < len(t.Enumerates) {
-						logs.StatusLog(ddlType+fmt.Sprintf(addEnumAfter, name, t.Enumerates[ord-offset-1]), ord-offset, len(t.Enumerates))
-					} else {
-						logs.StatusLog(ddlType+fmt.Sprintf(addEnumAfter, name, t.Enumerates[len(t.Enumerates)-1]), ord+offset, len(t.Enumerates))
-					}
-					offset++
+	offset := 0
+	for ord, enum := range enumerates {
+		if slices.Index(t.Enumerates, enum) < 0 {
+			name := "'" + strings.ReplaceAll(enum, "'", "''") + "'"
+			if ord == 0 {
+				ddlAddAttr := ddlType + fmt.Sprintf(addEnumBefore, name, t.Enumerates[0])
+				if err := db.Conn.ExecDDL(db.ctx, ddlAddAttr); err != nil {
+					return err
 				}
+				logInfo(preDB_CONFIG, fileName, ddlAddAttr, 1)
+			} else if ord-offset < len(t.Enumerates) {
+				logs.StatusLog(ddlType+fmt.Sprintf(addEnumAfter, name, t.Enumerates[ord-offset-1]), ord-offset, len(t.Enumerates))
+			} else {
+				logs.StatusLog(ddlType+fmt.Sprintf(addEnumAfter, name, t.Enumerates[len(t.Enumerates)-1]), ord+offset, len(t.Enumerates))
 			}
+			offset++
 		}
 	}
 	return nil
 }
 
-func (db *DB) alterCompositeType(t *Types, fileName, typeName string, fields []string) error {
+func (db *DB) alterCompositeType(t *Types, fileName, typeName string, attrs []ColumnDDL) error {
 	ddlType := "alter type " + typeName
-	for i, name := range regTypeAttr.SubexpNames() {
-		if name == "builderOpts" && (i < len(fields)) {
-			nameFields := strings.Split(fields[i], ",")
-			for _, name := range nameFields {
-				p := strings.Split(strings.TrimSpace(name), " ")
-				//todo aad "Name of wrong" performing
-				attrName := strings.ToLower(strings.TrimSpace(p[0]))
-				if len(p) < 2 {
-					return ErrWrongType{
-						Name:     name,
-						TypeName: typeName,
-						Attr:     attrName,
-					}
-				}
-
-				i := slices.IndexFunc(t.Attr, func(attr TypesAttr) bool {
-					return attr.Name == attrName
-				})
-				newType := strings.TrimSpace(strings.Join(p[1:], " "))
-				if i == -1 {
-					ddlAddAttr := ddlType + " add attribute " + name
-					err := db.Conn.ExecDDL(db.ctx, ddlAddAttr)
-					if err == nil {
-						logInfo(preDB_CONFIG, fileName, ddlAddAttr, 1)
-					} else if IsErrorAlreadyExists(err) {
-						logs.ErrorLog(err)
-					}
-					continue
-				}
-				chkAttr := t.Attr[i].CheckAttr(newType)
-				if len(chkAttr) == 0 {
-					continue
-				}
-
-				ddlAlter := ddlType + " alter attribute " + attrName
-				for i, flag := range chkAttr {
-					logs.StatusLog("%d. %s", i, flag)
-					switch flag {
-					case MustNotNull:
-						ddlAlter += " SET NOT NULL "
-					case Nullable:
-						ddlAlter += " DROP NOT NULL "
-					case ChgType:
-						ddlAlter += " SET DATA TYPE " + newType
-					case ChgLength:
-						ddlAlter += " SET DATA TYPE " + newType
-					case ChgToArray:
-					default:
-						logs.ErrorLog(fmt.Errorf("unhandled default case: %v", flag), fileName)
-					}
-				}
-				err := db.Conn.ExecDDL(db.ctx, ddlAlter)
-				if err != nil {
-					logs.ErrorLog(err, ddlAlter)
-					return err
-				}
-				logInfo(preDB_CONFIG, fileName, ddlAlter, 1)
-
-				logs.StatusLog(t.Attr[i], t.Attr[i].Column.Type(), attrName, newType)
+	for _, attr := range attrs {
+		attrName := attr.Name
+		i := slices.IndexFunc(t.Attr, func(attr TypesAttr) bool {
+			return attr.Name == attrName
+		})
+		newType := attr.Define
+		if i == -1 {
+			ddlAddAttr := ddlType + " add attribute " + attrName + " " + newType
+			err := db.Conn.ExecDDL(db.ctx, ddlAddAttr)
+			if err == nil {
+				logInfo(preDB_CONFIG, fileName, ddlAddAttr, 1)
+			} else if IsErrorAlreadyExists(err) {
+				logs.ErrorLog(err)
+			}
+			continue
+		}
+		chkAttr := t.Attr[i].CheckAttr(newType)
+		if len(chkAttr) == 0 {
+			continue
+		}
 
+		ddlAlter := ddlType + " alter attribute " + attrName
+		for i, flag := range chkAttr {
+			logs.StatusLog("%d. %s", i, flag)
+			switch flag {
+			case MustNotNull:
+				ddlAlter += " SET NOT NULL "
+			case Nullable:
+				ddlAlter += " DROP NOT NULL "
+			case ChgType:
+				ddlAlter += " SET DATA TYPE " + newType
+			case ChgLength:
+				ddlAlter += " SET DATA TYPE " + newType
+			case ChgToArray:
+			default:
+				logs.ErrorLog(fmt.Errorf("unhandled default case: %v", flag), fileName)
 			}
 		}
+		err := db.Conn.ExecDDL(db.ctx, ddlAlter)
+		if err != nil {
+			logs.ErrorLog(err, ddlAlter)
+			return err
+		}
+		logInfo(preDB_CONFIG, fileName, ddlAlter, 1)
+
+		logs.StatusLog(t.Attr[i], t.Attr[i].Column.Type(), attrName, newType)
 	}
 	return nil
 }
 
-var (
-	regRoutineTitle = regexp.MustCompile(`(function|procedure)\s+\w+\s*\(([^()]+(\(\d+\))?)*\)`)
-	regRoutineDef   = regexp.MustCompile(`\sdefault\s+[^,)]+`)
-)
-
-// todo split first row to get procedure title
+// readAndReplaceFunc performs file of routines, file may consist of several routines & other statements
 func (db *DB) readAndReplaceFunc(path string, info os.DirEntry, err error) error {
 	if (err != nil) || ((info != nil) && info.IsDir()) {
 		return nil
 	}
 
-	ext := filepath.Ext(path)
-	switch ext {
-	case ".ddl":
-		fileName := filepath.Base(path)
-		funcName := strings.TrimSuffix(fileName, ext)
+	switch filepath.Ext(path) {
+	case ".ddl", ".sql":
 		ddl, err := os.ReadFile(path)
 		if err != nil {
 			return err
 		}
 
-		ddlSQL := string(ddl)
-		// this local err - not return for parent method
-		err = db.Conn.ExecDDL(context.TODO(), ddlSQL)
-		if err == nil {
-			db.FuncsAdded = append(db.FuncsAdded, funcName)
-		} else if IsErrorAlreadyExists(err) {
-			err = nil
-		} else if IsErrorForReplace(err) {
-			err = nil
-			for _, funcName := range regRoutineTitle.FindAllString(strings.ToLower(ddlSQL), -1) {
-				dropSQL := "DROP " + regRoutineDef.ReplaceAllString(funcName, "")
-				logs.DebugLog(dropSQL)
-				err = db.Conn.ExecDDL(db.ctx, dropSQL)
-				if err != nil {
-					break
-				}
-			}
+		db.syncRoutinesDDL(filepath.Base(path), gotools.BytesToString(ddl))
 
-			if err == nil {
-				err = db.Conn.ExecDDL(db.ctx, ddlSQL)
-				if err == nil {
-					db.FuncsReplaced = append(db.FuncsReplaced, funcName)
-				}
-			}
-		}
-
-		if err != nil {
-			logError(err, ddlSQL, fileName)
-			err = nil
-		}
-
-		return err
+		return nil
 
 	default:
 		return nil
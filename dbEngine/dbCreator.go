@@ -32,81 +32,80 @@ func (p *ParserCfgDDL) runDDL(ddl string, args ...any) {
 	}
 }
 
+// checkDDLCreateIndex return index of statement 'create index' or foreign key of statement 'alter table',
+// ddl may consist of several statements, the first of such ones is parsed
 func (p *ParserCfgDDL) checkDDLCreateIndex(ddl string) (*Index, error) {
+	for _, stmt := range p.statements(ddl) {
+		switch s := stmt.(type) {
+		case *CreateIndexStmt:
+			if s.Table != p.Name() {
+				return nil, errors.Errorf("bad table name '%s'! %s", s.Table, ddl)
+			}
 
-	regIndex := regIndex
-	lower := strings.ToLower(ddl)
-	columns := regIndex.FindStringSubmatch(lower)
-	if len(columns) == 0 {
-		columns = regForeignIndex.FindStringSubmatch(lower)
-		if len(columns) == 0 {
-			return nil, nil
-		}
-		regIndex = regForeignIndex
-	}
-
-	var ind Index
-	for i, name := range regIndex.SubexpNames() {
+			ind := &Index{Name: s.Name, Unique: s.Unique, Method: s.Method}
 
-		if !(i < len(columns)) {
-			return nil, errors.Errorf("out if columns '%s'!", name)
-		}
+			return ind, p.indexItems(ind, s.Items)
 
-		switch token := columns[i]; name {
-		case "":
-		case "fTable":
-			ind.foreignTable = token
-		case "onUpdate":
-			ind.updateCascade = token
-		case "onDelete":
-			ind.deleteCascade = token
-		case "index":
-			ind.Name = token
-		case "unique":
-			ind.Unique = token == name
-		case "where":
-			ind.Where = token
-
-		case "table":
-			if token != p.Name() {
-				return nil, errors.Errorf("bad table name '%s'! %s", token, ddl)
+		case *AlterStmt:
+			if s.Object != "table" || len(s.Actions) != 1 || s.Actions[0].ForeignKey == nil {
+				continue
 			}
 
-		case "expr":
-			expr := make([]string, 0)
-			for _, name := range regExprSeparator.FindAllString(token, -1) {
-				expr = append(expr, regColumn.FindAllString(name, -1)...)
-				if col := p.FindColumn(name); col != nil {
-					ind.AddColumn(name)
-				} else if ind.Expr > "" {
-					ind.Expr += ", " + name
-				} else {
-					ind.Expr += name
-				}
+			if s.Name != p.Name() {
+				return nil, errors.Errorf("bad table name '%s'! %s", s.Name, ddl)
 			}
 
-			if len(ind.Columns) == 0 {
-				if len(expr) > 0 {
-					//we must add ONLY first column
-					name := expr[0]
-					if isColFunc(name) {
-						name = expr[1]
-					}
-					if col := p.FindColumn(name); col != nil {
-						ind.AddColumn(col.Name())
-					}
-				} else if ind.Expr == "" {
-					logs.StatusLog(token)
-					return nil, ErrNotFoundColumn{p.Name(), token}
-				}
+			fk := s.Actions[0].ForeignKey
+			ind := &Index{
+				Name:          fk.Name,
+				foreignTable:  fk.Parent,
+				foreignColumn: strings.Join(fk.ParentColumns, ","),
+				updateCascade: strings.ToLower(fk.UpdateRule),
+				deleteCascade: strings.ToLower(fk.DeleteRule),
 			}
 
-		default:
-			logInfo(preDB_CONFIG, p.filename, name+token, p.line)
+			return ind, p.indexItems(ind, fk.Columns)
+		}
+	}
+
+	return nil, nil
+}
+
+// indexItems adds items of index as columns of table or expression
+func (p *ParserCfgDDL) indexItems(ind *Index, items []string) error {
+	// items of expression may be columns or expressions with nested functions
+	for _, item := range items {
+		name := strings.ToLower(item)
+		if col := p.FindColumn(columnNameDDL(item)); col != nil {
+			ind.AddColumn(col.Name())
+		} else if ind.Expr > "" {
+			ind.Expr += ", " + name
+		} else {
+			ind.Expr += name
 		}
 	}
 
-	return &ind, nil
+	if len(ind.Columns) > 0 {
+		return nil
+	}
+
+	if ind.Expr == "" {
+		logs.StatusLog(items)
+		return ErrNotFoundColumn{p.Name(), strings.Join(items, ",")}
+	}
+
+	//we must add ONLY first column
+	if expr := regColumn.FindAllString(ind.Expr, -1); len(expr) > 0 {
+		name := expr[0]
+		if isColFunc(name) && len(expr) > 1 {
+			name = expr[1]
+		}
+		if col := p.FindColumn(name); col != nil {
+			ind.AddColumn(col.Name())
+		}
+	}
+
+	return nil
 }
 
 func isColFunc(name string) bool {
@@ -122,6 +121,14 @@ type ParserCfgDDL struct {
 	line       int
 	parseOrder []func(string) bool
 	updDLL     *strings.Builder
+	// AST of statement which is performed now
+	stmt StatementAST
+	// constraints of table which aren't declared in DDL, they are dropped at the end of parsing if setting allows it
+	absentConstraints []string
+	// names of triggers declared in DDL
+	declaredTriggers []string
+	// if it's set, triggers of table which aren't declared in DDL are dropped at the end of parsing if setting allows it
+	manageTriggers bool
 }
 
 // NewParserCfgDDL create new instance of ParserCfgDDL
@@ -139,36 +146,93 @@ func NewParserCfgDDL(db *DB, table Table) *ParserCfgDDL {
 		t.alterTable,
 		t.alterMaterializedView,
 		t.performsGrants,
+		t.updateTrigger,
 	}
 
 	return t
 }
 
-// Parse perform queries from ddl text
+// Parse perform queries from ddl text, statements with syntax errors are skipped
 func (p *ParserCfgDDL) Parse(ddl string) error {
 	p.line = 1
-	for _, sql := range strings.Split(ddl, ";") {
-
-		next_line := p.line + strings.Count(sql, "\n")
-		sql = strings.TrimSpace(strings.TrimPrefix(sql, "\n"))
-		if !(sql == "" || strings.TrimSpace(strings.Replace(sql, "\n", "", -1)) == "" ||
-			strings.HasPrefix(sql, "--")) {
-			if err := p.execSql(sql); err != nil {
-				logError(err, ddl, p.filename)
-			}
+	// triggers aren't dropped if some statement is skipped, it may declare one of them
+	dropTriggers := p.manageTriggers
+	statements, err := SplitStatementsDDL(ddl)
+	if err != nil {
+		p.logSyntaxError(err, ddl)
+		dropTriggers = false
+	}
 
-			if p.err != nil {
-				logError(p.err, ddl, p.filename)
-			}
+	for _, source := range statements {
+		p.line = source.Pos.Line
+		stmt, err := ParseStatementDDL(source)
+		if err != nil {
+			p.logSyntaxError(err, ddl)
+			dropTriggers = false
+			continue
+		}
+
+		p.stmt = stmt
+		if err := p.execSql(source.Text); err != nil {
+			logError(err, ddl, p.filename)
+		}
 
-			p.err = nil
+		if p.err != nil {
+			logError(p.err, ddl, p.filename)
 		}
-		p.line = next_line
+
+		p.err = nil
+	}
+	p.stmt = nil
+
+	p.dropAbsentConstraints()
+	if dropTriggers {
+		p.dropAbsentTriggers()
 	}
+	p.declaredTriggers = nil
 
 	return nil
 }
 
+// cfgOn return true if setting of DB is on
+func (p *ParserCfgDDL) cfgOn(name TypeCfgDB) bool {
+	if p.DB == nil {
+		return false
+	}
+
+	on, _ := p.DB.Cfg[string(name)].(bool)
+
+	return on
+}
+
+func (p *ParserCfgDDL) logSyntaxError(err error, ddl string) {
+	if e, ok := err.(*ErrDDLSyntax); ok {
+		e.File = p.filename
+	}
+
+	logError(err, ddl, p.filename)
+}
+
+// statement return AST of ddl, statement performed by Parse is parsed already
+func (p *ParserCfgDDL) statement(ddl string) StatementAST {
+	if list := p.statements(ddl); len(list) > 0 {
+		return list[0]
+	}
+
+	return nil
+}
+
+// statements return ASTs of ddl before first syntax error
+func (p *ParserCfgDDL) statements(ddl string) []StatementAST {
+	if p.stmt != nil && p.stmt.Source().Text == ddl {
+		return []StatementAST{p.stmt}
+	}
+
+	list, _ := ParseDDL(ddl)
+
+	return list
+}
+
 func (p *ParserCfgDDL) execSql(sql string) error {
 	for _, fnc := range p.parseOrder {
 		if fnc(sql) {
@@ -180,7 +244,7 @@ func (p *ParserCfgDDL) execSql(sql string) error {
 }
 
 func (p *ParserCfgDDL) performsCreateExt(ddl string) bool {
-	if !strings.HasPrefix(strings.ToLower(ddl), "create extension") {
+	if s, ok := p.statement(ddl).(*RawStmt); !ok || !s.Command("create", "extension") {
 		return false
 	}
 
@@ -189,25 +253,61 @@ func (p *ParserCfgDDL) performsCreateExt(ddl string) bool {
 	return true
 }
 
+// alterMaterializedView recreates materialized view if its definition is changed (or setting RECREATE_MATERIAZE_VIEW is on),
+// indexes & grants of view are restored after recreating
 func (p *ParserCfgDDL) alterMaterializedView(ddl string) bool {
-	if !strings.HasPrefix(strings.ToLower(ddl), "create materialized view") {
+	s, ok := p.statement(ddl).(*CreateViewStmt)
+	if !ok || !s.Materialized {
 		return false
 	}
 
-	if t, ok := p.DB.Cfg[string(RECREATE_MATERIAZE_VIEW)].(bool); ok && t {
-		p.runDDL("DROP materialized view " + p.Table.Name())
+	if s.Name != p.Name() {
+		p.err = errors.Errorf(errWrongTableName.Error(), s.Name, "materialized view")
+		return false
 	}
-	p.runDDL(ddl)
+
+	recreate, _ := p.DB.Cfg[string(RECREATE_MATERIAZE_VIEW)].(bool)
+	view, ok := p.Table.(MaterializedView)
+	if !ok {
+		if recreate {
+			p.runDDL("DROP materialized view " + p.Name())
+		}
+		p.runDDL(ddl)
+
+		return true
+	}
+
+	if !recreate {
+		changed, err := view.DefinitionChanged(p.DB.ctx, s.Columns, s.Query)
+		if err != nil {
+			p.err = err
+			return true
+		}
+
+		if !changed {
+			return true
+		}
+	}
+
+	dependents, err := view.DependentDDL(p.DB.ctx)
+	if err != nil {
+		p.err = err
+		return true
+	}
+
+	// statements run in one transaction, so DB keeps old view if other views depend on it or new DDL is wrong
+	p.runDDL(strings.Join(append([]string{"DROP MATERIALIZED VIEW " + p.Name(), ddl}, dependents...), ";\n"))
 
 	return true
 }
 
 func (p *ParserCfgDDL) performsInsert(ddl string) bool {
-	if !strings.Contains(strings.ToLower(ddl), "insert") {
+	s, ok := p.statement(ddl).(*InsertStmt)
+	if !ok {
 		return false
 	}
 
-	if !strings.Contains(strings.ToLower(ddl), "on conflict") {
+	if !s.OnConflict {
 		ddl += " ON CONFLICT  DO NOTHING "
 	}
 
@@ -217,7 +317,7 @@ func (p *ParserCfgDDL) performsInsert(ddl string) bool {
 }
 
 func (p *ParserCfgDDL) performsUpdate(ddl string) bool {
-	if !strings.Contains(strings.ToLower(ddl), "update") {
+	if s, ok := p.statement(ddl).(*RawStmt); !ok || !s.Command("update") {
 		return false
 	}
 
@@ -227,7 +327,7 @@ func (p *ParserCfgDDL) performsUpdate(ddl string) bool {
 }
 
 func (p *ParserCfgDDL) performsGrants(ddl string) bool {
-	if !strings.HasPrefix(strings.ToLower(ddl), "grant") {
+	if _, ok := p.statement(ddl).(*GrantStmt); !ok {
 		return false
 	}
 
@@ -237,42 +337,29 @@ func (p *ParserCfgDDL) performsGrants(ddl string) bool {
 }
 
 func (p *ParserCfgDDL) updateView(ddl string) bool {
-	fields := regView.FindStringSubmatch(strings.ToLower(ddl))
-	if len(fields) == 0 {
+	s, ok := p.statement(ddl).(*CreateViewStmt)
+	if !ok || s.Materialized {
 		return false
 	}
 
-	for i, name := range regView.SubexpNames() {
-		if !(i < len(fields)) {
-			return false
-		}
-
-		switch name {
-		case "":
-		case "name":
-			if fields[i] != p.Name() {
-				p.err = errors.Errorf(errWrongTableName.Error(), fields[i])
-				return false
-			}
+	if s.Name != p.Name() {
+		p.err = errors.Errorf(errWrongTableName.Error(), s.Name, "view")
+		return false
+	}
 
-			err := p.DB.Conn.ExecDDL(p.DB.ctx, ddl)
-			if err != nil {
-				if IsErrorCntChgView(err) {
-					err = p.DB.Conn.ExecDDL(p.DB.ctx, "DROP VIEW "+p.Name()+" CASCADE")
-					if err == nil {
-						err = p.DB.Conn.ExecDDL(p.DB.ctx, ddl)
-					}
-				}
-
-				if err != nil {
-					p.err = err
-				}
+	err := p.DB.Conn.ExecDDL(p.DB.ctx, ddl)
+	if err != nil {
+		if IsErrorCntChgView(err) {
+			err = p.DB.Conn.ExecDDL(p.DB.ctx, "DROP VIEW "+p.Name()+" CASCADE")
+			if err == nil {
+				err = p.DB.Conn.ExecDDL(p.DB.ctx, ddl)
 			}
+		}
 
-			return true
+		if err != nil {
+			p.err = err
 		}
 	}
 
-	return false
-
+	return true
 }
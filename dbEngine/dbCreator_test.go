@@ -5,11 +5,16 @@
 package dbEngine
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 func TestNewParserTableDDL(t *testing.T) {
@@ -241,12 +246,46 @@ func TestParserTableDDL_checkPrimary(t *testing.T) {
 		colDefine string
 		flags     []FlagColumn
 	}
+	table := TableString{name: "candidates"}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
+		name        string
+		fields      fields
+		args        args
+		want        string
+		wantDefault string
 	}{
-		// TODO: Add test cases.
+		{
+			name:   "add identity",
+			fields: fields{Table: table},
+			args: args{
+				col:       &identityColumn{NumberColumn: NewNumberColumn("id", "", true)},
+				colDefine: "integer generated always as identity",
+				flags:     []FlagColumn{ChgIdentity},
+			},
+			want: "ALTER TABLE candidates  ALTER COLUMN id DROP DEFAULT, ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY",
+			wantDefault: ";\nSELECT setval(pg_get_serial_sequence('candidates', 'id'), coalesce(max(id), 0) + 1, false) " +
+				"FROM candidates",
+		},
+		{
+			name:   "change identity",
+			fields: fields{Table: table},
+			args: args{
+				col:       &identityColumn{NumberColumn: NewNumberColumn("id", "", true), identity: IdentityAlways},
+				colDefine: "integer generated by  default as identity",
+				flags:     []FlagColumn{ChgIdentity},
+			},
+			want: "ALTER TABLE candidates  ALTER COLUMN id SET GENERATED BY DEFAULT",
+		},
+		{
+			name:   "drop identity & change type",
+			fields: fields{Table: table},
+			args: args{
+				col:       &identityColumn{NumberColumn: NewNumberColumn("id", "", true), identity: IdentityAlways},
+				colDefine: "bigint",
+				flags:     []FlagColumn{ChgType, ChgIdentity},
+			},
+			want: "ALTER TABLE candidates  ALTER COLUMN id TYPE bigint USING id::bigint, ALTER COLUMN id DROP IDENTITY IF EXISTS",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -258,11 +297,45 @@ func TestParserTableDDL_checkPrimary(t *testing.T) {
 				line:       tt.fields.line,
 				parseOrder: tt.fields.mapParse,
 			}
-			p.checkPrimary(tt.args.col, tt.args.colDefine, tt.args.flags)
+			sqlDefaults := &strings.Builder{}
+			p.checkPrimary(tt.args.col, tt.args.colDefine, tt.args.flags, sqlDefaults)
+			assert.Equal(t, tt.want, p.updDLL.String())
+			assert.Equal(t, tt.wantDefault, sqlDefaults.String())
 		})
 	}
 }
 
+// identityColumn is column with identity for tests
+type identityColumn struct {
+	*NumberColumn
+	identity, expr string
+}
+
+func (c *identityColumn) Identity() string {
+	return c.identity
+}
+
+func (c *identityColumn) GenerationExpression() string {
+	return c.expr
+}
+
+func TestDefineIdentity(t *testing.T) {
+	tests := []struct {
+		colDefine    string
+		wantIdentity string
+		wantDefine   string
+	}{
+		{"integer generated always as identity primary key", IdentityAlways, "integer primary key"},
+		{"bigint GENERATED BY DEFAULT AS IDENTITY (start with 10) not null", IdentityByDefault, "bigint not null"},
+		{"integer default 0", "", "integer default 0"},
+	}
+	for _, tt := range tests {
+		identity, define := DefineIdentity(tt.colDefine)
+		assert.Equal(t, tt.wantIdentity, identity)
+		assert.Equal(t, tt.wantDefine, define)
+	}
+}
+
 func TestParserTableDDL_createIndex(t *testing.T) {
 	type fields struct {
 		Table        Table
@@ -418,6 +491,22 @@ on candidates (name);`,
 				Columns: []string{"year"},
 			},
 		},
+		{
+			name: "full text index with nested functions",
+			fields: fields{
+				Table: TableString{
+					name:    "articles",
+					columns: SimpleColumns("title", "body"),
+				},
+			},
+			ddl: `create index if not exists articles_fts
+    on articles using gin (to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(body, '')))`,
+			want: &Index{
+				Name:   "articles_fts",
+				Expr:   "to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(body, ''))",
+				Method: "gin",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -859,3 +948,470 @@ func TestParserTableDDL_updateView(t *testing.T) {
 func TestDB_ReadTableSQL(t *testing.T) {
 	assert.IsType(t, (TypeCfgDB)(""), DB_SETTING, "test")
 }
+
+func TestParseTableDDL(t *testing.T) {
+	name, columns, err := ParseTableDDL(strings.Split(testCandidate, ";")[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "candidates", name)
+	assert.Len(t, columns, 27)
+
+	assert.Equal(t, ColumnDDL{Name: "id", Type: "serial", Define: "serial not null", NotNull: true, Primary: true}, columns[0])
+	assert.Equal(t, "integer[]", columns[2].Type)
+	assert.Equal(t, "''", columns[5].Default)
+	assert.Equal(t, "timestamp with time zone", columns[14].Type)
+	assert.False(t, columns[21].NotNull)
+
+	_, columns, err = ParseTableDDL(`create table t (code varchar(10) unique, "Note" text default 'a, b', primary key (code))`)
+	assert.Nil(t, err)
+	assert.Equal(t, []ColumnDDL{
+		{Name: "code", Type: "varchar(10)", Define: "varchar(10) unique", NotNull: true, Primary: true, Unique: true},
+		{Name: "Note", Type: "text", Define: "text default 'a, b'", Default: "'a, b'"},
+	}, columns)
+
+	_, _, err = ParseTableDDL("create view t as select 1")
+	assert.NotNil(t, err)
+}
+
+// constraintsTable is table with constraints read from DB for tests
+type constraintsTable struct {
+	*TableString
+	constraints Constraints
+}
+
+func (t constraintsTable) Constraints() Constraints {
+	return t.constraints
+}
+
+func TestParserTableDDL_constraintsDiff(t *testing.T) {
+	const ddl = `create table prices (
+	id serial primary key,
+	shop varchar(20) not null,
+	sku varchar(20) not null,
+	price numeric check (price >= 0),
+	during tstzrange,
+	constraint prices_shop_sku_key unique (shop, sku),
+	constraint prices_discount_check check (price < 1000),
+	exclude using gist (shop with =, during with &&),
+	foreign key (shop) references shops
+)`
+	p := &ParserCfgDDL{Table: constraintsTable{
+		TableString: NewTableString("prices", "", nil, nil, nil),
+		constraints: Constraints{
+			{Name: "prices_pkey", Type: ConstraintPrimary, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+			{Name: "prices_price_check", Type: ConstraintCheck, Columns: []string{"price"},
+				Definition: "CHECK ((price >= (0)::numeric))"},
+			{Name: "prices_shop_sku_key", Type: ConstraintUnique, Columns: []string{"shop", "sku"},
+				Definition: "UNIQUE (shop, sku)"},
+			{Name: "prices_discount_check", Type: ConstraintCheck, Columns: []string{"price"},
+				Definition: "CHECK ((price < (500)::numeric))"},
+			{Name: "prices_sku_key", Type: ConstraintUnique, Columns: []string{"sku"}, Definition: "UNIQUE (sku)"},
+		},
+	}}
+
+	alters, absent := p.constraintsDiff(ddl)
+	assert.Equal(t, []string{
+		"ALTER TABLE prices DROP CONSTRAINT prices_discount_check, ADD CONSTRAINT prices_discount_check check (price < 1000)",
+		"ALTER TABLE prices ADD exclude using gist (shop with =, during with &&)",
+	}, alters)
+	assert.Equal(t, []string{"prices_sku_key"}, absent)
+}
+
+// checksTable is table which normalizes definitions of checks like DB for tests
+type checksTable struct {
+	constraintsTable
+	stored map[string]string
+}
+
+func (t checksTable) CheckDefinition(_ context.Context, definition string) (string, error) {
+	return t.stored[definition], nil
+}
+
+func TestParserTableDDL_constraintsDiff_rewrittenCheck(t *testing.T) {
+	const ddl = `create table items (
+	status varchar(10) check (status in ('a', 'b')),
+	qty int,
+	constraint items_qty_check check (qty between 1 and 10)
+)`
+	const (
+		statusDef = "CHECK (((status)::text = ANY ((ARRAY['a'::character varying, 'b'::character varying])::text[])))"
+		qtyDef    = "CHECK (((qty >= 1) AND (qty <= 10)))"
+	)
+	p := &ParserCfgDDL{
+		DB: &DB{ctx: context.Background()},
+		Table: checksTable{
+			constraintsTable: constraintsTable{
+				TableString: NewTableString("items", "", nil, nil, nil),
+				constraints: Constraints{
+					{Name: "items_status_check", Type: ConstraintCheck, Columns: []string{"status"}, Definition: statusDef},
+					{Name: "items_qty_check", Type: ConstraintCheck, Columns: []string{"qty"}, Definition: qtyDef},
+				},
+			},
+			stored: map[string]string{
+				"check (status in ('a', 'b'))": statusDef,
+				"check (qty between 1 and 10)": qtyDef,
+			},
+		},
+	}
+
+	alters, absent := p.constraintsDiff(ddl)
+	assert.Empty(t, alters)
+	assert.Empty(t, absent)
+}
+
+func TestParserTableDDL_dropAbsentConstraints(t *testing.T) {
+	const ddl = "create table orders (id serial primary key, code varchar(10))"
+	conn := &execConn{}
+	p := &ParserCfgDDL{
+		DB: &DB{Conn: conn, ctx: context.Background(), Cfg: map[string]any{}},
+		Table: constraintsTable{
+			TableString: NewTableString("orders", "", nil, nil, nil),
+			constraints: Constraints{
+				{Name: "orders_pkey", Type: ConstraintPrimary, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+				// added by 'alter table' of other migration
+				{Name: "orders_code_key", Type: ConstraintUnique, Columns: []string{"code"}, Definition: "UNIQUE (code)"},
+			},
+		},
+	}
+
+	for range 2 {
+		p.updateConstraints(ddl)
+		p.dropAbsentConstraints()
+	}
+	assert.Empty(t, conn.ddl)
+
+	p.DB.Cfg[string(DROP_ABSENT_CONSTRAINTS)] = true
+	p.updateConstraints(ddl)
+	p.dropAbsentConstraints()
+	assert.Equal(t, []string{"ALTER TABLE orders DROP CONSTRAINT orders_code_key"}, conn.ddl)
+}
+
+func TestConstraintsDDL(t *testing.T) {
+	declared, columns := constraintsDDL(`create table t (
+	code varchar(10) unique,
+	"Primary" text default 'primary key, unique',
+	amount int not null check (amount > 0),
+	constraint "T_code_check" check (length(code) > 2),
+	primary key (code, amount)
+)`)
+	assert.Equal(t, Constraints{
+		{Name: "T_code_check", Type: ConstraintCheck, Definition: "check (length(code) > 2)"},
+		{Type: ConstraintPrimary, Columns: []string{"code", "amount"}, Definition: "primary key (code, amount)"},
+	}, declared)
+	assert.Equal(t, Constraints{
+		{Type: ConstraintUnique, Columns: []string{"code"}},
+		{Type: ConstraintCheck, Columns: []string{"amount"}},
+	}, columns)
+}
+
+func TestDB_ReferencingTables(t *testing.T) {
+	id := NewUUIDColumn("id", "", true)
+	userID := NewUUIDColumn("user_id", "", true)
+	userID.SetForeign(&ForeignKey{Name: "orders_user_fk", Parent: "users", Column: "id"})
+	authorID := NewUUIDColumn("author_id", "", true)
+	authorID.SetForeign(&ForeignKey{Name: "posts_author_fk", Parent: "users", Column: "id"})
+
+	users := constraintsTable{
+		TableString: NewTableString("users", "", []Column{id}, nil, nil),
+		constraints: Constraints{
+			{Name: "users_pkey", Type: ConstraintPrimary, Columns: []string{"id"}, Definition: "PRIMARY KEY (id)"},
+			{Name: "users_name_key", Type: ConstraintUnique, Columns: []string{"name"}, Definition: "UNIQUE (name)"},
+		},
+	}
+	db := &DB{Tables: map[string]Table{
+		"users":  users,
+		"posts":  NewTableString("posts", "", []Column{authorID}, nil, nil),
+		"orders": NewTableString("orders", "", []Column{userID}, nil, nil),
+		"tags":   NewTableString("tags", "", []Column{NewStringColumn("name", "", true)}, nil, nil),
+	}}
+
+	assert.Equal(t, []string{"orders", "posts"}, db.ReferencingTables("users"))
+	assert.Empty(t, db.ReferencingTables("tags"))
+
+	p := &ParserCfgDDL{Table: users, DB: db}
+	fk, table := p.referencingKey("users_pkey")
+	assert.Equal(t, "orders_user_fk", fk.Name)
+	assert.Equal(t, "orders", table)
+
+	fk, _ = p.referencingKey("users_name_key")
+	assert.Nil(t, fk)
+}
+
+// execConn is connection which records executed DDL for tests
+type execConn struct {
+	Connection
+	ddl  []string
+	errs map[string]error
+}
+
+func (c *execConn) ExecDDL(_ context.Context, sql string, _ ...any) error {
+	c.ddl = append(c.ddl, sql)
+	return c.errs[sql]
+}
+
+func (c *execConn) LastRowAffected() int64 {
+	return 0
+}
+
+// matViewTable is materialized view with stored definition for tests
+type matViewTable struct {
+	*TableString
+	changed    bool
+	dependents []string
+}
+
+func (t matViewTable) DefinitionChanged(context.Context, []string, string) (bool, error) {
+	return t.changed, nil
+}
+
+func (t matViewTable) DependentDDL(context.Context) ([]string, error) {
+	return t.dependents, nil
+}
+
+func TestParserTableDDL_alterMaterializedView(t *testing.T) {
+	const ddl = "create materialized view orders_sum as select id, sum(total) from orders group by id"
+	view := matViewTable{
+		TableString: NewTableString("orders_sum", "", nil, nil, nil),
+		dependents: []string{
+			"CREATE UNIQUE INDEX orders_sum_id_uindex ON public.orders_sum USING btree (id)",
+			"GRANT SELECT ON orders_sum TO manager",
+		},
+	}
+	conn := &execConn{}
+	db := &DB{Conn: conn, ctx: context.Background(), Cfg: map[string]any{}}
+
+	assert.True(t, NewParserCfgDDL(db, view).alterMaterializedView(ddl))
+	assert.Empty(t, conn.ddl)
+
+	view.changed = true
+	assert.True(t, NewParserCfgDDL(db, view).alterMaterializedView(ddl))
+	assert.Equal(t, []string{"DROP MATERIALIZED VIEW orders_sum;\n" + ddl + ";\n" +
+		"CREATE UNIQUE INDEX orders_sum_id_uindex ON public.orders_sum USING btree (id);\n" +
+		"GRANT SELECT ON orders_sum TO manager"}, conn.ddl)
+
+	conn.ddl = nil
+	p := NewParserCfgDDL(db, NewTableString("orders_sum", "", nil, nil, nil))
+	assert.True(t, p.alterMaterializedView(ddl))
+	assert.Equal(t, []string{ddl}, conn.ddl)
+
+	assert.False(t, p.alterMaterializedView("create materialized view orders_total as select 1"))
+	assert.NotNil(t, p.err)
+	assert.False(t, p.alterMaterializedView("create view orders_sum as select 1"))
+}
+
+func TestDB_RefreshMaterializedView(t *testing.T) {
+	conn := &execConn{}
+	db := &DB{Conn: conn}
+
+	assert.Nil(t, db.RefreshMaterializedView(context.Background(), "orders_sum", false))
+	assert.Nil(t, db.RefreshMaterializedView(context.Background(), "orders_sum", true))
+	assert.Equal(t, []string{
+		"REFRESH MATERIALIZED VIEW orders_sum",
+		"REFRESH MATERIALIZED VIEW CONCURRENTLY orders_sum",
+	}, conn.ddl)
+}
+
+// defRoutine is routine with stored definition for tests
+type defRoutine struct {
+	Routine
+	signature  string
+	overlay    Routine
+	changed    bool
+	err        error
+	dependents []string
+}
+
+func (r *defRoutine) Overlay() Routine {
+	return r.overlay
+}
+
+func (r *defRoutine) IsSignature(_ context.Context, signature string) (bool, error) {
+	return r.signature == signature, nil
+}
+
+func (r *defRoutine) DefinitionChanged(context.Context, string) (bool, error) {
+	return r.changed, r.err
+}
+
+func (r *defRoutine) DependentDDL(context.Context) ([]string, error) {
+	return r.dependents, nil
+}
+
+func TestDB_syncRoutinesDDL(t *testing.T) {
+	const ddl = `create or replace function total(a int) returns int as $$ select a $$ language sql;
+create or replace function total(a int, b int) returns bigint as $$ select a + b $$ language sql;
+create or replace function total(a text) returns text as 'select a || ''!''' language sql;
+create function add_item(a int) returns int as 'select a' language sql;
+create function add_item(a int) returns int as 'select a + 1' language sql;
+comment on function add_item(int) is 'new'`
+	statements, err := SplitStatementsDDL(ddl)
+	require.NoError(t, err)
+	require.Len(t, statements, 6)
+
+	conn := &execConn{errs: map[string]error{
+		statements[4].Text: errors.New(`function "add_item" already exists with same argument types`),
+	}}
+	db := &DB{Conn: conn, ctx: context.Background(), Routines: map[string]Routine{
+		"total": &defRoutine{signature: "total(int)", overlay: &defRoutine{
+			signature:  "total(int, int)",
+			err:        errors.New("cannot change return type of existing function"),
+			dependents: []string{"GRANT EXECUTE ON FUNCTION total(integer,integer) TO manager"},
+			overlay:    &defRoutine{signature: "total(text)", changed: true},
+		}},
+	}}
+
+	db.syncRoutinesDDL("total.sql", ddl)
+	assert.Equal(t, []string{
+		"DROP function total(int, int);\n" + statements[1].Text + ";\n" +
+			"GRANT EXECUTE ON FUNCTION total(integer,integer) TO manager",
+		statements[2].Text,
+		statements[3].Text,
+		statements[4].Text,
+		statements[5].Text,
+	}, conn.ddl)
+	assert.Equal(t, []string{"add_item"}, db.FuncsAdded)
+	assert.Equal(t, []string{"total", "total"}, db.FuncsReplaced)
+}
+
+// finderConn is connection which finds routines by signature for tests
+type finderConn struct {
+	*execConn
+	routines map[string]RoutineDefinition
+}
+
+func (c finderConn) FindRoutine(_ context.Context, signature string) (RoutineDefinition, error) {
+	return c.routines[signature], nil
+}
+
+func TestDB_syncRoutinesDDL_finder(t *testing.T) {
+	const ddl = `create or replace function total(a int) returns bigint as $$ select a $$ language sql;
+create or replace function total(a text) returns text as $$ select a $$ language sql;
+create function add_item(a int) returns int as 'select a' language sql`
+	statements, err := SplitStatementsDDL(ddl)
+	require.NoError(t, err)
+
+	conn := finderConn{
+		execConn: &execConn{},
+		routines: map[string]RoutineDefinition{
+			"total(int)": &defRoutine{
+				err:        errors.New("cannot change return type of existing function"),
+				dependents: []string{"COMMENT ON FUNCTION total(integer) IS 'sum'"},
+			},
+			"total(text)": &defRoutine{},
+		},
+	}
+	// routines of schema aren't read, so map is empty
+	db := &DB{Conn: conn, ctx: context.Background(), Routines: map[string]Routine{}}
+
+	db.syncRoutinesDDL("total.sql", ddl)
+	assert.Equal(t, []string{
+		"DROP function total(int);\n" + statements[0].Text + ";\n" +
+			"COMMENT ON FUNCTION total(integer) IS 'sum'",
+		statements[2].Text,
+	}, conn.ddl)
+	assert.Equal(t, []string{"add_item"}, db.FuncsAdded)
+	assert.Equal(t, []string{"total"}, db.FuncsReplaced)
+}
+
+// triggersTable is table with triggers from DB for tests
+type triggersTable struct {
+	*TableString
+	triggers Triggers
+}
+
+func (t triggersTable) Triggers() Triggers {
+	return t.triggers
+}
+
+func TestParserTableDDL_updateTrigger(t *testing.T) {
+	const (
+		created  = "create trigger orders_created after insert on orders for each row execute function notify_orders('created')"
+		changed  = "create trigger orders_changed after update of total on orders for each row when (old.total <> new.total) execute function notify_orders()"
+		replaced = "create trigger orders_deleted before delete on orders for each row execute function check_orders()"
+	)
+	table := triggersTable{
+		TableString: NewTableString("orders", "", nil, nil, nil),
+		triggers: Triggers{
+			{
+				Name: "orders_changed",
+				Definition: "CREATE TRIGGER orders_changed AFTER UPDATE OF total ON public.orders FOR EACH ROW " +
+					"WHEN ((old.total <> new.total)) EXECUTE FUNCTION notify_orders()",
+			},
+			{
+				Name:       "orders_deleted",
+				Definition: "CREATE TRIGGER orders_deleted AFTER DELETE ON public.orders FOR EACH ROW EXECUTE FUNCTION check_orders()",
+			},
+			{
+				Name:       "orders_audit",
+				Definition: "CREATE TRIGGER orders_audit AFTER INSERT ON public.orders FOR EACH STATEMENT EXECUTE FUNCTION audit()",
+			},
+		},
+	}
+	conn := &execConn{}
+	db := &DB{Conn: conn, ctx: context.Background(), Cfg: map[string]any{}}
+
+	p := NewParserCfgDDL(db, table)
+	assert.NoError(t, p.Parse(created+";\n"+changed+";\n"+replaced))
+	assert.Equal(t, []string{
+		created,
+		"DROP TRIGGER orders_deleted ON orders;\n" + replaced,
+	}, conn.ddl)
+
+	// trigger isn't declared, but it's kept without setting
+	conn.ddl = nil
+	p.manageTriggers = true
+	assert.NoError(t, p.Parse(created+";\n"+changed+";\n"+replaced))
+	assert.Equal(t, []string{
+		created,
+		"DROP TRIGGER orders_deleted ON orders;\n" + replaced,
+	}, conn.ddl)
+
+	conn.ddl = nil
+	db.Cfg[string(DROP_ABSENT_TRIGGERS)] = true
+	assert.NoError(t, p.Parse(created+";\n"+changed+";\n"+replaced))
+	assert.Equal(t, []string{
+		created,
+		"DROP TRIGGER orders_deleted ON orders;\n" + replaced,
+		"DROP TRIGGER orders_audit ON orders",
+	}, conn.ddl)
+
+	// trigger isn't dropped if DDL has syntax error
+	conn.ddl = nil
+	assert.NoError(t, p.Parse(changed+";\ncreate trigger orders_audit after on orders"))
+	assert.Empty(t, conn.ddl)
+
+	assert.False(t, p.updateTrigger("create trigger t after insert on users execute function f()"))
+	assert.NotNil(t, p.err)
+	assert.False(t, p.updateTrigger("create index on orders (id)"))
+}
+
+func TestDB_readTriggerSQL(t *testing.T) {
+	const created = "create trigger orders_num before insert on orders for each row execute function num()"
+	path := filepath.Join(t.TempDir(), "orders.ddl")
+	require.NoError(t, os.WriteFile(path, []byte(created), 0o644))
+
+	conn := &execConn{}
+	db := &DB{
+		Conn: conn,
+		ctx:  context.Background(),
+		Cfg:  map[string]any{},
+		Tables: map[string]Table{
+			"orders": triggersTable{
+				TableString: NewTableString("orders", "", nil, nil, nil),
+				triggers: Triggers{
+					{Name: "orders_audit", Definition: "CREATE TRIGGER orders_audit AFTER INSERT ON public.orders FOR EACH STATEMENT EXECUTE FUNCTION audit()"},
+					{Name: "orders_old", Definition: "CREATE TRIGGER orders_old AFTER DELETE ON public.orders FOR EACH ROW EXECUTE FUNCTION old()"},
+				},
+			},
+		},
+		// orders_audit is declared by DDL file of table
+		tableTriggers: map[string][]string{"orders": {"orders_audit"}},
+	}
+
+	require.NoError(t, db.readTriggerSQL(path, nil, nil))
+	assert.Equal(t, []string{created}, conn.ddl)
+
+	conn.ddl = nil
+	db.Cfg[string(DROP_ABSENT_TRIGGERS)] = true
+	require.NoError(t, db.readTriggerSQL(path, nil, nil))
+	assert.Equal(t, []string{created, "DROP TRIGGER orders_old ON orders"}, conn.ddl)
+}
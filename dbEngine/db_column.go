@@ -0,0 +1,415 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/ruslanBik4/gotools/typesExt"
+)
+
+// columnTypeAliases consists of names of types in DDL according to udt_name
+var columnTypeAliases = map[string][]string{
+	"bool":        {"bool", "boolean"},
+	"date":        {"date"},
+	"time":        {"time", "time without time zone"},
+	"timetz":      {"timetz", "time with time zone"},
+	"timestamp":   {"timestamp", "timestamp without time zone"},
+	"timestamptz": {"timestamptz", "timestamp with time zone"},
+	"numeric":     {"numeric", "decimal"},
+	"uuid":        {"uuid"},
+	"json":        {"json"},
+	"jsonb":       {"jsonb"},
+	"bytea":       {"bytea"},
+}
+
+// isSameType return true if type from DDL (without size) is alias of udtName
+func isSameType(udtName, ddlType string) bool {
+	ddlType, _, _ = strings.Cut(ddlType, "(")
+	ddlType = strings.TrimSpace(ddlType)
+	if aliases, ok := columnTypeAliases[udtName]; ok {
+		return slices.Contains(aliases, ddlType)
+	}
+
+	return udtName == ddlType
+}
+
+// baseColumn consists of common properties of typed columns for test tables & engines without DB
+type baseColumn struct {
+	name, comment string
+	req, primary  bool
+	isNullable    bool
+	colDefault    any
+	foreign       *ForeignKey
+	table         Table
+}
+
+func newBaseColumn(name, comment string, req bool) baseColumn {
+	return baseColumn{name: name, comment: comment, req: req, isNullable: !req}
+}
+
+// CharacterMaximumLength return max of length text columns
+func (c *baseColumn) CharacterMaximumLength() int {
+	return 0
+}
+
+// Comment of column
+func (c *baseColumn) Comment() string {
+	return c.comment
+}
+
+// Name of column
+func (c *baseColumn) Name() string {
+	return c.name
+}
+
+// AutoIncrement return true if column is autoincrement
+func (c *baseColumn) AutoIncrement() bool {
+	return false
+}
+
+// IsNullable return isNullable flag
+func (c *baseColumn) IsNullable() bool {
+	return c.isNullable
+}
+
+// SetNullable set nullable flag of column
+func (c *baseColumn) SetNullable(f bool) {
+	c.isNullable = f
+}
+
+// Default return default value of column
+func (c *baseColumn) Default() any {
+	return c.colDefault
+}
+
+// SetDefault set default value into column
+func (c *baseColumn) SetDefault(d any) {
+	c.colDefault = d
+}
+
+// Foreign return foreign key of column
+func (c *baseColumn) Foreign() *ForeignKey {
+	return c.foreign
+}
+
+// SetForeign set foreign key of column
+func (c *baseColumn) SetForeign(fk *ForeignKey) {
+	c.foreign = fk
+}
+
+// UserDefinedType return user defined type of column
+func (c *baseColumn) UserDefinedType() *Types {
+	return nil
+}
+
+// Table of column
+func (c *baseColumn) Table() Table {
+	return c.table
+}
+
+// SetTable set table of column
+func (c *baseColumn) SetTable(table Table) {
+	c.table = table
+}
+
+// Primary return true if column is primary key
+func (c *baseColumn) Primary() bool {
+	return c.primary
+}
+
+// SetPrimary set primary flag of column
+func (c *baseColumn) SetPrimary(f bool) {
+	c.primary = f
+}
+
+// Required return true if column need a value
+func (c *baseColumn) Required() bool {
+	return c.req
+}
+
+// checkAttr return flags of differences between column & define of DDL
+// same as psql.Column.CheckAttr does it
+func (c *baseColumn) checkAttr(isTypeValid bool, fieldDefine string) (flags []FlagColumn) {
+	fieldDefine = strings.ToLower(fieldDefine)
+	if !isTypeValid {
+		flags = append(flags, ChgType)
+	}
+
+	isNotNull := strings.Contains(fieldDefine, "not null")
+	if c.isNullable && isNotNull {
+		flags = append(flags, MustNotNull)
+	} else if !c.isNullable && !isNotNull {
+		flags = append(flags, Nullable)
+	}
+
+	colDef, hasDefault := c.colDefault.(string)
+	if newDef := DefaultDDL(fieldDefine); newDef > "" &&
+		(!hasDefault || strings.ToLower(colDef) != strings.Trim(newDef, "'\n")) {
+		flags = append(flags, ChgDefault)
+	}
+
+	return flags
+}
+
+// BoolColumn implement Column for boolean values
+type BoolColumn struct {
+	baseColumn
+}
+
+// NewBoolColumn create new BoolColumn
+func NewBoolColumn(name, comment string, req bool) *BoolColumn {
+	return &BoolColumn{newBaseColumn(name, comment, req)}
+}
+
+// BasicType return GoLangs type of column
+func (c *BoolColumn) BasicType() types.BasicKind {
+	return types.Bool
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *BoolColumn) BasicTypeInfo() types.BasicInfo {
+	return types.IsBoolean
+}
+
+// Type of column
+func (c *BoolColumn) Type() string {
+	return "bool"
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *BoolColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	return c.checkAttr(isSameType(c.Type(), defineType(fieldDefine)), fieldDefine)
+}
+
+// TimeColumn implement Column for date, time, timestamp & timestamptz values
+type TimeColumn struct {
+	baseColumn
+	udtName string
+}
+
+// NewTimeColumn create new TimeColumn, udtName is one of 'date', 'time', 'timetz', 'timestamp', 'timestamptz'
+func NewTimeColumn(name, comment, udtName string, req bool) *TimeColumn {
+	return &TimeColumn{baseColumn: newBaseColumn(name, comment, req), udtName: udtName}
+}
+
+// BasicType return GoLangs type of column
+func (c *TimeColumn) BasicType() types.BasicKind {
+	return typesExt.TStruct
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *TimeColumn) BasicTypeInfo() types.BasicInfo {
+	return types.IsUntyped
+}
+
+// Type of column
+func (c *TimeColumn) Type() string {
+	return c.udtName
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *TimeColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	return c.checkAttr(isSameType(c.Type(), defineType(fieldDefine)), fieldDefine)
+}
+
+// NumericColumn implement Column for numeric values with precision & scale
+type NumericColumn struct {
+	baseColumn
+	precision, scale int
+}
+
+// NewNumericColumn create new NumericColumn, zero precision means numeric without limits
+func NewNumericColumn(name, comment string, req bool, precision, scale int) *NumericColumn {
+	return &NumericColumn{baseColumn: newBaseColumn(name, comment, req), precision: precision, scale: scale}
+}
+
+// BasicType return GoLangs type of column
+func (c *NumericColumn) BasicType() types.BasicKind {
+	return types.Float64
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *NumericColumn) BasicTypeInfo() types.BasicInfo {
+	return types.IsFloat
+}
+
+// Type of column
+func (c *NumericColumn) Type() string {
+	return "numeric"
+}
+
+// Precision & scale of column
+func (c *NumericColumn) Precision() (int, int) {
+	return c.precision, c.scale
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *NumericColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	typ := defineType(fieldDefine)
+	flags := c.checkAttr(isSameType(c.Type(), typ), fieldDefine)
+
+	size := ""
+	if c.precision > 0 {
+		size = fmt.Sprintf("(%d,%d)", c.precision, c.scale)
+	}
+	if _, params, ok := strings.Cut(typ, "("); ok || size > "" {
+		params = "(" + strings.ReplaceAll(params, " ", "")
+		if !strings.Contains(params, ",") {
+			params = strings.TrimSuffix(params, ")") + ",0)"
+		}
+		if params != size {
+			flags = append(flags, ChgLength)
+		}
+	}
+
+	return flags
+}
+
+// UUIDColumn implement Column for uuid values
+type UUIDColumn struct {
+	baseColumn
+}
+
+// NewUUIDColumn create new UUIDColumn
+func NewUUIDColumn(name, comment string, req bool) *UUIDColumn {
+	return &UUIDColumn{newBaseColumn(name, comment, req)}
+}
+
+// BasicType return GoLangs type of column
+func (c *UUIDColumn) BasicType() types.BasicKind {
+	return types.String
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *UUIDColumn) BasicTypeInfo() types.BasicInfo {
+	return types.IsString
+}
+
+// Type of column
+func (c *UUIDColumn) Type() string {
+	return "uuid"
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *UUIDColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	return c.checkAttr(isSameType(c.Type(), defineType(fieldDefine)), fieldDefine)
+}
+
+// JSONColumn implement Column for json & jsonb values
+type JSONColumn struct {
+	baseColumn
+	udtName string
+}
+
+// NewJSONColumn create new JSONColumn with type json
+func NewJSONColumn(name, comment string, req bool) *JSONColumn {
+	return &JSONColumn{baseColumn: newBaseColumn(name, comment, req), udtName: "json"}
+}
+
+// NewJSONBColumn create new JSONColumn with type jsonb
+func NewJSONBColumn(name, comment string, req bool) *JSONColumn {
+	return &JSONColumn{baseColumn: newBaseColumn(name, comment, req), udtName: "jsonb"}
+}
+
+// BasicType return GoLangs type of column
+func (c *JSONColumn) BasicType() types.BasicKind {
+	return types.UnsafePointer
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *JSONColumn) BasicTypeInfo() types.BasicInfo {
+	return types.IsUntyped
+}
+
+// Type of column
+func (c *JSONColumn) Type() string {
+	return c.udtName
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *JSONColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	return c.checkAttr(isSameType(c.Type(), defineType(fieldDefine)), fieldDefine)
+}
+
+// ByteaColumn implement Column for binary values
+type ByteaColumn struct {
+	baseColumn
+}
+
+// NewByteaColumn create new ByteaColumn
+func NewByteaColumn(name, comment string, req bool) *ByteaColumn {
+	return &ByteaColumn{newBaseColumn(name, comment, req)}
+}
+
+// BasicType return GoLangs type of column
+func (c *ByteaColumn) BasicType() types.BasicKind {
+	return types.UnsafePointer
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *ByteaColumn) BasicTypeInfo() types.BasicInfo {
+	return types.IsUntyped
+}
+
+// Type of column
+func (c *ByteaColumn) Type() string {
+	return "bytea"
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *ByteaColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	return c.checkAttr(isSameType(c.Type(), defineType(fieldDefine)), fieldDefine)
+}
+
+// ArrayColumn implement Column for arrays of elem type
+type ArrayColumn struct {
+	baseColumn
+	elem Column
+}
+
+// NewArrayColumn create new ArrayColumn, elem describes type of elements
+func NewArrayColumn(name, comment string, req bool, elem Column) *ArrayColumn {
+	return &ArrayColumn{baseColumn: newBaseColumn(name, comment, req), elem: elem}
+}
+
+// BasicType return GoLangs type of elements same as psql.Column
+func (c *ArrayColumn) BasicType() types.BasicKind {
+	return c.elem.BasicType()
+}
+
+// BasicTypeInfo return types.BasicInfo of elements
+func (c *ArrayColumn) BasicTypeInfo() types.BasicInfo {
+	return c.elem.BasicTypeInfo()
+}
+
+// Type of column as udt_name of psql arrays
+func (c *ArrayColumn) Type() string {
+	return "_" + c.elem.Type()
+}
+
+// IsArray of column
+func (c *ArrayColumn) IsArray() bool {
+	return true
+}
+
+// Elem return column which describes elements of array
+func (c *ArrayColumn) Elem() Column {
+	return c.elem
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *ArrayColumn) CheckAttr(fieldDefine string) []FlagColumn {
+	typ, isArray := strings.CutSuffix(defineType(fieldDefine), "[]")
+	flags := c.checkAttr(isSameType(c.elem.Type(), typ), fieldDefine)
+	if !isArray {
+		flags = append(flags, ChgToArray)
+	}
+
+	return flags
+}
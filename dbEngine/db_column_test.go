@@ -0,0 +1,95 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumn_CheckAttr(t *testing.T) {
+	isActive := NewBoolColumn("is_active", "", true)
+	isActive.SetDefault("true")
+	created := NewTimeColumn("created_at", "", "timestamptz", false)
+	price := NewNumericColumn("price", "", true, 10, 2)
+	tags := NewArrayColumn("tags", "", false, NewStringColumn("tags", "", false))
+
+	tests := []struct {
+		name   string
+		col    Column
+		define string
+		want   []FlagColumn
+	}{
+		{"bool", isActive, "boolean not null default true", nil},
+		{"bool nullable", isActive, "bool default true", []FlagColumn{Nullable}},
+		{"bool default", isActive, "bool not null default false", []FlagColumn{ChgDefault}},
+		{"timestamptz", created, "timestamp with time zone default now()", []FlagColumn{ChgDefault}},
+		{"timestamp", created, "timestamp", []FlagColumn{ChgType}},
+		{"time not null", created, "timestamptz not null", []FlagColumn{MustNotNull}},
+		{"numeric", price, "numeric(10, 2) not null", nil},
+		{"decimal", price, "decimal(12,2) not null", []FlagColumn{ChgLength}},
+		{"numeric without size", price, "numeric not null", []FlagColumn{ChgLength}},
+		{"uuid", NewUUIDColumn("id", "", false), "uuid", nil},
+		{"jsonb", NewJSONBColumn("data", "", false), "json", []FlagColumn{ChgType}},
+		{"bytea", NewByteaColumn("raw", "", false), "bytea", nil},
+		{"array", tags, "string[]", nil},
+		{"not array", tags, "string", []FlagColumn{ChgToArray}},
+		{"array of int", NewArrayColumn("ids", "", false, NewNumberColumn("ids", "", false)), "integer[]", []FlagColumn{ChgType}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.col.CheckAttr(tt.define))
+		})
+	}
+}
+
+func TestArrayColumn(t *testing.T) {
+	elem := NewUUIDColumn("ids", "", false)
+	col := NewArrayColumn("ids", "list of ids", true, elem)
+	fk := &ForeignKey{Parent: "users", Column: "id"}
+	col.SetForeign(fk)
+
+	assert.True(t, col.IsArray())
+	assert.Equal(t, "_uuid", col.Type())
+	assert.Equal(t, types.String, col.BasicType())
+	assert.Equal(t, elem, col.Elem())
+	assert.Equal(t, fk, col.Foreign())
+	assert.True(t, col.Required())
+	assert.False(t, col.IsNullable())
+	assert.Nil(t, col.UserDefinedType())
+	assert.Nil(t, NewStringColumn("name", "", false).UserDefinedType())
+}
+
+func TestForeignKey_ForColumn(t *testing.T) {
+	fk := &ForeignKey{
+		Name:          "orders_items_fk",
+		Parent:        "items",
+		Columns:       []string{"shop", "sku"},
+		ParentColumns: []string{"shop_id", "code"},
+		MatchType:     "FULL",
+		Deferrable:    true,
+	}
+
+	key := fk.ForColumn("sku")
+	assert.Equal(t, "code", key.Column)
+	assert.Equal(t, fk.Columns, key.Columns)
+	assert.True(t, key.Deferrable)
+	assert.Empty(t, fk.Column)
+	assert.Nil(t, fk.ForColumn("price"))
+
+	shop, sku := NewUUIDColumn("shop", "", true), NewUUIDColumn("sku", "", true)
+	shop.SetForeign(fk.ForColumn("shop"))
+	sku.SetForeign(fk.ForColumn("sku"))
+	user := NewUUIDColumn("user_id", "", true)
+	user.SetForeign(&ForeignKey{Parent: "users", Column: "id"})
+	table := NewTableString("orders", "", []Column{shop, sku, NewStringColumn("name", "", false), user}, nil, nil)
+
+	assert.Equal(t, ForeignKeys{
+		fk.ForColumn("shop"),
+		{Parent: "users", Column: "id", Columns: []string{"user_id"}, ParentColumns: []string{"id"}},
+	}, table.ForeignKeys())
+}
@@ -3,6 +3,8 @@ package dbEngine
 import (
 	"encoding/json"
 	"go/types"
+	"slices"
+	"strings"
 
 	"github.com/jackc/pgtype"
 	"golang.org/x/net/context"
@@ -19,6 +21,7 @@ type (
 // Connection implement conn operation
 type Connection interface {
 	InitConn(ctx context.Context, dbURL string) error
+	Ping(ctx context.Context) error
 	GetRoutines(ctx context.Context, dbTypes map[string]Types, tables map[string]Table, cfg *CfgDB) (map[string]Routine, error)
 	GetSchema(ctx context.Context, cfg *CfgDB) (database map[string]*string, tables map[string]Table, routines map[string]Routine, dbTypes map[string]Types, err error)
 	GetStat() string
@@ -133,7 +136,13 @@ type Table interface {
 	Insert(ctx context.Context, Options ...BuildSqlOptions) (int64, error)
 	Update(ctx context.Context, Options ...BuildSqlOptions) (int64, error)
 	Upsert(ctx context.Context, Options ...BuildSqlOptions) (int64, error)
+	Validate(columns []string, values []any) error
 	Name() string
+	PrimaryKey() []Column
+	UniqueConstraints() Constraints
+	Constraints() Constraints
+	ForeignKeys() ForeignKeys
+	Triggers() Triggers
 	ReReadColumn(ctx context.Context, name string) Column
 	Select(ctx context.Context, Options ...BuildSqlOptions) error
 	SelectAndScanEach(ctx context.Context, each func() error, rowValue RowScanner, Options ...BuildSqlOptions) error
@@ -141,6 +150,21 @@ type Table interface {
 	SelectAndRunEach(ctx context.Context, each FncEachRow, Options ...BuildSqlOptions) error
 }
 
+// MaterializedView describes table which is able to compare its stored definition of materialized view with DDL
+type MaterializedView interface {
+	// DefinitionChanged reports whether query with names of columns differs from definition stored by DB
+	DefinitionChanged(ctx context.Context, columns []string, query string) (bool, error)
+	// DependentDDL return statements which restore indexes & grants of view after recreating
+	DependentDDL(ctx context.Context) ([]string, error)
+}
+
+// CheckDefinition describes table which is able to normalize definition of check constraint by DB,
+// because DB rewrites expressions of checks (IN, BETWEEN etc.)
+type CheckDefinition interface {
+	// CheckDefinition return definition of check constraint as DB stores it
+	CheckDefinition(ctx context.Context, definition string) (string, error)
+}
+
 // Routine describes methods for function/procedures operations
 type Routine interface {
 	Name() string
@@ -155,13 +179,121 @@ type Routine interface {
 	SelectAndRunEach(ctx context.Context, each FncEachRow, Options ...BuildSqlOptions) error
 }
 
-// ForeignKey consists of parameters of foreign key
+// RoutineDefinition describes routine which is able to compare its stored definition with DDL
+type RoutineDefinition interface {
+	// IsSignature reports whether signature 'name(types of input arguments)' belongs to routine, DB compares types
+	IsSignature(ctx context.Context, signature string) (bool, error)
+	// DefinitionChanged reports whether statement 'create or replace' changes definition stored by DB,
+	// it returns error of DB if routine can't be replaced
+	DefinitionChanged(ctx context.Context, ddl string) (bool, error)
+	// DependentDDL return statements which restore grants & comment of routine after recreating
+	DependentDDL(ctx context.Context) ([]string, error)
+}
+
+// RoutineFinder describes connection which is able to find routine of DB by its signature
+// without reading routines of schema
+type RoutineFinder interface {
+	// FindRoutine return routine with signature 'name(types of input arguments)' or nil if DB hasn't it
+	FindRoutine(ctx context.Context, signature string) (RoutineDefinition, error)
+}
+
+// ForeignKey consists of parameters of foreign key,
+// Column is column of parent table which is referenced by column of table
 type ForeignKey struct {
-	Parent     string `json:"parent"`
-	Column     string `json:"column"`
-	UpdateRule string `json:"update_rule"`
-	DeleteRule string `json:"delete_rule"`
-	ForeignCol Column `json:"-"`
+	Name              string   `json:"name"`
+	Parent            string   `json:"parent"`
+	Column            string   `json:"column"`
+	Columns           []string `json:"columns"`
+	ParentColumns     []string `json:"parent_columns"`
+	UpdateRule        string   `json:"update_rule"`
+	DeleteRule        string   `json:"delete_rule"`
+	MatchType         string   `json:"match_type"`
+	Deferrable        bool     `json:"deferrable"`
+	InitiallyDeferred bool     `json:"initially_deferred"`
+	ForeignCol        Column   `json:"-"`
+}
+
+// GetFields implements interface RowScanner
+func (fk *ForeignKey) GetFields(columns []Column) []any {
+	fields := make([]any, len(columns))
+	for i, col := range columns {
+		switch col.Name() {
+		case "constraint_name":
+			fields[i] = &fk.Name
+		case "parent":
+			fields[i] = &fk.Parent
+		case "column_names":
+			fields[i] = &fk.Columns
+		case "parent_columns":
+			fields[i] = &fk.ParentColumns
+		case "update_rule":
+			fields[i] = &fk.UpdateRule
+		case "delete_rule":
+			fields[i] = &fk.DeleteRule
+		case "match_type":
+			fields[i] = &fk.MatchType
+		case "deferrable":
+			fields[i] = &fk.Deferrable
+		case "initially_deferred":
+			fields[i] = &fk.InitiallyDeferred
+		default:
+			logs.DebugLog("unknown column %s", col.Name())
+		}
+	}
+
+	return fields
+}
+
+// ForColumn return foreign key for column 'name' of table (Column is referenced column of parent)
+// or nil if foreign key doesn't consist of the column
+func (fk *ForeignKey) ForColumn(name string) *ForeignKey {
+	i := slices.Index(fk.Columns, name)
+	if i < 0 || i >= len(fk.ParentColumns) {
+		return nil
+	}
+
+	key := *fk
+	key.Column = fk.ParentColumns[i]
+	key.ForeignCol = nil
+
+	return &key
+}
+
+// ForeignKeys cluster foreign keys of table
+type ForeignKeys []*ForeignKey
+
+// GetFields implements interface RowScanner
+func (f *ForeignKeys) GetFields(columns []Column) []any {
+	fk := &ForeignKey{}
+	*f = append(*f, fk)
+
+	return fk.GetFields(columns)
+}
+
+// ColumnsForeignKeys return foreign keys of table according to foreign keys of its columns,
+// it's used by tables which don't read foreign keys from DB
+func ColumnsForeignKeys(t Table) ForeignKeys {
+	res := make(ForeignKeys, 0)
+	for _, col := range t.Columns() {
+		fk := col.Foreign()
+		if fk == nil {
+			continue
+		}
+
+		if fk.Name > "" && slices.ContainsFunc(res, func(key *ForeignKey) bool {
+			return key.Name == fk.Name
+		}) {
+			continue
+		}
+
+		key := *fk
+		if len(key.Columns) == 0 {
+			key.Columns, key.ParentColumns = []string{col.Name()}, []string{fk.Column}
+		}
+		res = append(res, &key)
+	}
+
+	return res
 }
 
 // Column describes methods for table/view/function builderOpts
@@ -185,12 +317,37 @@ type Column interface {
 	SetNullable(bool) //+
 }
 
+// GeneratedColumn describes column with values generated by DB: identity or generated stored column
+type GeneratedColumn interface {
+	// Identity return 'ALWAYS' or 'BY DEFAULT' for identity column & empty string for other
+	Identity() string
+	// GenerationExpression return expression of generated stored column
+	GenerationExpression() string
+}
+
+// IsGeneratedColumn return true if DB doesn't allow to write values into column:
+// generated stored column or identity column GENERATED ALWAYS
+func IsGeneratedColumn(col Column) bool {
+	c, ok := col.(GeneratedColumn)
+	return ok && (c.GenerationExpression() > "" || c.Identity() == IdentityAlways)
+}
+
+// ColumnIdentity return identity of column (see GeneratedColumn)
+func ColumnIdentity(col Column) string {
+	if c, ok := col.(GeneratedColumn); ok {
+		return c.Identity()
+	}
+
+	return ""
+}
+
 // Index consists of index properties
 type Index struct {
 	Name                         string
 	foreignTable, foreignColumn  string
 	updateCascade, deleteCascade string
 	Expr                         string
+	Method                       string
 	Where                        string
 	Unique                       bool
 	Columns                      []string
@@ -208,6 +365,11 @@ func (ind *Index) AddColumn(name string) bool {
 	return true
 }
 
+// IsFullText return true if index is GIN index on tsvector expression
+func (ind *Index) IsFullText() bool {
+	return ind.Method == "gin" && strings.Contains(ind.Expr, "to_tsvector")
+}
+
 // GetFields implements interface RowScanner
 func (ind *Index) GetFields(columns []Column) []any {
 	fields := make([]any, len(columns))
@@ -219,6 +381,8 @@ func (ind *Index) GetFields(columns []Column) []any {
 			fields[i] = &ind.Expr
 		case "ind_unique":
 			fields[i] = &ind.Unique
+		case "ind_method":
+			fields[i] = &ind.Method
 		case "column_names":
 			fields[i] = &ind.Columns
 		default:
@@ -245,6 +409,198 @@ func (i Indexes) LastIndex() *Index {
 	return i[len(i)-1]
 }
 
+// types of constraints (pg_constraint.contype)
+const (
+	ConstraintPrimary = "p"
+	ConstraintUnique  = "u"
+	ConstraintCheck   = "c"
+	ConstraintExclude = "x"
+)
+
+// Constraint consists of properties of table constraint
+type Constraint struct {
+	Name       string
+	Type       string
+	Columns    []string
+	Definition string
+}
+
+// GetFields implements interface RowScanner
+func (c *Constraint) GetFields(columns []Column) []any {
+	fields := make([]any, len(columns))
+	for i, col := range columns {
+		switch col.Name() {
+		case "constraint_name":
+			fields[i] = &c.Name
+		case "constraint_type":
+			fields[i] = &c.Type
+		case "column_names":
+			fields[i] = &c.Columns
+		case "definition":
+			fields[i] = &c.Definition
+		default:
+			logs.DebugLog("unknown column %s", col.Name())
+		}
+	}
+
+	return fields
+}
+
+// DDL return definition of constraint for statements 'create table' & 'alter table'
+func (c *Constraint) DDL() string {
+	if c.Name == "" {
+		return c.Definition
+	}
+
+	return "CONSTRAINT " + c.Name + " " + c.Definition
+}
+
+// Constraints cluster constraints of table
+type Constraints []*Constraint
+
+// GetFields implements interface RowScanner
+func (c *Constraints) GetFields(columns []Column) []any {
+	constraint := &Constraint{}
+	*c = append(*c, constraint)
+
+	return constraint.GetFields(columns)
+}
+
+// Filter return constraints with type typ
+func (c Constraints) Filter(typ string) Constraints {
+	res := make(Constraints, 0)
+	for _, constraint := range c {
+		if constraint.Type == typ {
+			res = append(res, constraint)
+		}
+	}
+
+	return res
+}
+
+// Trigger consists of properties of trigger of table, Definition is statement 'create trigger' (pg_get_triggerdef)
+type Trigger struct {
+	Name string
+	// 'BEFORE', 'AFTER' or 'INSTEAD OF'
+	Timing string
+	// 'INSERT', 'UPDATE', 'DELETE' or 'TRUNCATE'
+	Events     []string
+	ForEachRow bool
+	Function   string
+	Enabled    bool
+	Definition string
+}
+
+// GetFields implements interface RowScanner
+func (t *Trigger) GetFields(columns []Column) []any {
+	fields := make([]any, len(columns))
+	for i, col := range columns {
+		switch col.Name() {
+		case "trigger_name":
+			fields[i] = &t.Name
+		case "timing":
+			fields[i] = &t.Timing
+		case "events":
+			fields[i] = &t.Events
+		case "for_each_row":
+			fields[i] = &t.ForEachRow
+		case "function_name":
+			fields[i] = &t.Function
+		case "enabled":
+			fields[i] = &t.Enabled
+		case "definition":
+			fields[i] = &t.Definition
+		default:
+			logs.DebugLog("unknown column %s", col.Name())
+		}
+	}
+
+	return fields
+}
+
+// Triggers cluster triggers of table
+type Triggers []*Trigger
+
+// GetFields implements interface RowScanner
+func (t *Triggers) GetFields(columns []Column) []any {
+	trigger := &Trigger{}
+	*t = append(*t, trigger)
+
+	return trigger.GetFields(columns)
+}
+
+// Find return trigger with name or nil
+func (t Triggers) Find(name string) *Trigger {
+	for _, trigger := range t {
+		if trigger.Name == name {
+			return trigger
+		}
+	}
+
+	return nil
+}
+
+// PrimaryColumns return columns of table with flag Primary
+func PrimaryColumns(t Table) []Column {
+	res := make([]Column, 0)
+	for _, col := range t.Columns() {
+		if col.Primary() {
+			res = append(res, col)
+		}
+	}
+
+	return res
+}
+
+// UniqueIndexes return unique constraints of table according to its unique indexes on columns
+func UniqueIndexes(t Table) Constraints {
+	res := make(Constraints, 0)
+	for _, ind := range t.Indexes() {
+		if ind.Unique && len(ind.Columns) > 0 && strings.TrimSpace(ind.Expr) == "" {
+			res = append(res, &Constraint{
+				Name:       ind.Name,
+				Type:       ConstraintUnique,
+				Columns:    ind.Columns,
+				Definition: "UNIQUE (" + strings.Join(ind.Columns, ", ") + ")",
+			})
+		}
+	}
+
+	return res
+}
+
+// KeyConstraints return constraint of primary key & unique constraints of table,
+// it's used by tables which don't read constraints from DB
+func KeyConstraints(t Table) Constraints {
+	res := make(Constraints, 0)
+	if pk := t.PrimaryKey(); len(pk) > 0 {
+		constraint := &Constraint{Name: t.Name() + "_pkey", Type: ConstraintPrimary}
+		for _, col := range pk {
+			constraint.Columns = append(constraint.Columns, col.Name())
+		}
+		constraint.Definition = "PRIMARY KEY (" + strings.Join(constraint.Columns, ", ") + ")"
+		res = append(res, constraint)
+	}
+
+	return append(res, t.UniqueConstraints()...)
+}
+
+// KeyColumns return names of columns of primary key of table or of its first unique constraint
+func KeyColumns(t Table) []string {
+	keys := make([]string, 0)
+	for _, col := range t.PrimaryKey() {
+		keys = append(keys, col.Name())
+	}
+
+	if len(keys) == 0 {
+		if unique := t.UniqueConstraints(); len(unique) > 0 {
+			keys = append(keys, unique[0].Columns...)
+		}
+	}
+
+	return keys
+}
+
 // RowScanner must return slice variables for pgx.Rows.Scan
 type RowScanner interface {
 	GetFields(columns []Column) []any
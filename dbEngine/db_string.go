@@ -12,8 +12,7 @@ type StringColumn struct {
 }
 
 func (s *StringColumn) UserDefinedType() *Types {
-	//TODO implement me
-	panic("implement me")
+	return nil
 }
 
 func NewStringColumn(name, comment string, req bool, maxLen ...int) *StringColumn {
@@ -0,0 +1,175 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgtype"
+	"github.com/ruslanBik4/gotools/typesExt"
+)
+
+// TypeDecoder return new value for decoding & encoding data of PostgreSQL type,
+// nil decoder means that data is passed as text
+type TypeDecoder func() pgtype.Value
+
+// RegisteredType consists of mapping PostgreSQL type into Go
+type RegisteredType struct {
+	UdtName   string
+	BasicKind types.BasicKind
+	// GoType is name of Go type for code generator, e.g. 'string', 'pgtype.Box'
+	GoType  string
+	Decoder TypeDecoder
+}
+
+// NewValue return new value of Decoder or nil
+func (t RegisteredType) NewValue() pgtype.Value {
+	if t.Decoder == nil {
+		return nil
+	}
+
+	return t.Decoder()
+}
+
+var (
+	typesRegistry = map[string]RegisteredType{}
+	typesLock     sync.RWMutex
+)
+
+// RegisterType adds (or replaces) mapping of PostgreSQL type udtName,
+// it's used by column metadata, SQLBuilder for arguments & code generator
+func RegisterType(udtName string, basicKind types.BasicKind, goType string, decoder TypeDecoder) {
+	typesLock.Lock()
+	defer typesLock.Unlock()
+
+	typesRegistry[udtName] = RegisteredType{
+		UdtName:   udtName,
+		BasicKind: basicKind,
+		GoType:    goType,
+		Decoder:   decoder,
+	}
+}
+
+// LookupType return registered mapping of udtName,
+// arrays ('_name') of registered types without own mapping get slice of element GoType & text decoding
+func LookupType(udtName string) (RegisteredType, bool) {
+	typesLock.RLock()
+	defer typesLock.RUnlock()
+
+	if t, ok := typesRegistry[udtName]; ok {
+		return t, true
+	}
+
+	if elem, ok := strings.CutPrefix(udtName, "_"); ok {
+		if t, ok := typesRegistry[elem]; ok {
+			return RegisteredType{
+				UdtName:   udtName,
+				BasicKind: t.BasicKind,
+				GoType:    "[]" + t.GoType,
+			}, true
+		}
+	}
+
+	return RegisteredType{}, false
+}
+
+// RegisteredTypes return all registered mappings sorted by udt name
+func RegisteredTypes() []RegisteredType {
+	typesLock.RLock()
+	defer typesLock.RUnlock()
+
+	list := make([]RegisteredType, 0, len(typesRegistry))
+	for _, t := range typesRegistry {
+		list = append(list, t)
+	}
+	slices.SortFunc(list, func(a, b RegisteredType) int {
+		return strings.Compare(a.UdtName, b.UdtName)
+	})
+
+	return list
+}
+
+// ConvertArg return argument for column of registered type decoded by its Decoder,
+// argument is returned as is if it has type of Decoder already or can't be converted
+func ConvertArg(col Column, arg any) any {
+	if col == nil || arg == nil {
+		return arg
+	}
+
+	t, ok := LookupType(col.Type())
+	if !ok || t.Decoder == nil {
+		return arg
+	}
+
+	if _, ok := arg.(pgtype.Value); ok {
+		return arg
+	}
+
+	value := t.NewValue()
+	if reflect.TypeOf(arg) == reflect.TypeOf(value).Elem() {
+		return arg
+	}
+
+	if err := value.Set(arg); err != nil {
+		return arg
+	}
+
+	return value
+}
+
+func newRangeArray(name string, elemOID uint32, newElem func() pgtype.ValueTranscoder) TypeDecoder {
+	return func() pgtype.Value {
+		return pgtype.NewArrayType(name, elemOID, newElem)
+	}
+}
+
+func init() {
+	// uuid & text like types of extensions
+	RegisterType("uuid", types.String, "string", func() pgtype.Value { return &pgtype.UUID{} })
+	RegisterType("_uuid", types.String, "[]string", func() pgtype.Value { return &pgtype.UUIDArray{} })
+	RegisterType("ltree", types.String, "string", func() pgtype.Value { return &pgtype.Ltree{} })
+	RegisterType("tsvector", types.String, "string", nil)
+	RegisterType("tsquery", types.String, "string", nil)
+	RegisterType("hstore", typesExt.TMap, "pgtype.Hstore", func() pgtype.Value { return &pgtype.Hstore{} })
+	RegisterType("_hstore", typesExt.TMap, "pgtype.HstoreArray", func() pgtype.Value { return &pgtype.HstoreArray{} })
+
+	// network types
+	RegisterType("macaddr", types.String, "string", func() pgtype.Value { return &pgtype.Macaddr{} })
+	RegisterType("_macaddr", types.String, "[]string", func() pgtype.Value { return &pgtype.MacaddrArray{} })
+	RegisterType("macaddr8", types.String, "string", nil)
+	RegisterType("inet", typesExt.TStruct, "pgtype.Inet", func() pgtype.Value { return &pgtype.Inet{} })
+	RegisterType("_inet", typesExt.TStruct, "pgtype.InetArray", func() pgtype.Value { return &pgtype.InetArray{} })
+	RegisterType("cidr", typesExt.TStruct, "pgtype.CIDR", func() pgtype.Value { return &pgtype.CIDR{} })
+	RegisterType("_cidr", typesExt.TStruct, "pgtype.CIDRArray", func() pgtype.Value { return &pgtype.CIDRArray{} })
+
+	// geometric types
+	RegisterType("box", typesExt.TStruct, "pgtype.Box", func() pgtype.Value { return &pgtype.Box{} })
+	RegisterType("line", typesExt.TStruct, "pgtype.Line", func() pgtype.Value { return &pgtype.Line{} })
+	RegisterType("lseg", typesExt.TStruct, "pgtype.Lseg", func() pgtype.Value { return &pgtype.Lseg{} })
+	RegisterType("path", typesExt.TStruct, "pgtype.Path", func() pgtype.Value { return &pgtype.Path{} })
+	RegisterType("polygon", typesExt.TStruct, "pgtype.Polygon", func() pgtype.Value { return &pgtype.Polygon{} })
+	RegisterType("circle", typesExt.TStruct, "pgtype.Circle", func() pgtype.Value { return &pgtype.Circle{} })
+
+	// ranges & their arrays, code generator gets arrays of ranges as text of elements
+	RegisterType("int4range", typesExt.TStruct, "pgtype.Int4range", func() pgtype.Value { return &pgtype.Int4range{} })
+	RegisterType("int8range", typesExt.TStruct, "pgtype.Int8range", func() pgtype.Value { return &pgtype.Int8range{} })
+	RegisterType("tstzrange", typesExt.TStruct, "pgtype.Tstzrange", func() pgtype.Value { return &pgtype.Tstzrange{} })
+	RegisterType("_int4range", typesExt.TStruct, "[]string",
+		newRangeArray("_int4range", pgtype.Int4rangeOID, func() pgtype.ValueTranscoder { return &pgtype.Int4range{} }))
+	RegisterType("_int8range", typesExt.TStruct, "[]string",
+		newRangeArray("_int8range", pgtype.Int8rangeOID, func() pgtype.ValueTranscoder { return &pgtype.Int8range{} }))
+	RegisterType("_numrange", typesExt.TStruct, "[]string",
+		newRangeArray("_numrange", pgtype.NumrangeOID, func() pgtype.ValueTranscoder { return &pgtype.Numrange{} }))
+	RegisterType("_daterange", typesExt.TStruct, "[]string",
+		newRangeArray("_daterange", pgtype.DaterangeOID, func() pgtype.ValueTranscoder { return &pgtype.Daterange{} }))
+	RegisterType("_tsrange", typesExt.TStruct, "[]string",
+		newRangeArray("_tsrange", pgtype.TsrangeOID, func() pgtype.ValueTranscoder { return &pgtype.Tsrange{} }))
+	RegisterType("_tstzrange", typesExt.TStruct, "[]string",
+		newRangeArray("_tstzrange", pgtype.TstzrangeOID, func() pgtype.ValueTranscoder { return &pgtype.Tstzrange{} }))
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ruslanBik4/gotools/typesExt"
+)
+
+func TestLookupType(t *testing.T) {
+	tests := []struct {
+		udtName string
+		kind    types.BasicKind
+		goType  string
+		decoder bool
+	}{
+		{"uuid", types.String, "string", true},
+		{"_uuid", types.String, "[]string", true},
+		{"ltree", types.String, "string", true},
+		{"_ltree", types.String, "[]string", false},
+		{"hstore", typesExt.TMap, "pgtype.Hstore", true},
+		{"macaddr", types.String, "string", true},
+		{"inet", typesExt.TStruct, "pgtype.Inet", true},
+		{"_inet", typesExt.TStruct, "pgtype.InetArray", true},
+		{"cidr", typesExt.TStruct, "pgtype.CIDR", true},
+		{"box", typesExt.TStruct, "pgtype.Box", true},
+		{"line", typesExt.TStruct, "pgtype.Line", true},
+		{"tsvector", types.String, "string", false},
+		{"_int4range", typesExt.TStruct, "[]string", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.udtName, func(t *testing.T) {
+			got, ok := LookupType(tt.udtName)
+			require.True(t, ok)
+			assert.Equal(t, tt.udtName, got.UdtName)
+			assert.Equal(t, tt.kind, got.BasicKind)
+			assert.Equal(t, tt.goType, got.GoType)
+			assert.Equal(t, tt.decoder, got.NewValue() != nil)
+		})
+	}
+
+	_, ok := LookupType("unknown_type")
+	assert.False(t, ok)
+
+	RegisterType("citext_email", types.String, "string", nil)
+	got, ok := LookupType("_citext_email")
+	require.True(t, ok)
+	assert.Equal(t, "[]string", got.GoType)
+	assert.Contains(t, RegisteredTypes(), RegisteredType{UdtName: "citext_email", BasicKind: types.String, GoType: "string"})
+}
+
+func TestConvertArg(t *testing.T) {
+	id := "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"
+	uuid := NewUUIDColumn("id", "", true)
+
+	got := ConvertArg(uuid, id)
+	require.IsType(t, &pgtype.UUID{}, got)
+	assert.Equal(t, pgtype.Present, got.(*pgtype.UUID).Status)
+
+	assert.Equal(t, "not uuid", ConvertArg(uuid, "not uuid"))
+	assert.Equal(t, []string{id}, ConvertArg(uuid, []string{id}))
+	assert.Equal(t, id, ConvertArg(NewStringColumn("name", "", false), id))
+	assert.Nil(t, ConvertArg(uuid, nil))
+
+	table := NewTableString("devices", "", []Column{uuid, NewStringColumn("name", "", false)}, nil, nil)
+	b, err := NewSQLBuilder(table, Columns("id", "name"), Values(id, "router"))
+	require.NoError(t, err)
+
+	sql, err := b.InsertSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO devices(id,name) VALUES ($1,$2) ", sql)
+	assert.IsType(t, &pgtype.UUID{}, b.Args[0])
+	assert.Equal(t, "router", b.Args[1])
+
+	b, err = NewSQLBuilder(table, ColumnsForSelect("name"), WhereForSelect("id"), ArgsForSelect(id))
+	require.NoError(t, err)
+
+	sql, err = b.SelectSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM devices WHERE id=$1", sql)
+	assert.IsType(t, &pgtype.UUID{}, b.Args[0])
+}
@@ -0,0 +1,429 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is kind of lexical token of DDL
+type TokenKind uint8
+
+// kinds of tokens
+const (
+	TokenEOF TokenKind = iota
+	TokenIdent
+	TokenQuotedIdent
+	TokenString
+	TokenNumber
+	TokenOperator
+	TokenParam
+	TokenLParen
+	TokenRParen
+	TokenComma
+	TokenSemicolon
+)
+
+// Position of token in DDL, Line & Column start from 1, Column counts runes
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// Token is lexical token of DDL, Text is source text of token
+type Token struct {
+	Kind TokenKind
+	Text string
+	Pos  Position
+}
+
+// End return offset of end of token in DDL
+func (t Token) End() int {
+	return t.Pos.Offset + len(t.Text)
+}
+
+// Is reports whether token is unquoted identifier (keyword) equal one of keywords without case
+func (t Token) Is(keywords ...string) bool {
+	if t.Kind != TokenIdent {
+		return false
+	}
+
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.Text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsName reports whether token is identifier (quoted or not)
+func (t Token) IsName() bool {
+	return t.Kind == TokenIdent || t.Kind == TokenQuotedIdent
+}
+
+// Name return identifier as DB stores it: unquoted identifier in lower case & quoted one without quotes
+func (t Token) Name() string {
+	if t.Kind == TokenQuotedIdent {
+		return strings.ReplaceAll(t.Text[1:len(t.Text)-1], `""`, `"`)
+	}
+
+	return strings.ToLower(t.Text)
+}
+
+// StringValue return value of string constant without quotes & escapes
+func (t Token) StringValue() string {
+	text := t.Text
+	switch {
+	case strings.HasPrefix(text, "$"):
+		tag := dollarTag(text)
+		return text[len(tag) : len(text)-len(tag)]
+	case text[0] == 'e' || text[0] == 'E':
+		return unescapeString(text[2 : len(text)-1])
+	case text[0] != '\'':
+		// bit or national strings
+		text = text[1:]
+	}
+
+	return strings.ReplaceAll(text[1:len(text)-1], "''", "'")
+}
+
+// unescapeString replaces backslash escapes of string E'...'
+func unescapeString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch c = s[i]; c {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = '\t'
+			case 'r':
+				c = '\r'
+			}
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// ddlLexer splits DDL into tokens, comments & spaces are skipped
+type ddlLexer struct {
+	src    string
+	offset int
+	line   int
+	column int
+}
+
+// TokenizeDDL return tokens of DDL or ErrDDLSyntax on unterminated string, identifier or comment
+func TokenizeDDL(ddl string) ([]Token, error) {
+	l := &ddlLexer{src: ddl, line: 1, column: 1}
+	tokens := make([]Token, 0)
+	for {
+		tok, ok, err := l.next()
+		if err != nil {
+			return tokens, err
+		}
+		if !ok {
+			return tokens, nil
+		}
+
+		tokens = append(tokens, tok)
+	}
+}
+
+func (l *ddlLexer) pos() Position {
+	return Position{Offset: l.offset, Line: l.line, Column: l.column}
+}
+
+func (l *ddlLexer) peek(n int) byte {
+	if l.offset+n < len(l.src) {
+		return l.src[l.offset+n]
+	}
+
+	return 0
+}
+
+// advance moves position on n bytes
+func (l *ddlLexer) advance(n int) {
+	for end := l.offset + n; l.offset < end && l.offset < len(l.src); {
+		r, size := utf8.DecodeRuneInString(l.src[l.offset:])
+		l.offset += size
+		if r == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+	}
+}
+
+// skipSpaces skips spaces & comments ('--' & nested '/* */')
+func (l *ddlLexer) skipSpaces() error {
+	for l.offset < len(l.src) {
+		switch c := l.peek(0); {
+		case c == ' ', c == '\t', c == '\n', c == '\r', c == '\f', c == '\v':
+			l.advance(1)
+
+		case c == '-' && l.peek(1) == '-':
+			end := strings.IndexByte(l.src[l.offset:], '\n')
+			if end < 0 {
+				end = len(l.src) - l.offset
+			}
+			l.advance(end)
+
+		case c == '/' && l.peek(1) == '*':
+			start := l.pos()
+			l.advance(2)
+			for level := 1; level > 0; {
+				switch {
+				case l.offset >= len(l.src):
+					return NewErrDDLSyntax(start, "unterminated comment")
+				case l.peek(0) == '/' && l.peek(1) == '*':
+					level++
+					l.advance(2)
+				case l.peek(0) == '*' && l.peek(1) == '/':
+					level--
+					l.advance(2)
+				default:
+					l.advance(1)
+				}
+			}
+
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// next return next token, false if DDL is finished
+func (l *ddlLexer) next() (Token, bool, error) {
+	if err := l.skipSpaces(); err != nil {
+		return Token{}, false, err
+	}
+
+	if l.offset >= len(l.src) {
+		return Token{}, false, nil
+	}
+
+	start := l.pos()
+	kind, n, err := l.scan()
+	if err != nil {
+		return Token{}, false, err
+	}
+
+	l.advance(n)
+
+	return Token{Kind: kind, Text: l.src[start.Offset:l.offset], Pos: start}, true, nil
+}
+
+// scan return kind & length of token at current position
+func (l *ddlLexer) scan() (TokenKind, int, error) {
+	src := l.src[l.offset:]
+	switch c := src[0]; {
+	case c == '(':
+		return TokenLParen, 1, nil
+	case c == ')':
+		return TokenRParen, 1, nil
+	case c == ',':
+		return TokenComma, 1, nil
+	case c == ';':
+		return TokenSemicolon, 1, nil
+
+	case c == '\'':
+		n, err := l.scanQuoted(src, '\'', false)
+		return TokenString, n, err
+
+	case (c == 'e' || c == 'E') && len(src) > 1 && src[1] == '\'':
+		n, err := l.scanQuoted(src[1:], '\'', true)
+		return TokenString, n + 1, err
+
+	case (c == 'b' || c == 'B' || c == 'x' || c == 'X' || c == 'n' || c == 'N') && len(src) > 1 && src[1] == '\'':
+		n, err := l.scanQuoted(src[1:], '\'', false)
+		return TokenString, n + 1, err
+
+	case c == '"':
+		n, err := l.scanQuoted(src, '"', false)
+		return TokenQuotedIdent, n, err
+
+	case c == '$':
+		if tag := dollarTag(src); tag != "" {
+			end := strings.Index(src[len(tag):], tag)
+			if end < 0 {
+				return TokenString, 0, NewErrDDLSyntax(l.pos(), "unterminated dollar-quoted string")
+			}
+			return TokenString, len(tag) + end + len(tag), nil
+		}
+
+		n := 1
+		for n < len(src) && src[n] >= '0' && src[n] <= '9' {
+			n++
+		}
+		return TokenParam, n, nil
+
+	case c >= '0' && c <= '9', c == '.' && len(src) > 1 && src[1] >= '0' && src[1] <= '9':
+		return TokenNumber, scanNumber(src), nil
+
+	case c == ':' && len(src) > 1 && src[1] == ':':
+		return TokenOperator, 2, nil
+
+	case isOperatorChar(c):
+		n := 1
+		for n < len(src) && isOperatorChar(src[n]) &&
+			!strings.HasPrefix(src[n:], "--") && !strings.HasPrefix(src[n:], "/*") {
+			n++
+		}
+		return TokenOperator, n, nil
+
+	default:
+		r, size := utf8.DecodeRuneInString(src)
+		if !isIdentStart(r) {
+			return TokenOperator, size, nil
+		}
+
+		n := size
+		for n < len(src) {
+			r, size := utf8.DecodeRuneInString(src[n:])
+			if !isIdentStart(r) && !unicode.IsDigit(r) && r != '$' {
+				break
+			}
+			n += size
+		}
+		return TokenIdent, n, nil
+	}
+}
+
+// scanQuoted return length of string or identifier quoted by 'quote', doubled quote is escaped one,
+// backslash escapes next char in strings E'...'
+func (l *ddlLexer) scanQuoted(src string, quote byte, backslash bool) (int, error) {
+	for i := 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(src) && src[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+
+	if quote == '"' {
+		return 0, NewErrDDLSyntax(l.pos(), "unterminated quoted identifier")
+	}
+
+	return 0, NewErrDDLSyntax(l.pos(), "unterminated quoted string")
+}
+
+// dollarTag return opening tag of dollar-quoted string ('$$' or '$tag$') or empty string
+func dollarTag(src string) string {
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '$':
+			return src[:i+1]
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+
+	return ""
+}
+
+func scanNumber(src string) int {
+	n := 0
+	for n < len(src) && (src[n] >= '0' && src[n] <= '9' || src[n] == '.') {
+		// '..' isn't part of number
+		if src[n] == '.' && n+1 < len(src) && src[n+1] == '.' {
+			return n
+		}
+		n++
+	}
+
+	if n < len(src) && (src[n] == 'e' || src[n] == 'E') {
+		m := n + 1
+		if m < len(src) && (src[m] == '+' || src[m] == '-') {
+			m++
+		}
+		if m < len(src) && src[m] >= '0' && src[m] <= '9' {
+			for n = m; n < len(src) && src[n] >= '0' && src[n] <= '9'; n++ {
+			}
+		}
+	}
+
+	return n
+}
+
+func isOperatorChar(c byte) bool {
+	return strings.IndexByte("+-*/<>=~!@#%^&|`?:.[]", c) >= 0
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// StatementDDL is one statement of DDL with its tokens (without finishing semicolon)
+type StatementDDL struct {
+	Text   string
+	Pos    Position
+	Tokens []Token
+}
+
+// Command reports whether statement starts with keywords
+func (s StatementDDL) Command(keywords ...string) bool {
+	if len(s.Tokens) < len(keywords) {
+		return false
+	}
+
+	for i, keyword := range keywords {
+		if !s.Tokens[i].Is(keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SplitStatementsDDL splits DDL into statements by semicolons outside strings, dollar-quoted bodies & comments
+func SplitStatementsDDL(ddl string) ([]StatementDDL, error) {
+	tokens, err := TokenizeDDL(ddl)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]StatementDDL, 0)
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && tokens[i].Kind != TokenSemicolon {
+			continue
+		}
+
+		if i > start {
+			first, last := tokens[start], tokens[i-1]
+			statements = append(statements, StatementDDL{
+				Text:   ddl[first.Pos.Offset:last.End()],
+				Pos:    first.Pos,
+				Tokens: tokens[start:i],
+			})
+		}
+		start = i + 1
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeDDL(t *testing.T) {
+	tokens, err := TokenizeDDL(`select "Name", E'it\'s', 'a''b', $$;$$, 1.5e3, x::text -- comment
+/* outer /* nested */ comment */ from t;`)
+	require.NoError(t, err)
+
+	texts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		texts[i] = tok.Text
+	}
+	assert.Equal(t, []string{"select", `"Name"`, ",", `E'it\'s'`, ",", "'a''b'", ",", "$$;$$", ",", "1.5e3", ",",
+		"x", "::", "text", "from", "t", ";"}, texts)
+
+	assert.Equal(t, TokenQuotedIdent, tokens[1].Kind)
+	assert.Equal(t, "Name", tokens[1].Name())
+	assert.Equal(t, "it's", tokens[3].StringValue())
+	assert.Equal(t, "a'b", tokens[5].StringValue())
+	assert.Equal(t, ";", tokens[7].StringValue())
+	assert.Equal(t, TokenNumber, tokens[9].Kind)
+	assert.Equal(t, Position{Offset: 99, Line: 2, Column: 34}, tokens[14].Pos)
+}
+
+func TestTokenizeDDL_errors(t *testing.T) {
+	tests := []struct {
+		ddl  string
+		want ErrDDLSyntax
+	}{
+		{"select 1;\nselect 'abc", ErrDDLSyntax{Line: 2, Column: 8, Msg: "unterminated quoted string"}},
+		{"create function f() as $body$ begin", ErrDDLSyntax{Line: 1, Column: 24, Msg: "unterminated dollar-quoted string"}},
+		{"comment on table \"t is ''", ErrDDLSyntax{Line: 1, Column: 18, Msg: "unterminated quoted identifier"}},
+		{"select 1 /* /* */", ErrDDLSyntax{Line: 1, Column: 10, Msg: "unterminated comment"}},
+	}
+	for _, tt := range tests {
+		_, err := TokenizeDDL(tt.ddl)
+		assert.Equal(t, &tt.want, err, tt.ddl)
+	}
+}
+
+func TestSplitStatementsDDL(t *testing.T) {
+	statements, err := SplitStatementsDDL(`-- header
+create table t (name text default ';');
+
+create function f() returns int as $$
+begin
+	return 1;
+end;
+$$ language plpgsql;;
+comment on table t is 'a; b'`)
+	require.NoError(t, err)
+	require.Len(t, statements, 3)
+
+	assert.Equal(t, "create table t (name text default ';')", statements[0].Text)
+	assert.Equal(t, Position{Offset: 10, Line: 2, Column: 1}, statements[0].Pos)
+	assert.Equal(t, "create function f() returns int as $$\nbegin\n\treturn 1;\nend;\n$$ language plpgsql",
+		statements[1].Text)
+	assert.Equal(t, 4, statements[1].Pos.Line)
+	assert.True(t, statements[1].Command("create", "function"))
+	assert.Equal(t, "comment on table t is 'a; b'", statements[2].Text)
+	assert.Equal(t, 9, statements[2].Pos.Line)
+}
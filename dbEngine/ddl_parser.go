@@ -0,0 +1,1366 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// StatementAST is parsed statement of DDL
+type StatementAST interface {
+	Source() StatementDDL
+}
+
+// Source return statement which AST was parsed from
+func (s StatementDDL) Source() StatementDDL {
+	return s
+}
+
+// CreateTableStmt is AST of statement 'create table'
+type CreateTableStmt struct {
+	StatementDDL
+	Name        string
+	IfNotExists bool
+	// name of parent table of partition
+	PartitionOf string
+	// parent tables of clause 'INHERITS' & tables of elements 'LIKE'
+	Inherits []string
+	Like     []string
+	Columns  []ColumnDDL
+	// constraints of table with definitions except foreign keys
+	Constraints Constraints
+	// constraints declared in definitions of columns, they haven't definitions
+	ColumnConstraints Constraints
+	// foreign keys of table & columns
+	ForeignKeys ForeignKeys
+	// source definitions of Columns
+	columnsDDL []elementDDL
+}
+
+// elementDDL is source of element of list in DDL
+type elementDDL struct {
+	text string
+	pos  Position
+}
+
+// ColumnDDL return source definition of column 'i' (with name) & its position
+func (s *CreateTableStmt) ColumnDDL(i int) (string, Position) {
+	return s.columnsDDL[i].text, s.columnsDDL[i].pos
+}
+
+// CreateIndexStmt is AST of statement 'create index'
+type CreateIndexStmt struct {
+	StatementDDL
+	Name         string
+	Table        string
+	Method       string
+	Where        string
+	Unique       bool
+	Concurrently bool
+	IfNotExists  bool
+	// columns & expressions of index as they are written in DDL
+	Items []string
+}
+
+// CreateViewStmt is AST of statement 'create view' or 'create materialized view'
+type CreateViewStmt struct {
+	StatementDDL
+	Name         string
+	OrReplace    bool
+	Materialized bool
+	IfNotExists  bool
+	Columns      []string
+	// query of view without clause 'WITH [NO] DATA'
+	Query  string
+	NoData bool
+}
+
+// CreateTypeStmt is AST of statement 'create type', it has Attributes of composite type or Enumerates of enum
+type CreateTypeStmt struct {
+	StatementDDL
+	Name       string
+	Attributes []ColumnDDL
+	// values of enum without quotes
+	Enumerates []string
+}
+
+// CreateRoutineStmt is AST of statements 'create function' & 'create procedure', Object is kind of routine
+type CreateRoutineStmt struct {
+	StatementDDL
+	Object    string
+	Name      string
+	OrReplace bool
+	Args      []RoutineArgDDL
+	// type of result without 'SETOF', empty for procedures & 'RETURNS TABLE' (its columns are Args)
+	Returns string
+	SetOf   bool
+}
+
+// RoutineArgDDL is argument of routine, Mode is 'in', 'out', 'inout', 'variadic' or 'table' (column of 'RETURNS TABLE')
+type RoutineArgDDL struct {
+	Mode    string
+	Name    string
+	Type    string
+	Default string
+}
+
+// Signature return name of routine with types of its input arguments, e.g. 'fnc(integer, text)'
+func (s *CreateRoutineStmt) Signature() string {
+	types := make([]string, 0, len(s.Args))
+	for _, arg := range s.Args {
+		if arg.Mode != "out" && arg.Mode != "table" {
+			types = append(types, arg.Type)
+		}
+	}
+
+	return s.Name + "(" + strings.Join(types, ", ") + ")"
+}
+
+// CreateTriggerStmt is AST of statement 'create trigger', Timing & Events are in upper case
+type CreateTriggerStmt struct {
+	StatementDDL
+	Name       string
+	OrReplace  bool
+	Constraint bool
+	Timing     string
+	Events     []string
+	// columns of event 'UPDATE OF'
+	Columns    []string
+	Table      string
+	ForEachRow bool
+	// condition of clause 'WHEN' without brackets
+	When     string
+	Function string
+	// arguments of function as they are written in DDL
+	Args []string
+}
+
+// AlterStmt is AST of statement 'alter', Object is kind of altered object ('table', 'materialized view' etc.)
+type AlterStmt struct {
+	StatementDDL
+	Object   string
+	Name     string
+	IfExists bool
+	Actions  []AlterActionDDL
+}
+
+// AlterActionDDL is one action of statement 'alter', Constraint or ForeignKey is set if action adds it
+type AlterActionDDL struct {
+	Text       string
+	Constraint *Constraint
+	ForeignKey *ForeignKey
+}
+
+// CommentStmt is AST of statement 'comment on',
+// Table is table of column, constraint or trigger & is equal Name for relations
+type CommentStmt struct {
+	StatementDDL
+	Object  string
+	Name    string
+	Table   string
+	Column  string
+	Comment string
+	IsNull  bool
+}
+
+// GrantStmt is AST of statements 'grant' & 'revoke'
+type GrantStmt struct {
+	StatementDDL
+	Revoke     bool
+	Privileges []string
+	// objects of privileges as they are written in DDL, empty for granting roles
+	Object string
+	Roles  []string
+}
+
+// InsertStmt is AST of statement 'insert'
+type InsertStmt struct {
+	StatementDDL
+	Table      string
+	Columns    []string
+	OnConflict bool
+}
+
+// RawStmt is statement which isn't parsed into AST (updates, extensions, drops etc.)
+type RawStmt struct {
+	StatementDDL
+}
+
+// ParseDDL splits DDL into statements & parses them, it stops on first syntax error
+func ParseDDL(ddl string) ([]StatementAST, error) {
+	statements, err := SplitStatementsDDL(ddl)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]StatementAST, 0, len(statements))
+	for _, stmt := range statements {
+		ast, err := ParseStatementDDL(stmt)
+		if err != nil {
+			return list, err
+		}
+
+		list = append(list, ast)
+	}
+
+	return list, nil
+}
+
+// ParseStatementDDL return AST of statement, unknown statements are returned as RawStmt
+func ParseStatementDDL(stmt StatementDDL) (StatementAST, error) {
+	p := &ddlParser{StatementDDL: stmt}
+	switch {
+	case p.accept("create"):
+		orReplace := p.accept("or", "replace")
+		for p.accept("temp") || p.accept("temporary") || p.accept("unlogged") ||
+			p.accept("global") || p.accept("local") {
+		}
+
+		switch {
+		case p.accept("table"):
+			return p.createTable()
+		case p.accept("unique", "index"):
+			return p.createIndex(true)
+		case p.accept("index"):
+			return p.createIndex(false)
+		case p.accept("materialized", "view"):
+			return p.createView(orReplace, true)
+		case p.accept("recursive", "view"), p.accept("view"):
+			return p.createView(orReplace, false)
+		case p.accept("type"):
+			return p.createType()
+		case p.accept("function"):
+			return p.createRoutine("function", orReplace)
+		case p.accept("procedure"):
+			return p.createRoutine("procedure", orReplace)
+		case p.accept("trigger"):
+			return p.createTrigger(orReplace, false)
+		case p.accept("constraint", "trigger"):
+			return p.createTrigger(orReplace, true)
+		}
+
+	case p.accept("alter"):
+		return p.alter()
+
+	case p.accept("comment", "on"):
+		return p.comment()
+
+	case p.peek(0).Is("grant", "revoke"):
+		return p.grant()
+
+	case p.accept("insert", "into"):
+		return p.insert()
+	}
+
+	return &RawStmt{StatementDDL: stmt}, nil
+}
+
+// ddlParser parses tokens of statement, i is index of current token
+type ddlParser struct {
+	StatementDDL
+	i int
+}
+
+// sub return parser of part of tokens of statement
+func (p *ddlParser) sub(tokens []Token) *ddlParser {
+	return &ddlParser{StatementDDL: StatementDDL{Text: p.Text, Pos: p.Pos, Tokens: tokens}}
+}
+
+func (p *ddlParser) peek(n int) Token {
+	if p.i+n < len(p.Tokens) {
+		return p.Tokens[p.i+n]
+	}
+
+	return Token{Kind: TokenEOF, Pos: p.endPos()}
+}
+
+// endPos return position after last token
+func (p *ddlParser) endPos() Position {
+	if len(p.Tokens) == 0 {
+		return p.Pos
+	}
+
+	last := p.Tokens[len(p.Tokens)-1]
+	pos := last.Pos
+	pos.Offset = last.End()
+	if i := strings.LastIndexByte(last.Text, '\n'); i >= 0 {
+		pos.Line += strings.Count(last.Text, "\n")
+		pos.Column = utf8.RuneCountInString(last.Text[i+1:]) + 1
+	} else {
+		pos.Column += utf8.RuneCountInString(last.Text)
+	}
+
+	return pos
+}
+
+func (p *ddlParser) next() Token {
+	tok := p.peek(0)
+	if tok.Kind != TokenEOF {
+		p.i++
+	}
+
+	return tok
+}
+
+// rest return tokens which aren't parsed yet
+func (p *ddlParser) rest() []Token {
+	tokens := p.Tokens[p.i:]
+	p.i = len(p.Tokens)
+
+	return tokens
+}
+
+// accept skips sequence of keywords if current tokens are them
+func (p *ddlParser) accept(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if !p.peek(i).Is(keyword) {
+			return false
+		}
+	}
+	p.i += len(keywords)
+
+	return true
+}
+
+func (p *ddlParser) expect(keywords ...string) error {
+	if p.accept(keywords...) {
+		return nil
+	}
+
+	return p.errorf(p.peek(0), "expected '%s'", strings.Join(keywords, " "))
+}
+
+// expectEnd return error if statement has tokens which aren't parsed
+func (p *ddlParser) expectEnd() error {
+	if tok := p.peek(0); tok.Kind != TokenEOF {
+		return p.errorf(tok, "expected end of statement")
+	}
+
+	return nil
+}
+
+func (p *ddlParser) errorf(tok Token, format string, args ...any) *ErrDDLSyntax {
+	found := "end of statement"
+	if tok.Kind != TokenEOF {
+		found = "'" + tok.Text + "'"
+	}
+
+	return NewErrDDLSyntax(tok.Pos, fmt.Sprintf(format, args...)+", found "+found)
+}
+
+// text return source text of tokens
+func (p *ddlParser) text(tokens []Token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	return p.Text[tokens[0].Pos.Offset-p.Pos.Offset : tokens[len(tokens)-1].End()-p.Pos.Offset]
+}
+
+// name return name of object without schema
+func (p *ddlParser) name() (string, error) {
+	tok := p.next()
+	if !tok.IsName() {
+		return "", p.errorf(tok, "expected name")
+	}
+
+	name := tok.Name()
+	for p.peek(0).Kind == TokenOperator && p.peek(0).Text == "." && p.peek(1).IsName() {
+		p.next()
+		name = p.next().Name()
+	}
+
+	return name, nil
+}
+
+// list return items of list in brackets, items are separated by commas
+func (p *ddlParser) list() ([][]Token, error) {
+	open := p.next()
+	if open.Kind != TokenLParen {
+		return nil, p.errorf(open, "expected '('")
+	}
+
+	items := make([][]Token, 0)
+	start, depth := p.i, 0
+	for {
+		tok := p.next()
+		switch {
+		case tok.Kind == TokenEOF:
+			return nil, p.errorf(open, "bracket isn't closed")
+		case tok.Kind == TokenLParen:
+			depth++
+		case tok.Kind == TokenRParen && depth > 0:
+			depth--
+		case depth > 0:
+		case tok.Kind == TokenComma, tok.Kind == TokenRParen:
+			item := p.Tokens[start : p.i-1]
+			if len(item) == 0 && (tok.Kind == TokenComma || len(items) > 0) {
+				return nil, p.errorf(tok, "expected item of list")
+			}
+			if len(item) > 0 {
+				items = append(items, item)
+			}
+			if tok.Kind == TokenRParen {
+				return items, nil
+			}
+			start = p.i
+		}
+	}
+}
+
+// names return names of list in brackets
+func (p *ddlParser) names() ([]string, error) {
+	items, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		if len(item) != 1 || !item[0].IsName() {
+			return nil, p.errorf(item[0], "expected name of column")
+		}
+		names[i] = item[0].Name()
+	}
+
+	return names, nil
+}
+
+// keywordIndex return index of first token (starting from 'from') outside brackets which is one of keywords,
+// len(tokens) if there isn't such token
+func keywordIndex(tokens []Token, from int, keywords ...string) int {
+	depth := 0
+	for i := from; i < len(tokens); i++ {
+		switch tok := tokens[i]; {
+		case tok.Kind == TokenLParen:
+			depth++
+		case tok.Kind == TokenRParen:
+			depth--
+		case depth == 0 && tok.Is(keywords...):
+			return i
+		}
+	}
+
+	return len(tokens)
+}
+
+// splitTokens splits tokens by commas outside brackets, empty parts are skipped
+func splitTokens(tokens []Token) [][]Token {
+	parts := make([][]Token, 0)
+	start, depth := 0, 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) {
+			switch tokens[i].Kind {
+			case TokenLParen:
+				depth++
+				continue
+			case TokenRParen:
+				depth--
+				continue
+			case TokenComma:
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if i > start {
+			parts = append(parts, tokens[start:i])
+		}
+		start = i + 1
+	}
+
+	return parts
+}
+
+func (p *ddlParser) createTable() (StatementAST, error) {
+	s := &CreateTableStmt{StatementDDL: p.StatementDDL, IfNotExists: p.accept("if", "not", "exists")}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	s.Name = name
+
+	switch {
+	case p.accept("partition", "of"):
+		s.PartitionOf, err = p.name()
+		return s, err
+	// tables created by query or of composite type haven't own columns
+	case p.peek(0).Is("as", "of"):
+		return s, nil
+	}
+
+	elements, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, element := range elements {
+		if err := s.addElement(p, element); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.accept("inherits") {
+		parents, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parent := range parents {
+			name, err := p.sub(parent).name()
+			if err != nil {
+				return nil, err
+			}
+			s.Inherits = append(s.Inherits, name)
+		}
+	}
+
+	return s, nil
+}
+
+// addElement adds column or constraint of table
+func (s *CreateTableStmt) addElement(p *ddlParser, tokens []Token) error {
+	switch first := tokens[0]; {
+	case first.Is("constraint"):
+		if len(tokens) < 3 || !tokens[1].IsName() {
+			return p.errorf(tokens[min(1, len(tokens)-1)], "expected name of constraint")
+		}
+		return s.addConstraint(p, tokens[1].Name(), tokens[2:])
+
+	case first.Is("primary", "unique", "check", "exclude", "foreign"):
+		return s.addConstraint(p, "", tokens)
+
+	case first.Is("like"):
+		name, err := p.sub(tokens[1:]).name()
+		if err != nil {
+			return err
+		}
+		s.Like = append(s.Like, name)
+
+		return nil
+
+	case first.IsName():
+		return s.addColumn(p, tokens)
+
+	default:
+		return p.errorf(first, "expected definition of column")
+	}
+}
+
+func (s *CreateTableStmt) addConstraint(p *ddlParser, name string, tokens []Token) error {
+	c, fk, err := p.sub(tokens).constraint(name, []string{})
+	switch {
+	case err != nil:
+		return err
+	case fk != nil:
+		s.ForeignKeys = append(s.ForeignKeys, fk)
+	default:
+		s.Constraints = append(s.Constraints, c)
+	}
+
+	return nil
+}
+
+// constraint parses constraint of table, foreign key is returned separately,
+// columns are columns of foreign key declared in definition of column
+func (p *ddlParser) constraint(name string, columns []string) (c *Constraint, fk *ForeignKey, err error) {
+	c = &Constraint{Name: name, Definition: p.text(p.Tokens)}
+	switch {
+	case p.accept("primary", "key"):
+		c.Type = ConstraintPrimary
+		c.Columns, err = p.names()
+
+	case p.accept("unique"):
+		c.Type = ConstraintUnique
+		_ = p.accept("nulls", "not", "distinct") || p.accept("nulls", "distinct")
+		c.Columns, err = p.names()
+
+	case p.accept("check"):
+		c.Type = ConstraintCheck
+		_, err = p.list()
+
+	case p.accept("exclude"):
+		c.Type = ConstraintExclude
+		p.rest()
+
+	case p.accept("foreign", "key"):
+		if columns, err = p.names(); err != nil {
+			return nil, nil, err
+		}
+		if err = p.expect("references"); err != nil {
+			return nil, nil, err
+		}
+		fk, err = p.references(name, columns)
+		return nil, fk, err
+
+	default:
+		return nil, nil, p.errorf(p.peek(0), "expected constraint")
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// clauses of index of constraint & deferring
+	p.rest()
+
+	return c, nil, nil
+}
+
+// references parses clause 'REFERENCES' of foreign key after keyword
+func (p *ddlParser) references(name string, columns []string) (*ForeignKey, error) {
+	fk := &ForeignKey{
+		Name:       name,
+		Columns:    columns,
+		UpdateRule: "NO ACTION",
+		DeleteRule: "NO ACTION",
+		MatchType:  "SIMPLE",
+	}
+
+	parent, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	fk.Parent = parent
+
+	if p.peek(0).Kind == TokenLParen {
+		if fk.ParentColumns, err = p.names(); err != nil {
+			return nil, err
+		}
+	}
+
+	for p.peek(0).Kind != TokenEOF {
+		switch {
+		case p.accept("match"):
+			fk.MatchType = strings.ToUpper(p.next().Text)
+		case p.accept("on", "update"):
+			fk.UpdateRule, err = p.referenceAction()
+		case p.accept("on", "delete"):
+			fk.DeleteRule, err = p.referenceAction()
+		case p.accept("deferrable"):
+			fk.Deferrable = true
+		case p.accept("not", "deferrable"), p.accept("initially", "immediate"):
+		case p.accept("initially", "deferred"):
+			fk.InitiallyDeferred = true
+		default:
+			return nil, p.errorf(p.peek(0), "unexpected clause of foreign key")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(columns) == 1 && len(fk.ParentColumns) == 1 {
+		fk.Column = fk.ParentColumns[0]
+	}
+
+	return fk, nil
+}
+
+// referenceAction return action of foreign key in upper case as DB reports it
+func (p *ddlParser) referenceAction() (string, error) {
+	var action string
+	switch {
+	case p.accept("cascade"):
+		action = "CASCADE"
+	case p.accept("restrict"):
+		action = "RESTRICT"
+	case p.accept("no", "action"):
+		action = "NO ACTION"
+	case p.accept("set", "null"):
+		action = "SET NULL"
+	case p.accept("set", "default"):
+		action = "SET DEFAULT"
+	default:
+		return "", p.errorf(p.peek(0), "expected action of foreign key")
+	}
+
+	// columns of actions 'SET NULL' & 'SET DEFAULT'
+	if p.peek(0).Kind == TokenLParen {
+		if _, err := p.list(); err != nil {
+			return "", err
+		}
+	}
+
+	return action, nil
+}
+
+// addColumn parses definition of column with its constraints
+func (s *CreateTableStmt) addColumn(p *ddlParser, tokens []Token) error {
+	col := ColumnDDL{Name: tokens[0].Name(), Define: p.text(tokens[1:])}
+	define := tokens[1:]
+	typeEnd := keywordIndex(define, 0, ddlTypeEnd...)
+	if typeEnd == 0 {
+		return p.errorf(p.sub(tokens).peek(1), "expected type of column '%s'", col.Name)
+	}
+	col.Type = strings.Join(strings.Fields(strings.ToLower(p.text(define[:typeEnd]))), " ")
+
+	name, depth := "", 0
+	for i := typeEnd; i < len(define); i++ {
+		tok, next := define[i], Token{}
+		if i+1 < len(define) {
+			next = define[i+1]
+		}
+
+		switch {
+		case tok.Kind == TokenLParen:
+			depth++
+			continue
+		case tok.Kind == TokenRParen:
+			depth--
+			continue
+		case depth > 0:
+			continue
+		case tok.Is("constraint") && next.IsName():
+			name = next.Name()
+			i++
+			continue
+		}
+
+		switch {
+		case tok.Is("not") && next.Is("null"):
+			col.NotNull = true
+			i++
+
+		case tok.Is("primary") && next.Is("key"):
+			col.Primary = true
+			s.ColumnConstraints = append(s.ColumnConstraints,
+				&Constraint{Name: name, Type: ConstraintPrimary, Columns: []string{col.Name}})
+			i++
+
+		case tok.Is("unique"):
+			col.Unique = true
+			s.ColumnConstraints = append(s.ColumnConstraints,
+				&Constraint{Name: name, Type: ConstraintUnique, Columns: []string{col.Name}})
+
+		case tok.Is("check"):
+			s.ColumnConstraints = append(s.ColumnConstraints,
+				&Constraint{Name: name, Type: ConstraintCheck, Columns: []string{col.Name}})
+
+		case tok.Is("default"):
+			end := keywordIndex(define, i+2, ddlTypeEnd...)
+			if i+1 == end {
+				return p.errorf(p.sub(define[:end]).peek(end), "expected default value of column '%s'", col.Name)
+			}
+			col.Default = p.text(define[i+1 : end])
+			i = end - 1
+
+		case tok.Is("references"):
+			end := keywordIndex(define, i+2, ddlTypeEnd...)
+			// parser of clause keeps previous tokens for position of its end
+			ref := p.sub(define[:end])
+			ref.i = i + 1
+			fk, err := ref.references(name, []string{col.Name})
+			if err != nil {
+				return err
+			}
+			s.ForeignKeys = append(s.ForeignKeys, fk)
+			i = end - 1
+		}
+		name = ""
+	}
+
+	s.Columns = append(s.Columns, col)
+	s.columnsDDL = append(s.columnsDDL, elementDDL{text: p.text(tokens), pos: tokens[0].Pos})
+
+	return nil
+}
+
+func (p *ddlParser) createIndex(unique bool) (StatementAST, error) {
+	s := &CreateIndexStmt{
+		StatementDDL: p.StatementDDL,
+		Unique:       unique,
+		Concurrently: p.accept("concurrently"),
+		IfNotExists:  p.accept("if", "not", "exists"),
+	}
+
+	var err error
+	if !p.peek(0).Is("on") {
+		if s.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.expect("on"); err != nil {
+		return nil, err
+	}
+	p.accept("only")
+	if s.Table, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	if p.accept("using") {
+		tok := p.next()
+		if !tok.IsName() {
+			return nil, p.errorf(tok, "expected method of index")
+		}
+		s.Method = tok.Name()
+	}
+
+	items, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		s.Items = append(s.Items, p.text(item))
+	}
+
+	if p.accept("include") {
+		if _, err = p.list(); err != nil {
+			return nil, err
+		}
+	}
+	_ = p.accept("nulls", "not", "distinct") || p.accept("nulls", "distinct")
+	if p.accept("with") {
+		if _, err = p.list(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("tablespace") {
+		if _, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("where") {
+		s.Where = p.text(p.rest())
+	}
+
+	return s, p.expectEnd()
+}
+
+func (p *ddlParser) createView(orReplace, materialized bool) (StatementAST, error) {
+	s := &CreateViewStmt{
+		StatementDDL: p.StatementDDL,
+		OrReplace:    orReplace,
+		Materialized: materialized,
+		IfNotExists:  p.accept("if", "not", "exists"),
+	}
+
+	var err error
+	if s.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	if p.peek(0).Kind == TokenLParen {
+		if s.Columns, err = p.names(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("using") {
+		p.next()
+	}
+	if p.accept("with") {
+		if _, err = p.list(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("tablespace") {
+		if _, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.expect("as"); err != nil {
+		return nil, err
+	}
+
+	query := p.rest()
+	switch n := len(query); {
+	case materialized && n > 3 && query[n-3].Is("with") && query[n-2].Is("no") && query[n-1].Is("data"):
+		s.NoData, query = true, query[:n-3]
+	case materialized && n > 2 && query[n-2].Is("with") && query[n-1].Is("data"):
+		query = query[:n-2]
+	}
+
+	if len(query) == 0 {
+		return nil, p.errorf(p.peek(0), "expected query of view")
+	}
+	s.Query = p.text(query)
+
+	return s, nil
+}
+
+func (p *ddlParser) createType() (StatementAST, error) {
+	s := &CreateTypeStmt{StatementDDL: p.StatementDDL}
+	var err error
+	if s.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !p.accept("as"):
+		// base or shell type
+
+	case p.accept("enum"):
+		items, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if len(item) != 1 || item[0].Kind != TokenString {
+				return nil, p.errorf(item[0], "expected value of enum")
+			}
+			s.Enumerates = append(s.Enumerates, item[0].StringValue())
+		}
+
+	case p.peek(0).Kind == TokenLParen:
+		items, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if len(item) < 2 || !item[0].IsName() {
+				return nil, p.errorf(item[0], "expected attribute of type")
+			}
+			define := p.text(item[1:])
+			s.Attributes = append(s.Attributes, ColumnDDL{
+				Name:   item[0].Name(),
+				Type:   defineType(define),
+				Define: define,
+			})
+		}
+
+	default:
+		// range or other kinds of types
+		p.rest()
+	}
+
+	return s, p.expectEnd()
+}
+
+// createRoutine parses name & arguments of routine, its body & attributes aren't parsed
+func (p *ddlParser) createRoutine(object string, orReplace bool) (StatementAST, error) {
+	s := &CreateRoutineStmt{StatementDDL: p.StatementDDL, Object: object, OrReplace: orReplace}
+	var err error
+	if s.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	items, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		arg, err := p.routineArg(item)
+		if err != nil {
+			return nil, err
+		}
+		s.Args = append(s.Args, arg)
+	}
+
+	if p.accept("returns") {
+		if err := s.returns(p); err != nil {
+			return nil, err
+		}
+	}
+	p.rest()
+
+	return s, nil
+}
+
+// routineAttributes are keywords which may follow type of result of routine
+var routineAttributes = []string{
+	"as", "language", "transform", "window", "immutable", "stable", "volatile", "not", "leakproof",
+	"called", "returns", "strict", "security", "external", "parallel", "cost", "rows", "support", "set",
+	"return", "begin",
+}
+
+// returns parses result of routine after keyword 'RETURNS'
+func (s *CreateRoutineStmt) returns(p *ddlParser) error {
+	s.SetOf = p.accept("setof")
+	if p.peek(0).Is("table") && p.peek(1).Kind == TokenLParen {
+		p.next()
+		items, err := p.list()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			arg, err := p.routineArg(item)
+			if err != nil {
+				return err
+			}
+			arg.Mode = "table"
+			s.Args = append(s.Args, arg)
+		}
+
+		return nil
+	}
+
+	tokens := p.Tokens[p.i:]
+	end := keywordIndex(tokens, 0, routineAttributes...)
+	if end == 0 {
+		return p.errorf(p.peek(0), "expected type of result")
+	}
+	s.Returns = strings.Join(strings.Fields(strings.ToLower(p.text(tokens[:end]))), " ")
+	p.i += end
+
+	return nil
+}
+
+// routineArg parses argument of routine: [mode] [name] type [{DEFAULT | =} expression]
+func (p *ddlParser) routineArg(tokens []Token) (RoutineArgDDL, error) {
+	arg := RoutineArgDDL{Mode: "in"}
+	sub := p.sub(tokens)
+	mode := func() {
+		switch {
+		case sub.accept("in", "out"):
+			arg.Mode = "inout"
+		case sub.peek(0).Is("in", "out", "inout", "variadic") && sub.peek(1).Kind != TokenEOF:
+			arg.Mode = strings.ToLower(sub.next().Text)
+		}
+	}
+
+	mode()
+	if first, second := sub.peek(0), sub.peek(1); first.IsName() && second.IsName() && !isTypeContinuation(first, second) {
+		arg.Name = sub.next().Name()
+		mode()
+	}
+
+	define := tokens[sub.i:]
+	typeEnd := keywordIndex(define, 0, "default")
+	for i, tok := range define[:typeEnd] {
+		if tok.Kind == TokenOperator && tok.Text == "=" {
+			typeEnd = i
+			break
+		}
+	}
+	if typeEnd == 0 {
+		return arg, p.errorf(sub.peek(0), "expected type of argument")
+	}
+	arg.Type = strings.Join(strings.Fields(strings.ToLower(p.text(define[:typeEnd]))), " ")
+
+	if typeEnd < len(define) {
+		if typeEnd+1 == len(define) {
+			return arg, p.errorf(p.sub(define).peek(len(define)), "expected default value of argument '%s'", arg.Name)
+		}
+		arg.Default = p.text(define[typeEnd+1:])
+	}
+
+	return arg, nil
+}
+
+// isTypeContinuation reports whether tokens are beginning of type of several words ('double precision' etc.)
+func isTypeContinuation(first, second Token) bool {
+	return first.Is("double", "national") ||
+		second.Is("precision", "varying", "with", "without") ||
+		first.Is("interval") && second.Is("year", "month", "day", "hour", "minute", "second")
+}
+
+func (p *ddlParser) createTrigger(orReplace, constraint bool) (StatementAST, error) {
+	s := &CreateTriggerStmt{StatementDDL: p.StatementDDL, OrReplace: orReplace, Constraint: constraint}
+	var err error
+	if s.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.accept("before"):
+		s.Timing = "BEFORE"
+	case p.accept("after"):
+		s.Timing = "AFTER"
+	case p.accept("instead", "of"):
+		s.Timing = "INSTEAD OF"
+	default:
+		return nil, p.errorf(p.peek(0), "expected time of trigger")
+	}
+
+	for {
+		tok := p.next()
+		if !tok.Is("insert", "update", "delete", "truncate") {
+			return nil, p.errorf(tok, "expected event of trigger")
+		}
+		s.Events = append(s.Events, strings.ToUpper(tok.Text))
+
+		if tok.Is("update") && p.accept("of") {
+			for {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				s.Columns = append(s.Columns, name)
+
+				if p.peek(0).Kind != TokenComma {
+					break
+				}
+				p.next()
+			}
+		}
+
+		if !p.accept("or") {
+			break
+		}
+	}
+
+	if err = p.expect("on"); err != nil {
+		return nil, err
+	}
+	if s.Table, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	for !p.accept("execute") {
+		switch {
+		case p.accept("from"):
+			if _, err = p.name(); err != nil {
+				return nil, err
+			}
+
+		case p.accept("not", "deferrable"), p.accept("deferrable"),
+			p.accept("initially", "immediate"), p.accept("initially", "deferred"):
+
+		case p.accept("referencing"):
+			for p.peek(0).Is("old", "new") {
+				p.next()
+				p.accept("table")
+				p.accept("as")
+				if _, err = p.name(); err != nil {
+					return nil, err
+				}
+			}
+
+		case p.accept("for"):
+			p.accept("each")
+			switch {
+			case p.accept("row"):
+				s.ForEachRow = true
+			case p.accept("statement"):
+			default:
+				return nil, p.errorf(p.peek(0), "expected 'row' or 'statement'")
+			}
+
+		case p.accept("when"):
+			start := p.i
+			if _, err = p.list(); err != nil {
+				return nil, err
+			}
+			s.When = p.text(p.Tokens[start+1 : p.i-1])
+
+		default:
+			return nil, p.errorf(p.peek(0), "expected 'execute'")
+		}
+	}
+
+	if !p.accept("function") && !p.accept("procedure") {
+		return nil, p.errorf(p.peek(0), "expected 'function'")
+	}
+	if s.Function, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	args, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		s.Args = append(s.Args, p.text(arg))
+	}
+
+	return s, p.expectEnd()
+}
+
+func (p *ddlParser) alter() (StatementAST, error) {
+	s := &AlterStmt{StatementDDL: p.StatementDDL}
+	switch {
+	case p.accept("materialized", "view"):
+		s.Object = "materialized view"
+	case p.accept("foreign", "table"):
+		s.Object = "foreign table"
+	default:
+		tok := p.next()
+		if tok.Kind != TokenIdent {
+			return nil, p.errorf(tok, "expected kind of object")
+		}
+		s.Object = strings.ToLower(tok.Text)
+	}
+
+	s.IfExists = p.accept("if", "exists")
+	p.accept("only")
+
+	var err error
+	if s.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	// arguments of routines
+	if p.peek(0).Kind == TokenLParen {
+		if _, err = p.list(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tokens := range splitTokens(p.rest()) {
+		action := AlterActionDDL{Text: p.text(tokens)}
+		sub := p.sub(tokens)
+		if sub.accept("add") {
+			name := ""
+			if sub.accept("constraint") {
+				if name, err = sub.name(); err != nil {
+					return nil, err
+				}
+			}
+
+			if sub.peek(0).Is("primary", "unique", "check", "exclude", "foreign") {
+				constraint := sub.sub(sub.rest())
+				if action.Constraint, action.ForeignKey, err = constraint.constraint(name, []string{}); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		s.Actions = append(s.Actions, action)
+	}
+
+	return s, nil
+}
+
+func (p *ddlParser) comment() (StatementAST, error) {
+	s := &CommentStmt{StatementDDL: p.StatementDDL}
+	switch {
+	case p.accept("materialized", "view"):
+		s.Object = "materialized view"
+	case p.accept("foreign", "table"):
+		s.Object = "foreign table"
+	default:
+		tok := p.next()
+		if tok.Kind != TokenIdent {
+			return nil, p.errorf(tok, "expected kind of object")
+		}
+		s.Object = strings.ToLower(tok.Text)
+	}
+
+	parts := make([]string, 0, 3)
+	for {
+		tok := p.next()
+		if !tok.IsName() {
+			return nil, p.errorf(tok, "expected name of %s", s.Object)
+		}
+		parts = append(parts, tok.Name())
+
+		if p.peek(0).Kind != TokenOperator || p.peek(0).Text != "." {
+			break
+		}
+		p.next()
+	}
+
+	s.Name, s.Table = parts[len(parts)-1], parts[len(parts)-1]
+	switch {
+	case s.Object == "column":
+		if len(parts) < 2 {
+			return nil, p.errorf(p.peek(0), "expected name of column with its table")
+		}
+		s.Table, s.Column = parts[len(parts)-2], s.Name
+
+	case p.peek(0).Kind == TokenLParen:
+		// arguments of routines
+		if _, err := p.list(); err != nil {
+			return nil, err
+		}
+
+	case p.accept("on"):
+		// constraint, trigger, rule or policy of table
+		p.accept("domain")
+		table, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		s.Table = table
+	}
+
+	if err := p.expect("is"); err != nil {
+		return nil, err
+	}
+
+	switch tok := p.next(); {
+	case tok.Is("null"):
+		s.IsNull = true
+	case tok.Kind == TokenString:
+		s.Comment = tok.StringValue()
+	default:
+		return nil, p.errorf(tok, "expected text of comment")
+	}
+
+	return s, p.expectEnd()
+}
+
+func (p *ddlParser) grant() (StatementAST, error) {
+	s := &GrantStmt{StatementDDL: p.StatementDDL, Revoke: p.next().Is("revoke")}
+	if s.Revoke {
+		p.accept("grant", "option", "for")
+	}
+
+	target := "to"
+	if s.Revoke {
+		target = "from"
+	}
+
+	on := keywordIndex(p.Tokens, p.i, "on", target)
+	for _, privilege := range splitTokens(p.Tokens[p.i:on]) {
+		s.Privileges = append(s.Privileges, strings.ToLower(p.text(privilege)))
+	}
+	if len(s.Privileges) == 0 {
+		return nil, p.errorf(p.peek(0), "expected privileges")
+	}
+
+	p.i = on
+	if p.accept("on") {
+		end := keywordIndex(p.Tokens, p.i, target)
+		s.Object = p.text(p.Tokens[p.i:end])
+		p.i = end
+	}
+
+	if err := p.expect(target); err != nil {
+		return nil, err
+	}
+
+	end := keywordIndex(p.Tokens, p.i, "with", "granted", "cascade", "restrict")
+	for _, role := range splitTokens(p.Tokens[p.i:end]) {
+		s.Roles = append(s.Roles, p.text(role))
+	}
+	if len(s.Roles) == 0 {
+		return nil, p.errorf(p.peek(0), "expected roles")
+	}
+	p.rest()
+
+	return s, nil
+}
+
+func (p *ddlParser) insert() (StatementAST, error) {
+	s := &InsertStmt{StatementDDL: p.StatementDDL}
+	var err error
+	if s.Table, err = p.name(); err != nil {
+		return nil, err
+	}
+
+	if p.accept("as") {
+		if _, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek(0).Kind == TokenLParen && !p.peek(1).Is("select", "with", "values") {
+		if s.Columns, err = p.names(); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := keywordIndex(p.Tokens, p.i, "on"); i < len(p.Tokens); i = keywordIndex(p.Tokens, i+1, "on") {
+		if i+1 < len(p.Tokens) && p.Tokens[i+1].Is("conflict") {
+			s.OnConflict = true
+		}
+	}
+	p.rest()
+
+	return s, nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDDL_createTable(t *testing.T) {
+	list, err := ParseDDL(`create table if not exists public.orders (
+	id bigint generated always as identity primary key,
+	"Total" numeric(10, 2) not null default round((0.0)::numeric, 2), -- money
+	user_id uuid constraint orders_user_fk references users (id) on delete cascade,
+	tags text[] default '{}'::text[] check (cardinality(tags) < 10),
+	note text collate "C" default 'a, (b)',
+	unique nulls not distinct (user_id, note),
+	foreign key (tags) references tags (names) match full on update set null deferrable
+) partition by range (id)`)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	s, ok := list[0].(*CreateTableStmt)
+	require.True(t, ok)
+	assert.Equal(t, "orders", s.Name)
+	assert.True(t, s.IfNotExists)
+	assert.Equal(t, []ColumnDDL{
+		{Name: "id", Type: "bigint", Define: "bigint generated always as identity primary key", Primary: true},
+		{Name: "Total", Type: "numeric(10, 2)", Define: "numeric(10, 2) not null default round((0.0)::numeric, 2)",
+			Default: "round((0.0)::numeric, 2)", NotNull: true},
+		{Name: "user_id", Type: "uuid", Define: "uuid constraint orders_user_fk references users (id) on delete cascade"},
+		{Name: "tags", Type: "text[]", Define: "text[] default '{}'::text[] check (cardinality(tags) < 10)",
+			Default: "'{}'::text[]"},
+		{Name: "note", Type: "text", Define: `text collate "C" default 'a, (b)'`, Default: "'a, (b)'"},
+	}, s.Columns)
+
+	text, pos := s.ColumnDDL(1)
+	assert.Equal(t, `"Total" numeric(10, 2) not null default round((0.0)::numeric, 2)`, text)
+	assert.Equal(t, Position{Offset: 97, Line: 3, Column: 2}, pos)
+
+	assert.Equal(t, Constraints{
+		{Type: ConstraintUnique, Columns: []string{"user_id", "note"}, Definition: "unique nulls not distinct (user_id, note)"},
+	}, s.Constraints)
+	assert.Equal(t, Constraints{
+		{Type: ConstraintPrimary, Columns: []string{"id"}},
+		{Type: ConstraintCheck, Columns: []string{"tags"}},
+	}, s.ColumnConstraints)
+	assert.Equal(t, ForeignKeys{
+		{Name: "orders_user_fk", Parent: "users", Column: "id", Columns: []string{"user_id"}, ParentColumns: []string{"id"},
+			UpdateRule: "NO ACTION", DeleteRule: "CASCADE", MatchType: "SIMPLE"},
+		{Parent: "tags", Columns: []string{"tags"}, ParentColumns: []string{"names"}, Column: "names",
+			UpdateRule: "SET NULL", DeleteRule: "NO ACTION", MatchType: "FULL", Deferrable: true},
+	}, s.ForeignKeys)
+
+	list, err = ParseDDL("create table orders_2020 partition of orders for values from (1) to (100)")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", list[0].(*CreateTableStmt).PartitionOf)
+
+	list, err = ParseDDL("create table orders_archive (like public.orders including all, note text) inherits (archive, public.logs)")
+	require.NoError(t, err)
+	s = list[0].(*CreateTableStmt)
+	assert.Equal(t, []string{"orders"}, s.Like)
+	assert.Equal(t, []string{"archive", "logs"}, s.Inherits)
+}
+
+func TestParseDDL_statements(t *testing.T) {
+	list, err := ParseDDL(`create unique index concurrently if not exists orders_note_uindex on only orders using btree
+	(lower(note), (user_id::text) desc) include (id) where note > '';
+create or replace view orders_view with (security_barrier) as select id, note from orders;
+create materialized view orders_sum (id, total) as select id, sum("Total") from orders group by id with no data;
+create type status as enum ('new', 'it''s done');
+create type address as (city varchar(50), zip text[]);
+alter table orders add constraint orders_parent_fk foreign key (parent_id) references orders, alter column note drop not null;
+comment on column public.orders."Total" is 'sum of order';
+comment on constraint orders_parent_fk on orders is null;
+grant select, update (note) on table orders, orders_view to manager, "Reader" with grant option;
+revoke all on orders from public;
+insert into orders as o (id, note) values (1, 'a') on conflict (id) do nothing;
+update orders set note = ''`)
+	require.NoError(t, err)
+	require.Len(t, list, 12)
+
+	index := list[0].(*CreateIndexStmt)
+	assert.Equal(t, "orders_note_uindex", index.Name)
+	assert.Equal(t, "orders", index.Table)
+	assert.Equal(t, "btree", index.Method)
+	assert.Equal(t, "note > ''", index.Where)
+	assert.True(t, index.Unique && index.Concurrently && index.IfNotExists)
+	assert.Equal(t, []string{"lower(note)", "(user_id::text) desc"}, index.Items)
+
+	view := list[1].(*CreateViewStmt)
+	assert.Equal(t, "orders_view", view.Name)
+	assert.True(t, view.OrReplace)
+	assert.False(t, view.Materialized)
+	assert.Equal(t, "select id, note from orders", view.Query)
+
+	matView := list[2].(*CreateViewStmt)
+	assert.True(t, matView.Materialized && matView.NoData)
+	assert.Equal(t, []string{"id", "total"}, matView.Columns)
+	assert.Equal(t, `select id, sum("Total") from orders group by id`, matView.Query)
+
+	assert.Equal(t, []string{"new", "it's done"}, list[3].(*CreateTypeStmt).Enumerates)
+	assert.Equal(t, []ColumnDDL{
+		{Name: "city", Type: "varchar(50)", Define: "varchar(50)"},
+		{Name: "zip", Type: "text[]", Define: "text[]"},
+	}, list[4].(*CreateTypeStmt).Attributes)
+
+	alter := list[5].(*AlterStmt)
+	assert.Equal(t, "table", alter.Object)
+	assert.Equal(t, "orders", alter.Name)
+	require.Len(t, alter.Actions, 2)
+	assert.Equal(t, "orders_parent_fk", alter.Actions[0].ForeignKey.Name)
+	assert.Equal(t, "orders", alter.Actions[0].ForeignKey.Parent)
+	assert.Equal(t, "alter column note drop not null", alter.Actions[1].Text)
+
+	assert.Equal(t, &CommentStmt{StatementDDL: list[6].Source(), Object: "column", Name: "Total", Table: "orders",
+		Column: "Total", Comment: "sum of order"}, list[6])
+	assert.Equal(t, &CommentStmt{StatementDDL: list[7].Source(), Object: "constraint", Name: "orders_parent_fk",
+		Table: "orders", IsNull: true}, list[7])
+
+	grant := list[8].(*GrantStmt)
+	assert.Equal(t, []string{"select", "update (note)"}, grant.Privileges)
+	assert.Equal(t, "table orders, orders_view", grant.Object)
+	assert.Equal(t, []string{"manager", `"Reader"`}, grant.Roles)
+	assert.True(t, list[9].(*GrantStmt).Revoke)
+
+	insert := list[10].(*InsertStmt)
+	assert.Equal(t, "orders", insert.Table)
+	assert.Equal(t, []string{"id", "note"}, insert.Columns)
+	assert.True(t, insert.OnConflict)
+
+	assert.IsType(t, &RawStmt{}, list[11])
+}
+
+func TestParseDDL_createRoutine(t *testing.T) {
+	list, err := ParseDDL(`create or replace function public.orders_total(in user_id uuid, "From" timestamp with time zone,
+	double precision = 0.5, out total numeric(10, 2), variadic tags text[] default '{}') returns numeric as $$
+begin
+	return 1;
+end;
+$$ language plpgsql;
+create procedure clean(days interval day default '1 day'::interval, inout cnt int)
+language sql as 'delete from orders'`)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	fnc := list[0].(*CreateRoutineStmt)
+	assert.Equal(t, "function", fnc.Object)
+	assert.Equal(t, "orders_total", fnc.Name)
+	assert.True(t, fnc.OrReplace)
+	assert.Equal(t, []RoutineArgDDL{
+		{Mode: "in", Name: "user_id", Type: "uuid"},
+		{Mode: "in", Name: "From", Type: "timestamp with time zone"},
+		{Mode: "in", Type: "double precision", Default: "0.5"},
+		{Mode: "out", Name: "total", Type: "numeric(10, 2)"},
+		{Mode: "variadic", Name: "tags", Type: "text[]", Default: "'{}'"},
+	}, fnc.Args)
+	assert.Equal(t, "orders_total(uuid, timestamp with time zone, double precision, text[])", fnc.Signature())
+	assert.Equal(t, "numeric", fnc.Returns)
+
+	proc := list[1].(*CreateRoutineStmt)
+	assert.Equal(t, "procedure", proc.Object)
+	assert.False(t, proc.OrReplace)
+	assert.Equal(t, "clean(interval day, int)", proc.Signature())
+	assert.Equal(t, "'1 day'::interval", proc.Args[0].Default)
+	assert.Equal(t, "inout", proc.Args[1].Mode)
+	assert.Empty(t, proc.Returns)
+
+	list, err = ParseDDL(`create function orders_of(a address) returns setof public.orders stable as 'select 1' language sql;
+create function totals(since date) returns table (id int, "Total" double precision) language sql as 'select 1'`)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	fnc = list[0].(*CreateRoutineStmt)
+	assert.True(t, fnc.SetOf)
+	assert.Equal(t, "public.orders", fnc.Returns)
+
+	fnc = list[1].(*CreateRoutineStmt)
+	assert.Empty(t, fnc.Returns)
+	assert.Equal(t, []RoutineArgDDL{
+		{Mode: "in", Name: "since", Type: "date"},
+		{Mode: "table", Name: "id", Type: "int"},
+		{Mode: "table", Name: "Total", Type: "double precision"},
+	}, fnc.Args)
+	assert.Equal(t, "totals(date)", fnc.Signature())
+
+	_, err = ParseDDL("create function f() returns as 'select 1'")
+	assert.Error(t, err)
+}
+
+func TestParseDDL_createTrigger(t *testing.T) {
+	list, err := ParseDDL(`create or replace trigger orders_total_changed after update of total, status or insert
+	on public.orders for each row when (old.total is distinct from new.total)
+	execute function notify_orders('total', 1);
+CREATE CONSTRAINT TRIGGER orders_check AFTER DELETE ON public.orders DEFERRABLE INITIALLY DEFERRED
+	FOR EACH ROW EXECUTE PROCEDURE check_orders()`)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	tr := list[0].(*CreateTriggerStmt)
+	assert.Equal(t, "orders_total_changed", tr.Name)
+	assert.True(t, tr.OrReplace)
+	assert.False(t, tr.Constraint)
+	assert.Equal(t, "AFTER", tr.Timing)
+	assert.Equal(t, []string{"UPDATE", "INSERT"}, tr.Events)
+	assert.Equal(t, []string{"total", "status"}, tr.Columns)
+	assert.Equal(t, "orders", tr.Table)
+	assert.True(t, tr.ForEachRow)
+	assert.Equal(t, "old.total is distinct from new.total", tr.When)
+	assert.Equal(t, "notify_orders", tr.Function)
+	assert.Equal(t, []string{"'total'", "1"}, tr.Args)
+
+	tr = list[1].(*CreateTriggerStmt)
+	assert.Equal(t, "orders_check", tr.Name)
+	assert.True(t, tr.Constraint)
+	assert.Equal(t, []string{"DELETE"}, tr.Events)
+	assert.Equal(t, "check_orders", tr.Function)
+	assert.Empty(t, tr.Args)
+}
+
+func TestParseDDL_errors(t *testing.T) {
+	tests := []struct {
+		ddl  string
+		want ErrDDLSyntax
+	}{
+		{"create table t (\n\tid int,\n\t,name text)",
+			ErrDDLSyntax{Line: 3, Column: 2, Msg: "expected item of list, found ','"}},
+		{"create table t (\n\tid int default)",
+			ErrDDLSyntax{Line: 2, Column: 16, Msg: "expected default value of column 'id', found end of statement"}},
+		{"create table t (id int references)",
+			ErrDDLSyntax{Line: 1, Column: 34, Msg: "expected name, found end of statement"}},
+		{"create index on t (id",
+			ErrDDLSyntax{Line: 1, Column: 19, Msg: "bracket isn't closed, found '('"}},
+		{"select 1;\ncomment on table t is 1",
+			ErrDDLSyntax{Line: 2, Column: 23, Msg: "expected text of comment, found '1'"}},
+		{"create function f(a int default) returns int as 'select 1'",
+			ErrDDLSyntax{Line: 1, Column: 32, Msg: "expected default value of argument 'a', found end of statement"}},
+		{"create function f(a default 1) returns int as 'select 1'",
+			ErrDDLSyntax{Line: 1, Column: 21, Msg: "expected type of argument, found 'default'"}},
+		{"create trigger t before on orders execute function f()",
+			ErrDDLSyntax{Line: 1, Column: 25, Msg: "expected event of trigger, found 'on'"}},
+		{"create view v (id) select 1",
+			ErrDDLSyntax{Line: 1, Column: 20, Msg: "expected 'as', found 'select'"}},
+	}
+	for _, tt := range tests {
+		_, err := ParseDDL(tt.ddl)
+		assert.Equal(t, &tt.want, err, tt.ddl)
+	}
+}
+
+func TestDefaultDDL(t *testing.T) {
+	assert.Equal(t, "coalesce(now(), (now() - '1 day'::interval))",
+		DefaultDDL("timestamp default coalesce(now(), (now() - '1 day'::interval)) not null"))
+	assert.Equal(t, "null", DefaultDDL("text default null"))
+	assert.Equal(t, "", DefaultDDL("integer not null"))
+}
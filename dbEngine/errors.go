@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
@@ -99,6 +100,34 @@ func (err ErrWrongArgsLen) Error() string {
 	return fmt.Sprintf("Wrong argument len %d (expect %d) for table `%s` ", len(err.Args), len(err.Filter), err.Table)
 }
 
+// ErrQueryTimeout if query of table/routine {Name} was cancelled by timeout
+type ErrQueryTimeout struct {
+	Name    string
+	Timeout time.Duration
+	Err     error
+}
+
+// NewErrQueryTimeout create new error
+func NewErrQueryTimeout(name string, timeout time.Duration, err error) *ErrQueryTimeout {
+	return &ErrQueryTimeout{Name: name, Timeout: timeout, Err: err}
+}
+
+// Error implement error interface
+func (err ErrQueryTimeout) Error() string {
+	return fmt.Sprintf("query of `%s` cancelled by timeout %v: %v", err.Name, err.Timeout, err.Err)
+}
+
+// Unwrap return origin error
+func (err ErrQueryTimeout) Unwrap() error {
+	return err.Err
+}
+
+// IsErrorQueryCanceled indicates about query was canceled by statement_timeout or user request
+func IsErrorQueryCanceled(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == codeQueryCanceled
+}
+
 // IsErrorNullValues indicates about column can't add because has NOT NULL
 func IsErrorNullValues(err error) bool {
 	if err == nil {
@@ -169,6 +198,8 @@ func IsErrorForReplace(err error) bool {
 	ignoreErrors := []string{
 		"cannot change return type of existing function",
 		"cannot change name of input parameter",
+		"cannot remove parameter defaults from existing function",
+		"cannot change whether a procedure has output parameters",
 		ErrCannotAlterColumnUsedView,
 	}
 	for _, val := range ignoreErrors {
@@ -254,6 +285,21 @@ func (err ErrWrongType) Error() string {
 
 }
 
+// ErrUnsupportedCondition if condition {Condition} of WHERE clause can't be evaluated without DB
+type ErrUnsupportedCondition struct {
+	Condition string
+}
+
+// NewErrUnsupportedCondition create new error
+func NewErrUnsupportedCondition(condition string) *ErrUnsupportedCondition {
+	return &ErrUnsupportedCondition{Condition: condition}
+}
+
+// Error implement error interface
+func (err ErrUnsupportedCondition) Error() string {
+	return fmt.Sprintf("condition `%s` isn't supported without DB", err.Condition)
+}
+
 // ErrUnknownSql if {sql} is unknown for parser
 type ErrUnknownSql struct {
 	sql  string
@@ -274,6 +320,39 @@ func (err *ErrUnknownSql) Error() string {
 	return fmt.Sprintf("unknow sql `%s` for DB migration ", err.sql)
 }
 
+// ErrMigrationCycle if DDL files of migration have cyclic dependencies, every cycle consists of names of objects
+type ErrMigrationCycle struct {
+	Cycles [][]string
+}
+
+// Error implement error interface
+func (err ErrMigrationCycle) Error() string {
+	cycles := make([]string, len(err.Cycles))
+	for i, cycle := range err.Cycles {
+		cycles[i] = strings.Join(cycle, " -> ")
+	}
+
+	return fmt.Sprintf("cyclic dependencies of migration: %s", strings.Join(cycles, "; "))
+}
+
+// ErrDDLSyntax if DDL of migration can't be parsed, {File}, {Line} & {Column} point to wrong place
+type ErrDDLSyntax struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+// NewErrDDLSyntax create new error
+func NewErrDDLSyntax(pos Position, msg string) *ErrDDLSyntax {
+	return &ErrDDLSyntax{Line: pos.Line, Column: pos.Column, Msg: msg}
+}
+
+// Error implement error interface
+func (err ErrDDLSyntax) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", err.File, err.Line, err.Column, err.Msg)
+}
+
 var errWrongTableName = errors.New("wrong table name '%v' %s")
 
 func logError(err error, ddlSQL string, fileName string) {
@@ -296,6 +375,8 @@ func logError(err error, ddlSQL string, fileName string) {
 		printError(fileName, line, msg)
 	} else if e, ok := err.(*ErrUnknownSql); ok {
 		printError(fileName, e.Line, e.Msg+e.sql+": not parse this SQL")
+	} else if e, ok := err.(*ErrDDLSyntax); ok {
+		printError(fileName, e.Line, fmt.Sprintf("column %d: %s", e.Column, e.Msg))
 	} else {
 		printError(fileName, 1, err.Error())
 	}
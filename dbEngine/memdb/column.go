@@ -0,0 +1,269 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+	"github.com/ruslanBik4/dbEngine/dbEngine/psql"
+)
+
+// udtNames consists of names of types in DDL which psql stores with other udt_name
+var udtNames = map[string]string{
+	"serial":                      "int4",
+	"int":                         "int4",
+	"integer":                     "int4",
+	"smallserial":                 "int2",
+	"smallint":                    "int2",
+	"bigserial":                   "int8",
+	"bigint":                      "int8",
+	"real":                        "float4",
+	"float":                       "float8",
+	"double precision":            "float8",
+	"decimal":                     "numeric",
+	"boolean":                     "bool",
+	"character varying":           "varchar",
+	"character":                   "bpchar",
+	"char":                        "bpchar",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+}
+
+// Column implement dbEngine.Column for column of memory table, its properties are read from DDL
+type Column struct {
+	table      *Table
+	name       string
+	udtName    string
+	kind       types.BasicKind
+	isArray    bool
+	isNullable bool
+	primary    bool
+	unique     bool
+	autoInc    bool
+	maxLen     int
+	colDefault any
+	comment    string
+}
+
+// NewColumn create Column according to define of DDL
+func NewColumn(table *Table, define dbEngine.ColumnDDL) *Column {
+	col := &Column{
+		table:      table,
+		name:       define.Name,
+		isNullable: !define.NotNull,
+		primary:    define.Primary,
+		unique:     define.Unique,
+	}
+
+	typ := define.Type
+	typ, col.isArray = strings.CutSuffix(typ, "[]")
+	if name, size, ok := strings.Cut(typ, "("); ok {
+		typ = strings.TrimSpace(name)
+		if n, err := strconv.Atoi(strings.TrimSuffix(size, ")")); err == nil {
+			col.maxLen = n
+		}
+	}
+
+	col.autoInc = strings.HasSuffix(typ, "serial")
+	if udtName, ok := udtNames[typ]; ok {
+		typ = udtName
+	}
+	col.udtName = typ
+	if col.isArray {
+		col.udtName = "_" + typ
+	}
+	col.kind = psql.UdtNameToType(col.udtName, nil, nil)
+
+	if define.Default > "" {
+		col.colDefault = define.Default
+	}
+
+	return col
+}
+
+// BasicType return GoLangs type of column
+func (c *Column) BasicType() types.BasicKind {
+	return c.kind
+}
+
+// BasicTypeInfo return types.BasicInfo of column
+func (c *Column) BasicTypeInfo() types.BasicInfo {
+	switch c.kind {
+	case types.Bool:
+		return types.IsBoolean
+	case types.Int16, types.Int32, types.Int64:
+		return types.IsInteger
+	case types.Float32, types.Float64:
+		return types.IsFloat
+	case types.String:
+		return types.IsString
+	default:
+		return types.IsUntyped
+	}
+}
+
+// CheckAttr check attributes of column on DB schema according to ddl-file
+func (c *Column) CheckAttr(fieldDefine string) []dbEngine.FlagColumn {
+	return nil
+}
+
+// CharacterMaximumLength return max of length text columns
+func (c *Column) CharacterMaximumLength() int {
+	return c.maxLen
+}
+
+// Comment of column
+func (c *Column) Comment() string {
+	return c.comment
+}
+
+// Name of column
+func (c *Column) Name() string {
+	return c.name
+}
+
+// AutoIncrement return true if column is autoincrement
+func (c *Column) AutoIncrement() bool {
+	return c.autoInc
+}
+
+// IsArray of column
+func (c *Column) IsArray() bool {
+	return c.isArray
+}
+
+// IsNullable return isNullable flag
+func (c *Column) IsNullable() bool {
+	return c.isNullable
+}
+
+// Default return default value of column as in DDL
+func (c *Column) Default() any {
+	return c.colDefault
+}
+
+// SetDefault set default value into column
+func (c *Column) SetDefault(d any) {
+	c.colDefault = d
+}
+
+// Foreign return foreign key of column
+func (c *Column) Foreign() *dbEngine.ForeignKey {
+	return nil
+}
+
+// UserDefinedType return user defined type of column
+func (c *Column) UserDefinedType() *dbEngine.Types {
+	return nil
+}
+
+// Table of column
+func (c *Column) Table() dbEngine.Table {
+	return c.table
+}
+
+// Primary return true if column is primary key
+func (c *Column) Primary() bool {
+	return c.primary
+}
+
+// Type return udt_name of column same as psql
+func (c *Column) Type() string {
+	return c.udtName
+}
+
+// Required return true if column must have value
+func (c *Column) Required() bool {
+	return !c.isNullable && c.colDefault == nil && !c.autoInc
+}
+
+// SetNullable set nullable flag of column
+func (c *Column) SetNullable(f bool) {
+	c.isNullable = f
+}
+
+// defaultValue return value of column for new row
+func (c *Column) defaultValue() (any, error) {
+	def, ok := c.colDefault.(string)
+	if !ok {
+		return c.normalize(c.colDefault)
+	}
+
+	def, _, _ = strings.Cut(def, "::")
+	switch lower := strings.ToLower(strings.TrimSpace(def)); {
+	case lower == "" || lower == "null":
+		return nil, nil
+	case lower == "now" || lower == "current_timestamp" || lower == "localtimestamp":
+		return c.normalize(time.Now())
+	case lower == "current_date":
+		return c.normalize(time.Now().Truncate(24 * time.Hour))
+	case strings.HasPrefix(lower, "'"):
+		return c.normalize(strings.ReplaceAll(strings.Trim(def, "'"), "''", "'"))
+	case strings.ContainsAny(lower, "()"):
+		// functions are unknown for memory table
+		return nil, nil
+	default:
+		return c.normalize(def)
+	}
+}
+
+// normalize converts value of argument into type of column, arrays & composite types are stored as is
+func (c *Column) normalize(arg any) (any, error) {
+	if arg == nil || c.isArray {
+		return arg, nil
+	}
+
+	var err error
+	switch c.udtName {
+	case "bool":
+		var v bool
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "int2":
+		var v int16
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "int4":
+		var v int32
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "int8":
+		var v int64
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "float4":
+		var v float32
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "float8", "numeric", "money":
+		var v float64
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "date", "timestamp", "timestamptz":
+		var v time.Time
+		err = dbEngine.ScanValue(&v, arg)
+		arg = v
+	case "varchar", "bpchar", "text", "citext", "uuid":
+		var v string
+		err = dbEngine.ScanValue(&v, arg)
+		if err == nil && c.maxLen > 0 && len([]rune(v)) > c.maxLen {
+			return nil, newPgError(codeStringTooLong, c.table.name,
+				"value too long for type character varying(%d)", c.maxLen)
+		}
+		arg = v
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, c.name)
+	}
+
+	return arg, nil
+}
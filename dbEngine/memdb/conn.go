@@ -0,0 +1,249 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package memdb implements dbEngine.Connection which stores tables in memory,
+// schema is read from DDL files, so repository code may be tested without PostgreSQL
+package memdb
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// Conn implement dbEngine.Connection for tables in memory
+type Conn struct {
+	tables          map[string]*Table
+	lock            sync.RWMutex
+	lastRowAffected atomic.Int64
+}
+
+// NewConn create empty memory DB
+func NewConn() *Conn {
+	return &Conn{tables: make(map[string]*Table)}
+}
+
+// InitConn reads schema from file or directory (recursively) of .ddl files, empty dbURL means empty DB
+func (c *Conn) InitConn(ctx context.Context, dbURL string) error {
+	if c.tables == nil {
+		c.tables = make(map[string]*Table)
+	}
+
+	if dbURL == "" {
+		return nil
+	}
+
+	return filepath.WalkDir(dbURL, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(path) != ".ddl" {
+			return nil
+		}
+
+		ddl, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return errors.Wrap(c.ExecDDL(ctx, string(ddl)), path)
+	})
+}
+
+// Ping always success
+func (c *Conn) Ping(ctx context.Context) error {
+	return nil
+}
+
+// GetRoutines return empty map, memory DB hasn't routines
+func (c *Conn) GetRoutines(ctx context.Context, dbTypes map[string]dbEngine.Types, tables map[string]dbEngine.Table,
+	cfg *dbEngine.CfgDB) (map[string]dbEngine.Routine, error) {
+	return make(map[string]dbEngine.Routine), nil
+}
+
+// GetSchema return tables of memory DB
+func (c *Conn) GetSchema(ctx context.Context, cfg *dbEngine.CfgDB) (database map[string]*string, tables map[string]dbEngine.Table,
+	routines map[string]dbEngine.Routine, dbTypes map[string]dbEngine.Types, err error) {
+	name, schema := "memdb", "public"
+	database = map[string]*string{
+		"db_name":   &name,
+		"db_schema": &schema,
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	tables = make(map[string]dbEngine.Table, len(c.tables))
+	for name, table := range c.tables {
+		tables[name] = table
+	}
+
+	return database, tables, make(map[string]dbEngine.Routine), make(map[string]dbEngine.Types), nil
+}
+
+// GetStat return count of tables & rows
+func (c *Conn) GetStat() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	rows := 0
+	for _, table := range c.tables {
+		table.lock.RLock()
+		rows += len(table.rows)
+		table.lock.RUnlock()
+	}
+
+	return fmt.Sprintf("memdb: %d tables, %d rows", len(c.tables), rows)
+}
+
+// ExecDDL performs statements 'create table', 'create index', 'comment on' & 'drop table',
+// 'alter', 'grant', 'revoke' & 'create extension' are ignored, because they don't change memory tables,
+// statements are split by semicolons outside strings, comments & dollar-quoted bodies
+func (c *Conn) ExecDDL(ctx context.Context, sql string, args ...any) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lastRowAffected.Store(0)
+	statements, err := dbEngine.SplitStatementsDDL(sql)
+	if err != nil {
+		return err
+	}
+
+	for _, source := range statements {
+		stmt, err := dbEngine.ParseStatementDDL(source)
+		if err != nil {
+			return err
+		}
+
+		if err := c.execStmt(stmt); err != nil {
+			if _, ok := err.(*dbEngine.ErrUnknownSql); ok {
+				return dbEngine.NewErrUnknownSql(source.Text, source.Pos.Line)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Conn) execStmt(stmt dbEngine.StatementAST) error {
+	switch s := stmt.(type) {
+	case *dbEngine.CreateTableStmt:
+		name, columns, err := dbEngine.ParseTableDDL(s.Text)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := c.tables[name]; ok {
+			return newPgError(codeDuplicateTable, name, `relation "%s" already exists`, name)
+		}
+		c.tables[name] = newTable(c, name, columns)
+
+	case *dbEngine.CreateIndexStmt:
+		table, ok := c.tables[s.Table]
+		if !ok {
+			return newPgError(codeUndefinedTable, s.Table, `relation "%s" does not exist`, s.Table)
+		}
+
+		ind, err := dbEngine.ParseIndexDDL(table, s.Text)
+		if err != nil {
+			return err
+		}
+		if ind == nil {
+			return dbEngine.NewErrUnknownSql(s.Text, 0)
+		}
+		if table.FindIndex(ind.Name) != nil {
+			return newPgError(codeDuplicateTable, s.Table, `relation "%s" already exists`, ind.Name)
+		}
+		table.indexes = append(table.indexes, ind)
+
+	case *dbEngine.CommentStmt:
+		if s.Object != "table" && s.Object != "column" {
+			return dbEngine.NewErrUnknownSql(s.Text, 0)
+		}
+		return c.comment(s)
+
+	case *dbEngine.AlterStmt, *dbEngine.GrantStmt:
+		return nil
+
+	case *dbEngine.RawStmt:
+		switch {
+		case regDropTable.MatchString(s.Text):
+			tokens := regDropTable.FindStringSubmatch(s.Text)
+			name := strings.ToLower(tokens[2])
+			if _, ok := c.tables[name]; !ok && tokens[1] == "" {
+				return newPgError(codeUndefinedTable, name, `table "%s" does not exist`, name)
+			}
+			delete(c.tables, name)
+
+		case s.Command("create", "extension"):
+			return nil
+
+		default:
+			return dbEngine.NewErrUnknownSql(s.Text, 0)
+		}
+
+	default:
+		return dbEngine.NewErrUnknownSql(stmt.Source().Text, 0)
+	}
+
+	return nil
+}
+
+// comment stores comment of table or column
+func (c *Conn) comment(s *dbEngine.CommentStmt) error {
+	table, ok := c.tables[s.Table]
+	if !ok {
+		return newPgError(codeUndefinedTable, s.Table, `relation "%s" does not exist`, s.Table)
+	}
+
+	if s.Object == "table" {
+		table.comment = s.Comment
+		return nil
+	}
+
+	col := table.FindColumn(s.Column)
+	if col == nil {
+		return dbEngine.NewErrNotFoundColumn(s.Table, s.Column)
+	}
+	col.(*Column).comment = s.Comment
+
+	return nil
+}
+
+// NewTable return table of memory DB or new empty table which isn't stored in DB
+func (c *Conn) NewTable(name, typ string) dbEngine.Table {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if table, ok := c.tables[name]; ok {
+		return table
+	}
+
+	return &Table{conn: c, name: name}
+}
+
+// LastRowAffected return number of rows which were changed by last operation
+func (c *Conn) LastRowAffected() int64 {
+	return c.lastRowAffected.Load()
+}
+
+// columnName return name of column as DB stores it
+func columnName(name string) string {
+	if strings.HasPrefix(name, `"`) {
+		return strings.Trim(name, `"`)
+	}
+
+	return strings.ToLower(name)
+}
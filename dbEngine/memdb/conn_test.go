@@ -0,0 +1,230 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+const testDDL = `create table users
+(
+    id serial not null,
+    name character varying(20) not null,
+    email character varying not null default '',
+    age integer,
+    is_active boolean not null default true,
+    PRIMARY KEY (id)
+);
+COMMENT ON TABLE users IS 'list of users';
+-- email must be unique
+create unique index users_email_uindex
+    on users (email);
+`
+
+type user struct {
+	id   int32
+	name string
+	age  *int32
+}
+
+func (u *user) GetFields(columns []dbEngine.Column) []any {
+	v := make([]any, len(columns))
+	for i, col := range columns {
+		switch col.Name() {
+		case "id":
+			v[i] = &u.id
+		case "name":
+			v[i] = &u.name
+		case "age":
+			v[i] = &u.age
+		}
+	}
+
+	return v
+}
+
+func newTestDB(t *testing.T) *dbEngine.DB {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.ddl"), []byte(testDDL), 0o644))
+
+	ctx := context.WithValue(context.Background(), dbEngine.DB_SETTING,
+		dbEngine.CfgDB{Url: dir, GetSchema: &struct{}{}})
+	db, err := dbEngine.NewDB(ctx, NewConn())
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestConn_InitConn(t *testing.T) {
+	db := newTestDB(t)
+	assert.Equal(t, "memdb", db.Name)
+
+	table, ok := db.Tables["users"]
+	require.True(t, ok)
+	assert.Equal(t, "list of users", table.Comment())
+	assert.Len(t, table.Columns(), 5)
+	assert.NotNil(t, table.FindIndex("users_email_uindex"))
+
+	id := table.FindColumn("id")
+	assert.True(t, id.Primary())
+	assert.True(t, id.AutoIncrement())
+	assert.Equal(t, "int4", id.Type())
+
+	name := table.FindColumn("name")
+	assert.Equal(t, 20, name.CharacterMaximumLength())
+	assert.True(t, name.Required())
+	assert.True(t, table.FindColumn("age").IsNullable())
+
+	err := db.Conn.ExecDDL(context.Background(), testDDL)
+	assert.True(t, dbEngine.IsErrorAlreadyExists(err), err)
+	assert.Error(t, db.Conn.ExecDDL(context.Background(), "insert into users(name) values ('a')"))
+}
+
+func TestTable_Insert(t *testing.T) {
+	ctx := context.Background()
+	table := newTestDB(t).Tables["users"]
+
+	id, err := table.Insert(ctx, dbEngine.Columns("name", "email", "age"), dbEngine.Values("ann", "ann@test", 30))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, id)
+
+	id, err = table.Insert(ctx, dbEngine.Columns("name"), dbEngine.Values("bob"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, id)
+
+	_, err = table.Insert(ctx, dbEngine.Columns("name", "email"), dbEngine.Values("ann", "ann@test"))
+	_, ok := dbEngine.IsErrorDuplicated(err)
+	assert.True(t, ok, err)
+
+	id, err = table.Insert(ctx, dbEngine.Columns("name", "email"), dbEngine.Values("ann", "ann@test"),
+		dbEngine.InsertOnConflictDoNothing())
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, id)
+
+	_, err = table.Insert(ctx, dbEngine.Columns("email"), dbEngine.Values("nobody@test"))
+	assert.Error(t, err)
+
+	_, err = table.Insert(ctx, dbEngine.Columns("name"), dbEngine.Values("too long name of user for column"))
+	assert.Error(t, err)
+
+	_, err = table.Insert(ctx, dbEngine.Columns("email", "age"), dbEngine.Values("nobody@test", "old"),
+		dbEngine.ValidateBeforeWrite())
+	errValidation, ok := dbEngine.IsErrorValidation(err)
+	require.True(t, ok, err)
+	assert.ErrorIs(t, errValidation["name"], dbEngine.ErrValueRequired)
+	assert.ErrorIs(t, errValidation["age"], dbEngine.ErrValueWrongType)
+
+	var active bool
+	require.NoError(t, table.SelectOneAndScan(ctx, &active, dbEngine.ColumnsForSelect("is_active"),
+		dbEngine.WhereForSelect("name"), dbEngine.ArgsForSelect("bob")))
+	assert.True(t, active)
+}
+
+func TestTable_Select(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	table := db.Tables["users"]
+	for i, name := range []string{"ann", "bob", "carl", "dan"} {
+		_, err := table.Insert(ctx, dbEngine.Columns("name", "email", "age"), dbEngine.Values(name, name+"@test", 20+i*10))
+		require.NoError(t, err)
+	}
+	_, err := table.Insert(ctx, dbEngine.Columns("name", "email"), dbEngine.Values("eve", "eve@test"))
+	require.NoError(t, err)
+
+	names := make([]string, 0)
+	u := &user{}
+	err = table.SelectAndScanEach(ctx,
+		func() error {
+			names = append(names, u.name)
+			return nil
+		},
+		u,
+		dbEngine.ColumnsForSelect("id", "name"),
+		dbEngine.WhereForSelect(">=age"),
+		dbEngine.ArgsForSelect(30),
+		dbEngine.OrderBy("age desc"),
+		dbEngine.FetchOnlyRows(2),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dan", "carl"}, names)
+
+	require.NoError(t, table.SelectOneAndScan(ctx, u, dbEngine.ColumnsForSelect("name", "age"),
+		dbEngine.WhereForSelect("age"), dbEngine.ArgsForSelect(nil)))
+	assert.Equal(t, "eve", u.name)
+	assert.Nil(t, u.age)
+
+	err = table.SelectOneAndScan(ctx, u, dbEngine.ColumnsForSelect("name"),
+		dbEngine.WhereForSelect("name"), dbEngine.ArgsForSelect("nobody"))
+	assert.Equal(t, pgx.ErrNoRows, err)
+
+	// sql of SQLBuilder may be performed by Conn
+	b, err := dbEngine.NewSQLBuilder(table, dbEngine.ColumnsForSelect("name", "age"),
+		dbEngine.WhereForSelect("<age", "^name", "email"), dbEngine.ArgsForSelect(45, "a", []string{"ann@test", "carl@test"}),
+		dbEngine.OrderBy("name"))
+	require.NoError(t, err)
+	sql, err := b.SelectSql()
+	require.NoError(t, err)
+
+	rows, err := db.Conn.SelectToMaps(ctx, sql, b.Args...)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "ann", rows[0]["name"])
+	assert.EqualValues(t, 40, rows[1]["age"])
+
+	_, err = db.Conn.SelectToMaps(ctx, "select name from orders")
+	assert.True(t, dbEngine.IsErrorDoesNotExists(err), err)
+}
+
+func TestTable_UpdateUpsertDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	table := db.Tables["users"]
+	for _, name := range []string{"ann", "bob", "carl"} {
+		_, err := table.Insert(ctx, dbEngine.Columns("name", "email"), dbEngine.Values(name, name+"@test"))
+		require.NoError(t, err)
+	}
+
+	count, err := table.Update(ctx, dbEngine.Columns("age"), dbEngine.WhereForSelect("~name"), dbEngine.Values(50, "^[ab]"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.EqualValues(t, 2, db.Conn.LastRowAffected())
+
+	_, err = table.Update(ctx, dbEngine.Columns("email"), dbEngine.WhereForSelect("name"), dbEngine.Values("ann@test", "bob"))
+	_, ok := dbEngine.IsErrorDuplicated(err)
+	assert.True(t, ok, err)
+
+	id, err := table.Upsert(ctx, dbEngine.Columns("id", "name", "email"), dbEngine.Values(2, "robert", "bob@test"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, id)
+
+	u := &user{}
+	require.NoError(t, table.SelectOneAndScan(ctx, u, dbEngine.ColumnsForSelect("id", "name", "age"),
+		dbEngine.WhereForSelect("id"), dbEngine.ArgsForSelect(2)))
+	assert.Equal(t, "robert", u.name)
+	require.NotNil(t, u.age)
+	assert.EqualValues(t, 50, *u.age)
+
+	id, err = table.Upsert(ctx, dbEngine.Columns("name", "email"), dbEngine.Values("dan", "dan@test"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, id)
+
+	count, err = table.Delete(ctx, dbEngine.WhereForSelect("age"), dbEngine.ArgsForSelect(50))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	rows, columns, err := db.Conn.SelectToMultiDimension(ctx, "SELECT name FROM users order by name desc")
+	require.NoError(t, err)
+	assert.Equal(t, "name", columns[0].Name())
+	assert.Equal(t, [][]any{{"dan"}, {"carl"}}, rows)
+}
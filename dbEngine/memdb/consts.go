@@ -0,0 +1,42 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgconn"
+)
+
+// codes of PostgreSQL errors which memory DB returns same as server
+const (
+	codeNotNullViolation = "23502"
+	codeUniqueViolation  = "23505"
+	codeStringTooLong    = "22001"
+	codeUndefinedTable   = "42P01"
+	codeDuplicateTable   = "42P07"
+)
+
+var (
+	regSelect = regexp.MustCompile(`(?is)^\s*select\s+(?P<columns>.+?)\s+from\s+(?P<table>\w+)` +
+		`(?:\s+where\s+(?P<where>.+?))?(?:\s+order\s+by\s+(?P<order>.+?))?` +
+		`(?:\s+offset\s+(?P<offset>\d+))?(?:\s+fetch\s+first\s+(?P<limit>\d+)\s+rows\s+only)?\s*;?\s*$`)
+	regAnd       = regexp.MustCompile(`(?i)\s+and\s+`)
+	regIsNull    = regexp.MustCompile(`(?i)^("[^"]+"|\w+)\s+(is(?:\s+not)?\s+null)$`)
+	regCondition = regexp.MustCompile(`(?i)^("[^"]+"|\w+)\s*(>=|<=|<>|!=|=|>|<|~\*|!~|~)\s*` +
+		`(?:\$(?P<arg>\d+)|any\(\$(?P<any>\d+)\)|concat\('(?P<pre>\^?\.\*)',\s*\$(?P<pattern>\d+)(?P<suffix>,\s*'\$')?\))$`)
+	regDropTable = regexp.MustCompile(`(?i)^drop\s+table\s+(if\s+exists\s+)?(\w+)`)
+)
+
+// newPgError create error same as PostgreSQL returns, so dbEngine.IsError* handle it
+func newPgError(code, table, format string, args ...any) *pgconn.PgError {
+	return &pgconn.PgError{
+		Severity:  "ERROR",
+		Code:      code,
+		Message:   fmt.Sprintf(format, args...),
+		TableName: table,
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// SelectOneAndScan run SELECT which SQLBuilder generates & scan first row into rowValues
+func (c *Conn) SelectOneAndScan(ctx context.Context, rowValues any, sql string, args ...any) error {
+	t, b, err := c.parseSelect(sql, args)
+	if err != nil {
+		return err
+	}
+
+	return t.selectOneAndScan(b, rowValues)
+}
+
+// SelectAndScanEach run SELECT which SQLBuilder generates & scan every row into rowValue & run each
+func (c *Conn) SelectAndScanEach(ctx context.Context, each func() error, rowValue dbEngine.RowScanner, sql string, args ...any) error {
+	t, b, err := c.parseSelect(sql, args)
+	if err != nil {
+		return err
+	}
+
+	return t.selectAndScanEach(b, each, rowValue)
+}
+
+// SelectAndRunEach run SELECT which SQLBuilder generates & performs each every row
+func (c *Conn) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, sql string, args ...any) error {
+	t, b, err := c.parseSelect(sql, args)
+	if err != nil {
+		return err
+	}
+
+	return t.selectAndRunEach(b, each)
+}
+
+// SelectAndPerformRaw run SELECT which SQLBuilder generates & performs each with values of row as text
+func (c *Conn) SelectAndPerformRaw(ctx context.Context, each dbEngine.FncRawRow, sql string, args ...any) error {
+	return c.SelectAndRunEach(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			raw := make([][]byte, len(values))
+			for i, value := range values {
+				raw[i] = dbEngine.FormatRaw(value)
+			}
+
+			return each(raw, columns)
+		},
+		sql,
+		args...)
+}
+
+// SelectToMap run SELECT which SQLBuilder generates & return first row as map of column values
+func (c *Conn) SelectToMap(ctx context.Context, sql string, args ...any) (map[string]any, error) {
+	rows, err := c.SelectToMaps(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return rows[0], nil
+}
+
+// SelectToMaps run SELECT which SQLBuilder generates & return rows as maps of column values
+func (c *Conn) SelectToMaps(ctx context.Context, sql string, args ...any) ([]map[string]any, error) {
+	rows := make([]map[string]any, 0)
+	err := c.SelectAndRunEach(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			row := make(map[string]any, len(values))
+			for i, col := range columns {
+				row[col.Name()] = values[i]
+			}
+			rows = append(rows, row)
+
+			return nil
+		},
+		sql,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// SelectToMultiDimension run SELECT which SQLBuilder generates & return rows as slices of values
+func (c *Conn) SelectToMultiDimension(ctx context.Context, sql string, args ...any) ([][]any, []dbEngine.Column, error) {
+	t, b, err := c.parseSelect(sql, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns, rows, err := t.selectRows(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rows, columns, nil
+}
+
+// parseSelect converts text of SELECT (in form which SQLBuilder.SelectSql generates) into SQLBuilder of table
+func (c *Conn) parseSelect(sql string, args []any) (*Table, *dbEngine.SQLBuilder, error) {
+	tokens := regSelect.FindStringSubmatch(sql)
+	if len(tokens) == 0 {
+		return nil, nil, dbEngine.NewErrUnknownSql(sql, 0)
+	}
+
+	name := strings.ToLower(tokens[regSelect.SubexpIndex("table")])
+	c.lock.RLock()
+	t, ok := c.tables[name]
+	c.lock.RUnlock()
+	if !ok {
+		return nil, nil, newPgError(codeUndefinedTable, name, `relation "%s" does not exist`, name)
+	}
+
+	// pgx options aren't arguments of query
+	queryArgs := make([]any, 0, len(args))
+	for _, arg := range args {
+		if _, ok := arg.(pgx.QuerySimpleProtocol); !ok {
+			queryArgs = append(queryArgs, arg)
+		}
+	}
+
+	options := make([]dbEngine.BuildSqlOptions, 0)
+	if columns := strings.TrimSpace(tokens[regSelect.SubexpIndex("columns")]); columns != "*" {
+		names := strings.Split(columns, ",")
+		for i, name := range names {
+			names[i] = columnName(strings.TrimSpace(name))
+		}
+		options = append(options, dbEngine.ColumnsForSelect(names...))
+	}
+
+	if where := tokens[regSelect.SubexpIndex("where")]; where > "" {
+		filter, filterArgs, err := parseWhere(where, queryArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, dbEngine.WhereForSelect(filter...), dbEngine.ArgsForSelect(filterArgs...))
+	}
+
+	if order := tokens[regSelect.SubexpIndex("order")]; order > "" {
+		names := strings.Split(order, ",")
+		for i, name := range names {
+			name, desc := strings.CutSuffix(strings.TrimSpace(name), " desc")
+			names[i] = columnName(strings.TrimSpace(name))
+			if desc {
+				names[i] += " desc"
+			}
+		}
+		options = append(options, dbEngine.OrderBy(names...))
+	}
+
+	if offset := tokens[regSelect.SubexpIndex("offset")]; offset > "" {
+		i, _ := strconv.Atoi(offset)
+		options = append(options, dbEngine.Offset(i))
+	}
+
+	if limit := tokens[regSelect.SubexpIndex("limit")]; limit > "" {
+		i, _ := strconv.Atoi(limit)
+		options = append(options, dbEngine.FetchOnlyRows(i))
+	}
+
+	b, err := dbEngine.NewSQLBuilder(t, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, b, nil
+}
+
+// parseWhere converts terms of WHERE into names with prefix operators for Where option & their arguments
+func parseWhere(where string, args []any) ([]string, []any, error) {
+	filter := make([]string, 0)
+	filterArgs := make([]any, 0)
+	for _, term := range regAnd.Split(strings.TrimSpace(where), -1) {
+		if tokens := regIsNull.FindStringSubmatch(term); len(tokens) > 0 {
+			filter = append(filter, columnName(tokens[1])+" "+strings.Join(strings.Fields(strings.ToLower(tokens[2])), " "))
+			continue
+		}
+
+		tokens := regCondition.FindStringSubmatch(term)
+		if len(tokens) == 0 {
+			return nil, nil, dbEngine.NewErrUnsupportedCondition(term)
+		}
+
+		name, op := columnName(tokens[1]), tokens[2]
+		num := tokens[regCondition.SubexpIndex("arg")] + tokens[regCondition.SubexpIndex("any")]
+		if pattern := tokens[regCondition.SubexpIndex("pattern")]; pattern > "" {
+			num = pattern
+			switch pre := tokens[regCondition.SubexpIndex("pre")]; {
+			case tokens[regCondition.SubexpIndex("suffix")] > "" && op == "~":
+				op = "$"
+			case pre == "^.*" && op == "~":
+				op = "^"
+			case pre == "^.*" && op == "~*":
+				op = "*"
+			default:
+				return nil, nil, dbEngine.NewErrUnsupportedCondition(term)
+			}
+		}
+
+		switch op {
+		case "=":
+			op = ""
+		case "!=":
+			op = "<>"
+		case "!~":
+			return nil, nil, dbEngine.NewErrUnsupportedCondition(term)
+		}
+
+		i, _ := strconv.Atoi(num)
+		if i < 1 || i > len(args) {
+			return nil, nil, dbEngine.NewErrWrongArgsLen(term, filter, args)
+		}
+
+		filter = append(filter, op+name)
+		filterArgs = append(filterArgs, args[i-1])
+	}
+
+	return filter, filterArgs, nil
+}
@@ -0,0 +1,444 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// Table implement dbEngine.Table, it stores rows in memory & performs SQLBuilder options without DB
+type Table struct {
+	conn    *Conn
+	name    string
+	comment string
+	columns []dbEngine.Column
+	indexes dbEngine.Indexes
+	rows    [][]any
+	seq     int64
+	lock    sync.RWMutex
+}
+
+// newTable create table with columns of DDL
+func newTable(conn *Conn, name string, defines []dbEngine.ColumnDDL) *Table {
+	t := &Table{conn: conn, name: name}
+	t.columns = make([]dbEngine.Column, len(defines))
+	for i, define := range defines {
+		t.columns[i] = NewColumn(t, define)
+	}
+
+	return t
+}
+
+// Columns of Table
+func (t *Table) Columns() []dbEngine.Column {
+	return t.columns
+}
+
+// Comment of Table
+func (t *Table) Comment() string {
+	return t.comment
+}
+
+// FindColumn return column 'name' on Table or nil
+func (t *Table) FindColumn(name string) dbEngine.Column {
+	if i := dbEngine.ColumnIndex(t.columns, name); i > -1 {
+		return t.columns[i]
+	}
+
+	return nil
+}
+
+// FindIndex get index according to name
+func (t *Table) FindIndex(name string) *dbEngine.Index {
+	for _, ind := range t.indexes {
+		if ind.Name == name {
+			return ind
+		}
+	}
+
+	return nil
+}
+
+// Indexes get indexes according to table
+func (t *Table) Indexes() dbEngine.Indexes {
+	return t.indexes
+}
+
+// GetColumns do nothing, columns are read from DDL
+func (t *Table) GetColumns(ctx context.Context, dbTypes map[string]dbEngine.Types) error {
+	return nil
+}
+
+// Name of Table
+func (t *Table) Name() string {
+	return t.name
+}
+
+// PrimaryKey return columns of primary key of table
+func (t *Table) PrimaryKey() []dbEngine.Column {
+	return dbEngine.PrimaryColumns(t)
+}
+
+// UniqueConstraints return unique constraints of table
+func (t *Table) UniqueConstraints() dbEngine.Constraints {
+	return dbEngine.UniqueIndexes(t)
+}
+
+// Constraints return primary key & unique constraints of table
+func (t *Table) Constraints() dbEngine.Constraints {
+	return dbEngine.KeyConstraints(t)
+}
+
+// ForeignKeys return foreign keys of table
+func (t *Table) ForeignKeys() dbEngine.ForeignKeys {
+	return dbEngine.ColumnsForeignKeys(t)
+}
+
+// Triggers return nil, table doesn't support triggers
+func (t *Table) Triggers() dbEngine.Triggers {
+	return nil
+}
+
+// Validate check values of columns according to their metadata before writing
+func (t *Table) Validate(columns []string, values []any) error {
+	return dbEngine.ValidateValues(t, columns, values)
+}
+
+// ReReadColumn return column 'name', its properties don't change without DDL
+func (t *Table) ReReadColumn(ctx context.Context, name string) dbEngine.Column {
+	return t.FindColumn(name)
+}
+
+// Insert new row & return new ID if table has autoincrement primary key or rowsAffected
+func (t *Table) Insert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	if err := b.ValidateArgs(true); err != nil {
+		return 0, err
+	}
+
+	names := b.ColumnNames()
+	if len(names) != len(b.Args) {
+		return 0, dbEngine.NewErrWrongArgsLen(t.name, names, b.Args)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	row, err := t.newRow(names, b.Args)
+	if err != nil {
+		return 0, err
+	}
+
+	err = t.write(append(slices.Clone(t.rows), row))
+	if _, ok := dbEngine.IsErrorDuplicated(err); ok && strings.HasSuffix(b.OnConflict(), "DO NOTHING") {
+		return t.affected(0), nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return t.returning(row, 1), nil
+}
+
+// Update table according to Options
+func (t *Table) Update(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	if err := b.ValidateArgs(false); err != nil {
+		return 0, err
+	}
+
+	names, values, conditions, err := b.UpdateValues()
+	if err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	rows := slices.Clone(t.rows)
+	count := int64(0)
+	for i, row := range rows {
+		ok, err := dbEngine.MatchConditions(conditions, func(name string) (any, error) {
+			return dbEngine.RowValue(t, row, name)
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			rows[i], err = t.setValues(row, names, values)
+			if err != nil {
+				return 0, err
+			}
+			count++
+		}
+	}
+
+	if count > 0 {
+		if err := t.write(rows); err != nil {
+			return 0, err
+		}
+	}
+
+	return t.affected(count), nil
+}
+
+// Upsert preforms INSERT or UPDATE if record with same keys (see SQLBuilder.UpsertKeys) exists
+func (t *Table) Upsert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	if err := b.ValidateArgs(true); err != nil {
+		return 0, err
+	}
+
+	names := b.ColumnNames()
+	if len(names) != len(b.Args) {
+		return 0, dbEngine.NewErrWrongArgsLen(t.name, names, b.Args)
+	}
+
+	keys, doNothing, err := b.UpsertKeys()
+	if err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	row, err := t.newRow(names, b.Args)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := slices.Clone(t.rows)
+	i := -1
+	if len(keys) > 0 {
+		key, err := t.key(row, keys)
+		if err != nil {
+			return 0, err
+		}
+		i = slices.IndexFunc(rows, func(r []any) bool {
+			k, _ := t.key(r, keys)
+			return k == key
+		})
+	}
+
+	switch {
+	case i < 0:
+		rows = append(rows, row)
+	case doNothing:
+		return t.affected(0), nil
+	default:
+		updNames, updValues := make([]string, 0, len(names)), make([]any, 0, len(names))
+		for j, name := range names {
+			if !slices.Contains(keys, name) {
+				updNames = append(updNames, name)
+				updValues = append(updValues, b.Args[j])
+			}
+		}
+
+		rows[i], err = t.setValues(rows[i], updNames, updValues)
+		if err != nil {
+			return 0, err
+		}
+		row = rows[i]
+	}
+
+	err = t.write(rows)
+	if _, ok := dbEngine.IsErrorDuplicated(err); ok && doNothing {
+		return t.affected(0), nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return t.returning(row, 1), nil
+}
+
+// Delete rows of table according to Options
+func (t *Table) Delete(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	conditions, err := b.Conditions()
+	if err != nil {
+		return 0, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	rows := make([][]any, 0, len(t.rows))
+	for _, row := range t.rows {
+		ok, err := dbEngine.MatchConditions(conditions, func(name string) (any, error) {
+			return dbEngine.RowValue(t, row, name)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			rows = append(rows, row)
+		}
+	}
+
+	count := int64(len(t.rows) - len(rows))
+	t.rows = rows
+
+	return t.affected(count), nil
+}
+
+// Select run sql with Options (deprecated)
+func (t *Table) Select(ctx context.Context, Options ...dbEngine.BuildSqlOptions) error {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return errors.Wrap(err, "setOption")
+	}
+
+	_, _, err = t.selectRows(b)
+
+	return err
+}
+
+// SelectOneAndScan run sql of table with Options & return first row into rowValues
+func (t *Table) SelectOneAndScan(ctx context.Context, row any, Options ...dbEngine.BuildSqlOptions) error {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return errors.Wrap(err, "setOption")
+	}
+
+	return t.selectOneAndScan(b, row)
+}
+
+// SelectAndScanEach run sql of table with Options & return every row into rowValues & run each
+func (t *Table) SelectAndScanEach(ctx context.Context, each func() error, rowValue dbEngine.RowScanner, Options ...dbEngine.BuildSqlOptions) error {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return errors.Wrap(err, "setOption")
+	}
+
+	return t.selectAndScanEach(b, each, rowValue)
+}
+
+// SelectAndRunEach run sql of table with Options & performs each every row of query results
+func (t *Table) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, Options ...dbEngine.BuildSqlOptions) error {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return errors.Wrap(err, "setOption")
+	}
+
+	return t.selectAndRunEach(b, each)
+}
+
+func (t *Table) selectOneAndScan(b *dbEngine.SQLBuilder, row any) error {
+	columns, rows, err := t.selectRows(b)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		return pgx.ErrNoRows
+	}
+
+	var dest []any
+	switch r := row.(type) {
+	case []any:
+		dest = r
+	case dbEngine.RowScanner:
+		dest = r.GetFields(columns)
+	default:
+		dest = []any{r}
+	}
+
+	return dbEngine.ScanRow(dest, rows[0], columns)
+}
+
+func (t *Table) selectAndScanEach(b *dbEngine.SQLBuilder, each func() error, rowValue dbEngine.RowScanner) error {
+	columns, rows, err := t.selectRows(b)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := dbEngine.ScanRow(rowValue.GetFields(columns), row, columns); err != nil {
+			return err
+		}
+
+		if each != nil {
+			if err := each(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) selectAndRunEach(b *dbEngine.SQLBuilder, each dbEngine.FncEachRow) error {
+	columns, rows, err := t.selectRows(b)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if each != nil {
+			if err := each(row, columns); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectRows return selected columns & their values of rows according to SQLBuilder
+// (Where with prefix operators, OrderBy, Offset, FetchOnlyRows)
+func (t *Table) selectRows(b *dbEngine.SQLBuilder) ([]dbEngine.Column, [][]any, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return dbEngine.SelectRows(b, t, t.rows)
+}
+
+// affected stores count of changed rows for Conn.LastRowAffected
+func (t *Table) affected(count int64) int64 {
+	if t.conn != nil {
+		t.conn.lastRowAffected.Store(count)
+	}
+
+	return count
+}
+
+// returning return value of autoincrement primary key of row same as psql, otherwise count
+func (t *Table) returning(row []any, count int64) int64 {
+	t.affected(count)
+	// only single column key may be returned as ID
+	if pk := t.PrimaryKey(); len(pk) == 1 && pk[0].AutoIncrement() {
+		i := dbEngine.ColumnIndex(t.columns, pk[0].Name())
+		var id int64
+		if err := dbEngine.ScanValue(&id, row[i]); err == nil {
+			return id
+		}
+	}
+
+	return count
+}
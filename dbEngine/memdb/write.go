@@ -0,0 +1,145 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// uniqueKey is columns which values must be unique in table
+type uniqueKey struct {
+	name    string
+	columns []string
+}
+
+// newRow create row from values of columns 'names', other columns get their default values
+func (t *Table) newRow(names []string, values []any) ([]any, error) {
+	row := make([]any, len(t.columns))
+	for i, c := range t.columns {
+		col := c.(*Column)
+		if col.autoInc && !slices.Contains(names, col.name) {
+			t.seq++
+			row[i], _ = col.normalize(t.seq)
+			continue
+		}
+
+		value, err := col.defaultValue()
+		if err != nil {
+			return nil, err
+		}
+		row[i] = value
+	}
+
+	return t.setValues(row, names, values)
+}
+
+// setValues return copy of row with new values of columns 'names'
+func (t *Table) setValues(row []any, names []string, values []any) ([]any, error) {
+	if len(names) != len(values) {
+		return nil, dbEngine.NewErrWrongArgsLen(t.name, names, values)
+	}
+
+	newRow := make([]any, len(row))
+	copy(newRow, row)
+	for i, name := range names {
+		ind := dbEngine.ColumnIndex(t.columns, name)
+		if ind < 0 {
+			return nil, dbEngine.NewErrNotFoundColumn(t.name, name)
+		}
+
+		value, err := t.columns[ind].(*Column).normalize(values[i])
+		if err != nil {
+			return nil, err
+		}
+		newRow[ind] = value
+	}
+
+	for i, col := range t.columns {
+		if newRow[i] == nil && !col.IsNullable() {
+			return nil, newPgError(codeNotNullViolation, t.name,
+				`null value in column "%s" of relation "%s" violates not-null constraint`, col.Name(), t.name)
+		}
+	}
+
+	return newRow, nil
+}
+
+// key return values of columns 'names' of row as text
+func (t *Table) key(row []any, names []string) (string, error) {
+	key := make([]string, len(names))
+	for i, name := range names {
+		value, err := dbEngine.RowValue(t, row, name)
+		if err != nil {
+			return "", err
+		}
+		key[i] = fmt.Sprint(value)
+	}
+
+	return strings.Join(key, ", "), nil
+}
+
+// uniqueKeys return primary key, unique columns & unique indexes (without expressions & predicates)
+func (t *Table) uniqueKeys() []uniqueKey {
+	keys := make([]uniqueKey, 0)
+	primary := make([]string, 0)
+	for _, c := range t.columns {
+		col := c.(*Column)
+		if col.primary {
+			primary = append(primary, col.name)
+		}
+		if col.unique {
+			keys = append(keys, uniqueKey{name: t.name + "_" + col.name + "_key", columns: []string{col.name}})
+		}
+	}
+	if len(primary) > 0 {
+		keys = append(keys, uniqueKey{name: t.name + "_pkey", columns: primary})
+	}
+
+	for _, ind := range t.indexes {
+		if ind.Unique && ind.Expr == "" && ind.Where == "" {
+			keys = append(keys, uniqueKey{name: ind.Name, columns: ind.Columns})
+		}
+	}
+
+	return keys
+}
+
+// write checks unique keys of rows & replace rows of table, NULL values don't violate unique keys
+func (t *Table) write(rows [][]any) error {
+	for _, key := range t.uniqueKeys() {
+		values := make(map[string]struct{}, len(rows))
+	rows:
+		for _, row := range rows {
+			for _, name := range key.columns {
+				if v, _ := dbEngine.RowValue(t, row, name); v == nil {
+					continue rows
+				}
+			}
+
+			value, err := t.key(row, key.columns)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := values[value]; ok {
+				err := newPgError(codeUniqueViolation, t.name,
+					`duplicate key value violates unique constraint "%s"`, key.name)
+				err.ConstraintName = key.name
+				err.Detail = fmt.Sprintf("Key (%s)=(%s) already exists.", strings.Join(key.columns, ", "), value)
+
+				return err
+			}
+			values[value] = struct{}{}
+		}
+	}
+
+	t.rows = rows
+
+	return nil
+}
@@ -0,0 +1,369 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ruslanBik4/gotools"
+)
+
+// phases of migration, their files are ordered by dependencies
+const (
+	migrationRoles   = "roles"
+	migrationTypes   = "types"
+	migrationTable   = "table"
+	migrationView    = "view"
+	migrationFunc    = "func"
+	migrationTrigger = "trigger"
+)
+
+// migration is DDL file of migration phase
+type migration struct {
+	phase string
+	path  string
+	name  string
+	ddl   string
+}
+
+// String return name of object of migration with its phase
+func (m *migration) String() string {
+	return m.phase + "/" + m.name
+}
+
+// readMigrations collects files of migration phases in order of phases
+func readMigrations(root string, phases []string) ([]*migration, error) {
+	list := make([]*migration, 0)
+	for _, phase := range phases {
+		err := filepath.WalkDir(filepath.Join(root, phase), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+
+			ddl, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			fileName := filepath.Base(path)
+			list = append(list, &migration{
+				phase: phase,
+				path:  path,
+				name:  strings.ToLower(strings.TrimSuffix(fileName, filepath.Ext(fileName))),
+				ddl:   gotools.BytesToString(ddl),
+			})
+
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "migration "+phase)
+		}
+	}
+
+	return list, nil
+}
+
+// tableTriggers return names of triggers which DDL files of tables declare by tables,
+// statements with syntax errors are skipped
+func tableTriggers(list []*migration) map[string][]string {
+	triggers := make(map[string][]string)
+	for _, m := range list {
+		if m.phase != migrationTable {
+			continue
+		}
+
+		statements, _ := SplitStatementsDDL(m.ddl)
+		for _, source := range statements {
+			stmt, err := ParseStatementDDL(source)
+			if s, ok := stmt.(*CreateTriggerStmt); ok && err == nil {
+				triggers[s.Table] = append(triggers[s.Table], s.Name)
+			}
+		}
+	}
+
+	return triggers
+}
+
+// dependencies return indexes of migrations in list which must be performed before m:
+// referenced tables & views (foreign keys, sources of views), types used by columns & functions called by DDL,
+// functions depend only on types & relations of their arguments & results because bodies aren't checked during creating,
+// triggers depend on their table & functions
+func (m *migration) dependencies(list []*migration, byName map[string][]int) []int {
+	deps := make([]int, 0)
+	add := func(names []string, phases ...string) {
+		for _, name := range names {
+			for _, i := range byName[strings.ToLower(name)] {
+				if list[i] != m && slices.Contains(phases, list[i].phase) && !slices.Contains(deps, i) {
+					deps = append(deps, i)
+				}
+			}
+		}
+	}
+
+	// file of triggers has name of their table
+	if m.phase == migrationTrigger {
+		add([]string{m.name}, migrationTable, migrationView)
+	}
+
+	refs := m.references()
+	add(refs.relations, migrationTable, migrationView)
+	add(refs.types, migrationTypes)
+	add(refs.routines, migrationFunc)
+
+	return deps
+}
+
+// migrationRefs are names of objects (without schema) which DDL of migration uses
+type migrationRefs struct {
+	relations []string
+	types     []string
+	routines  []string
+}
+
+// references collects objects used by statements of migration, statements with syntax errors are skipped
+func (m *migration) references() *migrationRefs {
+	refs := &migrationRefs{}
+	statements, _ := SplitStatementsDDL(m.ddl)
+	for _, source := range statements {
+		stmt, err := ParseStatementDDL(source)
+		if err != nil {
+			continue
+		}
+
+		switch s := stmt.(type) {
+		case *CreateTableStmt:
+			refs.relations = append(refs.relations, s.Inherits...)
+			refs.relations = append(refs.relations, s.Like...)
+			if s.PartitionOf > "" {
+				refs.relations = append(refs.relations, s.PartitionOf)
+			}
+			for _, fk := range s.ForeignKeys {
+				refs.relations = append(refs.relations, fk.Parent)
+			}
+			for _, col := range s.Columns {
+				refs.column(col)
+			}
+			for _, c := range s.Constraints {
+				refs.expression(c.Definition)
+			}
+
+		case *CreateViewStmt:
+			refs.expression(s.Query)
+
+		case *CreateIndexStmt:
+			refs.relations = append(refs.relations, s.Table)
+			for _, item := range s.Items {
+				refs.expression(item)
+			}
+			refs.expression(s.Where)
+
+		case *CreateTypeStmt:
+			for _, attr := range s.Attributes {
+				refs.types = append(refs.types, typeName(attr.Type))
+			}
+
+		case *CreateRoutineStmt:
+			types := make([]string, 0, len(s.Args)+1)
+			for _, arg := range s.Args {
+				types = append(types, typeName(arg.Type))
+			}
+			if s.Returns > "" {
+				types = append(types, typeName(s.Returns))
+			}
+			// type of argument or result may be row of table
+			refs.types = append(refs.types, types...)
+			refs.relations = append(refs.relations, types...)
+
+		case *CreateTriggerStmt:
+			refs.relations = append(refs.relations, s.Table)
+			refs.routines = append(refs.routines, s.Function)
+			refs.expression(s.When)
+
+		case *AlterStmt:
+			refs.relations = append(refs.relations, s.Name)
+			for _, action := range s.Actions {
+				if action.ForeignKey != nil {
+					refs.relations = append(refs.relations, action.ForeignKey.Parent)
+				} else {
+					refs.expression(action.Text)
+				}
+			}
+
+		case *CommentStmt:
+			if s.Table > "" {
+				refs.relations = append(refs.relations, s.Table)
+			}
+
+		case *InsertStmt:
+			refs.relations = append(refs.relations, s.Table)
+		}
+	}
+
+	return refs
+}
+
+// column adds type of column & objects used by its default value & constraints
+func (r *migrationRefs) column(col ColumnDDL) {
+	r.types = append(r.types, typeName(col.Type))
+
+	tokens, err := TokenizeDDL(col.Define)
+	if err != nil {
+		return
+	}
+
+	r.tokens(tokens[keywordIndex(tokens, 0, ddlTypeEnd...):])
+}
+
+// expression adds objects used by text of expression or query
+func (r *migrationRefs) expression(text string) {
+	if tokens, err := TokenizeDDL(text); err == nil {
+		r.tokens(tokens)
+	}
+}
+
+// tokens adds functions called in tokens, types of casts & relations of clauses 'FROM', 'JOIN' & 'REFERENCES',
+// types & lists of columns with brackets after names aren't calls
+func (r *migrationRefs) tokens(tokens []Token) {
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if !tok.IsName() {
+			continue
+		}
+
+		if tok.Is("from", "join", "references") {
+			j := i + 1
+			for j < len(tokens) && tokens[j].Is("only", "lateral") {
+				j++
+			}
+			if j == len(tokens) || !tokens[j].IsName() {
+				continue
+			}
+
+			name, end := qualifiedName(tokens, j)
+			// function in clause 'FROM'
+			if end < len(tokens) && tokens[end].Kind == TokenLParen && !tok.Is("references") {
+				r.routines = append(r.routines, name)
+			} else {
+				r.relations = append(r.relations, name)
+			}
+			i = end - 1
+			continue
+		}
+
+		name, end := qualifiedName(tokens, i)
+		switch {
+		case i > 0 && tokens[i-1].Kind == TokenOperator && tokens[i-1].Text == "::":
+			r.types = append(r.types, name)
+		case end < len(tokens) && tokens[end].Kind == TokenLParen:
+			r.routines = append(r.routines, name)
+		}
+		i = end - 1
+	}
+}
+
+// qualifiedName return name (without schema) which starts from tokens[i] & index of token after it
+func qualifiedName(tokens []Token, i int) (string, int) {
+	name := tokens[i].Name()
+	for i+2 < len(tokens) && tokens[i+1].Kind == TokenOperator && tokens[i+1].Text == "." && tokens[i+2].IsName() {
+		i += 2
+		name = tokens[i].Name()
+	}
+
+	return name, i + 1
+}
+
+// typeName return name of type without schema, modifiers & brackets of array
+func typeName(typ string) string {
+	if i := strings.IndexAny(typ, "(["); i >= 0 {
+		typ = typ[:i]
+	}
+	if i := strings.LastIndex(typ, "."); i >= 0 {
+		typ = typ[i+1:]
+	}
+
+	return strings.Trim(strings.TrimSpace(typ), `"`)
+}
+
+// sortMigrations return migrations in topological order of their dependencies,
+// independent migrations keep order of list (phases order),
+// every cycle of dependencies is reported by ErrMigrationCycle & broken on its first migration of list
+func sortMigrations(list []*migration) ([]*migration, error) {
+	byName := make(map[string][]int)
+	for i, m := range list {
+		byName[m.name] = append(byName[m.name], i)
+	}
+
+	deps := make([][]int, len(list))
+	for i, m := range list {
+		deps[i] = m.dependencies(list, byName)
+	}
+
+	done := make([]bool, len(list))
+	isReady := func(i int) bool {
+		return !done[i] && !slices.ContainsFunc(deps[i], func(dep int) bool {
+			return !done[dep]
+		})
+	}
+
+	var errCycle *ErrMigrationCycle
+	order := make([]*migration, 0, len(list))
+	for len(order) < len(list) {
+		next := -1
+		for i := range list {
+			if isReady(i) {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			cycle := findCycle(deps, done)
+			if errCycle == nil {
+				errCycle = &ErrMigrationCycle{}
+			}
+
+			names := make([]string, 0, len(cycle)+1)
+			for _, i := range cycle {
+				names = append(names, list[i].String())
+			}
+			errCycle.Cycles = append(errCycle.Cycles, append(names, names[0]))
+
+			next = slices.Min(cycle)
+		}
+
+		done[next] = true
+		order = append(order, list[next])
+	}
+
+	if errCycle != nil {
+		return order, errCycle
+	}
+
+	return order, nil
+}
+
+// findCycle return indexes of cycle among not done migrations,
+// it's called only if every not done migration has not done dependency
+func findCycle(deps [][]int, done []bool) []int {
+	path := make([]int, 0)
+	i := slices.Index(done, false)
+	for !slices.Contains(path, i) {
+		path = append(path, i)
+		for _, dep := range deps[i] {
+			if !done[dep] {
+				i = dep
+				break
+			}
+		}
+	}
+
+	return path[slices.Index(path, i):]
+}
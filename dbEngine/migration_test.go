@@ -0,0 +1,153 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func migrationNames(list []*migration) []string {
+	names := make([]string, len(list))
+	for i, m := range list {
+		names[i] = m.String()
+	}
+
+	return names
+}
+
+func TestSortMigrations(t *testing.T) {
+	list := []*migration{
+		{phase: migrationRoles, name: "admin", ddl: "create role admin"},
+		{phase: migrationTypes, name: "address", ddl: "create type address as (city text, zip zip_code)"},
+		{phase: migrationTypes, name: "zip_code", ddl: "create domain zip_code as text check (value ~ '^\\d{5}$')"},
+		{phase: migrationTable, name: "order_items", ddl: `create table order_items (
+	order_id integer not null references orders,
+	sku text not null,
+	shop text not null,
+	-- references archive
+	foreign key (sku, shop) references public.items (sku, shop)
+)`},
+		{phase: migrationTable, name: "orders", ddl: `create table orders (
+	id serial primary key,
+	address address,
+	num text default next_order_num()
+)`},
+		{phase: migrationTable, name: "items", ddl: "create table items (sku text, shop text, primary key (sku, shop))"},
+		{phase: migrationView, name: "order_totals", ddl: "create or replace view order_totals as select * from order_sums join orders on true"},
+		{phase: migrationView, name: "order_sums", ddl: "create or replace view order_sums as select order_id, count(*) from order_items group by 1"},
+		{phase: migrationFunc, name: "next_order_num", ddl: "create function next_order_num() returns text as $$ select count(*) from orders $$"},
+		{phase: migrationFunc, name: "orders_of", ddl: "create function orders_of(a address) returns setof orders as $$ select 1 $$"},
+		{phase: migrationTrigger, name: "orders", ddl: "create trigger orders_num before insert on orders for each row execute function next_order_num()"},
+	}
+
+	order, err := sortMigrations(list)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"roles/admin",
+		"types/zip_code",
+		"types/address",
+		"table/items",
+		"func/next_order_num",
+		"table/orders",
+		"table/order_items",
+		"view/order_sums",
+		"view/order_totals",
+		"func/orders_of",
+		"trigger/orders",
+	}, migrationNames(order))
+
+	byName := map[string][]int{"orders": {4, 10}, "next_order_num": {8}}
+	assert.ElementsMatch(t, []int{4, 8}, list[10].dependencies(list, byName))
+}
+
+func TestSortMigrations_Cycles(t *testing.T) {
+	list := []*migration{
+		{phase: migrationTable, name: "a", ddl: "create table a (id int, b_id int references b)"},
+		{phase: migrationTable, name: "b", ddl: "create table b (id int, a_id int references a)"},
+		{phase: migrationTable, name: "c", ddl: "create table c (id int, a_id int references a)"},
+		{phase: migrationView, name: "v1", ddl: "create view v1 as select * from v2"},
+		{phase: migrationView, name: "v2", ddl: "create view v2 as select * from v1"},
+	}
+
+	order, err := sortMigrations(list)
+	assert.Equal(t, []string{"table/a", "table/b", "table/c", "view/v1", "view/v2"}, migrationNames(order))
+
+	var errCycle *ErrMigrationCycle
+	require.ErrorAs(t, err, &errCycle)
+	assert.Equal(t, [][]string{
+		{"table/a", "table/b", "table/a"},
+		{"view/v1", "view/v2", "view/v1"},
+	}, errCycle.Cycles)
+	assert.Equal(t, "cyclic dependencies of migration: table/a -> table/b -> table/a; view/v1 -> view/v2 -> view/v1",
+		err.Error())
+}
+
+func TestReadMigrations(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, migrationTable, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, migrationTable, "Orders.ddl"), []byte("create table orders()"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, migrationTable, "sub", "items.ddl"), []byte("create table items()"), 0o644))
+
+	list, err := readMigrations(root, []string{migrationTypes, migrationTable})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"table/orders", "table/items"}, migrationNames(list))
+	assert.Equal(t, "create table orders()", list[0].ddl)
+}
+
+func TestTableTriggers(t *testing.T) {
+	list := []*migration{
+		{phase: migrationTable, name: "orders", ddl: `create table orders (id int);
+create trigger orders_audit after insert on public.orders execute function audit();
+create trigger broken after on orders`},
+		{phase: migrationTrigger, name: "orders", ddl: "create trigger orders_num before insert on orders execute function num()"},
+	}
+
+	assert.Equal(t, map[string][]string{"orders": {"orders_audit"}}, tableTriggers(list))
+}
+
+func TestSortMigrations_noFalseDependencies(t *testing.T) {
+	list := []*migration{
+		{phase: migrationTable, name: "orders", ddl: `create table orders (
+	id int,
+	total numeric(10, 2) default round(0, 2),
+	item_id int references items(id),
+	primary key (id)
+)`},
+		{phase: migrationTable, name: "items", ddl: "create table items (id int primary key, code varchar(10))"},
+		// functions which have names of types & relations used with brackets by tables
+		{phase: migrationFunc, name: "items", ddl: "create function items() returns setof orders as 'select 1' language sql"},
+		{phase: migrationFunc, name: "varchar", ddl: "create function varchar(a orders) returns text as 'select 1' language sql"},
+		{phase: migrationFunc, name: "round", ddl: "create function round(a int, b int) returns int as 'select 1' language sql"},
+	}
+
+	byName := map[string][]int{"orders": {0}, "items": {1, 2}, "varchar": {3}, "round": {4}}
+	assert.ElementsMatch(t, []int{1, 4}, list[0].dependencies(list, byName))
+	assert.Empty(t, list[1].dependencies(list, byName))
+
+	order, err := sortMigrations(list)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"table/items", "func/round", "table/orders", "func/items", "func/varchar"}, migrationNames(order))
+}
+
+func TestDB_readCfg_cycle(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, migrationView), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, migrationView, "v1.ddl"), []byte("create view v1 as select * from v2"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, migrationView, "v2.ddl"), []byte("create view v2 as select * from v1"), 0o644))
+
+	conn := &execConn{}
+	db := &DB{Conn: conn, ctx: context.Background(), Cfg: map[string]any{}}
+	err := db.readCfg(context.Background(), &CfgDB{PathCfg: &root})
+
+	var errCycle *ErrMigrationCycle
+	require.ErrorAs(t, err, &errCycle)
+	assert.Empty(t, conn.ddl)
+}
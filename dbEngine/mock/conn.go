@@ -5,117 +5,366 @@
 package mock
 
 import (
-	"github.com/jackc/pgtype/pgxtype"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
-	"github.com/stretchr/testify/mock"
 	"golang.org/x/net/context"
 
-	"github.com/ruslanBik4/logs"
-
 	"github.com/ruslanBik4/dbEngine/dbEngine"
 )
 
-// Conn for mock connection
+// Conn for mock connection, it checks calls according to expectations (see ExpectQuery & ExpectExec)
+// & returns their results, by default calls must go in order of expectations
 type Conn struct {
-	pgxtype.Querier
-	mock.Mock
+	Tables          map[string]dbEngine.Table
+	Routines        map[string]dbEngine.Routine
+	expectations    []*Expectation
+	unordered       bool
+	lastRowAffected int64
+	lock            sync.Mutex
 }
 
-// SelectAndPerformRaw  run sql with args & run each every row
-func (c *Conn) SelectAndPerformRaw(ctx context.Context, each dbEngine.FncRawRow, sql string, args ...interface{}) error {
+// NewConn create mock connection without expectations
+func NewConn() *Conn {
+	return &Conn{
+		Tables:   make(map[string]dbEngine.Table),
+		Routines: make(map[string]dbEngine.Routine),
+	}
+}
 
-	return c.chkSqlAndArgs(ctx, sql, args)
+// ExpectQuery adds expectation of Select* call which sql matches regexp sqlPattern
+func (c *Conn) ExpectQuery(sqlPattern string) *Expectation {
+	return c.expect(kindQuery, sqlPattern)
 }
 
-func (c *Conn) chkSqlAndArgs(ctx context.Context, sql string, args []interface{}) error {
-	if !regSQl.MatchString(sql) {
-		return errors.New(sql)
-	}
+// ExpectExec adds expectation of ExecDDL or Exec call which sql matches regexp sqlPattern
+func (c *Conn) ExpectExec(sqlPattern string) *Expectation {
+	return c.expect(kindExec, sqlPattern)
+}
+
+func (c *Conn) expect(kind, sqlPattern string) *Expectation {
+	e := &Expectation{kind: kind, sql: regexp.MustCompile(sqlPattern)}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.expectations = append(c.expectations, e)
 
-	if !c.Mock.Called().Is(args...) {
-		logs.DebugLog("args failed")
+	return e
+}
+
+// MatchExpectationsInOrder switches checking order of calls, it's on by default
+func (c *Conn) MatchExpectationsInOrder(inOrder bool) {
+	c.unordered = !inOrder
+}
+
+// ExpectationsWereMet return ErrUnmetExpectations if some expectations weren't triggered
+func (c *Conn) ExpectationsWereMet() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	unmet := make(ErrUnmetExpectations, 0)
+	for _, e := range c.expectations {
+		if !e.triggered {
+			unmet = append(unmet, e)
+		}
 	}
 
-	c.Calls[0].RunFn = func(args mock.Arguments) {
-		logs.StatusLog(args.String(), ctx.Value(CONN_MOCK_ENV))
+	if len(unmet) > 0 {
+		return unmet
 	}
 
 	return nil
 }
 
-// InitConn create pool of connection
+// call finds expectation for call & marks it as triggered
+func (c *Conn) call(kind, sql string, args []any) (*Expectation, error) {
+	// pgx options aren't arguments of query
+	queryArgs := make([]any, 0, len(args))
+	for _, arg := range args {
+		if _, ok := arg.(pgx.QuerySimpleProtocol); !ok {
+			queryArgs = append(queryArgs, arg)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	reason := "all expectations were already triggered"
+	for _, e := range c.expectations {
+		if e.triggered {
+			continue
+		}
+
+		err := e.match(kind, sql, queryArgs)
+		if err == nil {
+			e.triggered = true
+			if kind == kindExec {
+				c.lastRowAffected = e.rowsAffected
+			}
+
+			return e, e.err
+		}
+
+		reason = err.Error()
+		if !c.unordered {
+			break
+		}
+	}
+
+	return nil, ErrUnexpectedCall{SQL: sql, Args: queryArgs, Reason: reason}
+}
+
+// query performs each for every row of expected query
+func (c *Conn) query(ctx context.Context, each dbEngine.FncEachRow, sql string, args []any) error {
+	_, err := c.queryColumns(ctx, each, sql, args)
+	return err
+}
+
+// queryColumns performs each for every row of expected query & return its columns
+func (c *Conn) queryColumns(ctx context.Context, each dbEngine.FncEachRow, sql string, args []any) ([]dbEngine.Column, error) {
+	e, err := c.call(kindQuery, sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range e.rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(row) != len(e.columns) {
+			return nil, errors.Errorf("row %v has %d values, expected %d columns", row, len(row), len(e.columns))
+		}
+
+		if err := each(row, e.columns); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.columns, nil
+}
+
+// InitConn do nothing
 func (c *Conn) InitConn(ctx context.Context, dbURL string) error {
-	logs.DebugLog(dbURL)
 	return nil
 }
 
-// GetRoutines get properties of DB routines & returns them as map
-func (c *Conn) GetRoutines(ctx context.Context, dbTypes map[string]dbEngine.Types, tables map[string]dbEngine.Table) (map[string]dbEngine.Routine, error) {
-	panic("implement me")
+// Ping mock checking of connection
+func (c *Conn) Ping(ctx context.Context) error {
+	return nil
 }
 
-// GetSchema read DB schema & store it
-func (c *Conn) GetSchema(ctx context.Context, cfg ...*dbEngine.CfgDB) (map[string]*string, map[string]dbEngine.Table, map[string]dbEngine.Routine, map[string]dbEngine.Types, error) {
-	panic("implement me")
+// GetRoutines return Routines of mock
+func (c *Conn) GetRoutines(ctx context.Context, dbTypes map[string]dbEngine.Types, tables map[string]dbEngine.Table,
+	cfg *dbEngine.CfgDB) (map[string]dbEngine.Routine, error) {
+	return c.Routines, nil
 }
 
-// GetStat return stats of Pool
-func (c *Conn) GetStat() string {
-	panic("implement me")
+// GetSchema return Tables & Routines of mock
+func (c *Conn) GetSchema(ctx context.Context, cfg *dbEngine.CfgDB) (database map[string]*string, tables map[string]dbEngine.Table,
+	routines map[string]dbEngine.Routine, dbTypes map[string]dbEngine.Types, err error) {
+	name, schema := "mock", "public"
+	database = map[string]*string{
+		"db_name":   &name,
+		"db_schema": &schema,
+	}
+
+	return database, c.Tables, c.Routines, make(map[string]dbEngine.Types), nil
 }
 
-// Exec mock exec command
-func (c *Conn) Exec(ctx context.Context, sql string, args ...interface{}) error {
-	logs.StatusLog(sql)
-	if regSQl.MatchString(sql) {
-		return nil
+// GetStat return count of expectations
+func (c *Conn) GetStat() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	triggered := 0
+	for _, e := range c.expectations {
+		if e.triggered {
+			triggered++
+		}
 	}
 
-	return errors.New(sql)
+	return fmt.Sprintf("mock: %d/%d expectations triggered", triggered, len(c.expectations))
+}
+
+// Exec mock exec command
+func (c *Conn) Exec(ctx context.Context, sql string, args ...any) error {
+	_, err := c.call(kindExec, sql, args)
+	return err
 }
 
 // ExecDDL execute sql
-func (c *Conn) ExecDDL(ctx context.Context, sql string, args ...interface{}) error {
-	return c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) ExecDDL(ctx context.Context, sql string, args ...any) error {
+	_, err := c.call(kindExec, sql, args)
+	return err
 }
 
-// NewTable create new empty Table with name & type
+// NewTable return table from Tables or create new mock Table with name & type
 func (c *Conn) NewTable(name, typ string) dbEngine.Table {
-	panic("implement me")
+	if table, ok := c.Tables[name]; ok {
+		return table
+	}
+
+	return NewTable(name, typ, "")
 }
 
-// LastRowAffected return number of insert/deleted/updated rows
+// LastRowAffected return number of rows of last expected exec (see WillReturnResult)
 func (c *Conn) LastRowAffected() int64 {
-	panic("implement me")
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lastRowAffected
 }
 
 // SelectOneAndScan run sql with Options & return rows into rowValues
-func (c *Conn) SelectOneAndScan(ctx context.Context, rowValues interface{}, sql string, args ...interface{}) error {
-	return c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) SelectOneAndScan(ctx context.Context, rowValues any, sql string, args ...any) error {
+	found := false
+	err := c.query(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			if found {
+				return nil
+			}
+			found = true
+
+			var dest []any
+			switch r := rowValues.(type) {
+			case []any:
+				dest = r
+			case dbEngine.RowScanner:
+				dest = r.GetFields(columns)
+			default:
+				dest = []any{r}
+			}
+
+			return dbEngine.ScanRow(dest, values, columns)
+		},
+		sql,
+		args)
+	if err == nil && !found {
+		return pgx.ErrNoRows
+	}
+
+	return err
 }
 
 // SelectAndScanEach run sql with Options & return every row into rowValues & run each
-func (c *Conn) SelectAndScanEach(ctx context.Context, each func() error, rowValue dbEngine.RowScanner, sql string, args ...interface{}) error {
-	return c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) SelectAndScanEach(ctx context.Context, each func() error, rowValue dbEngine.RowScanner, sql string, args ...any) error {
+	return c.query(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			if err := dbEngine.ScanRow(rowValue.GetFields(columns), values, columns); err != nil {
+				return err
+			}
+
+			if each != nil {
+				return each()
+			}
+
+			return nil
+		},
+		sql,
+		args)
 }
 
 // SelectAndRunEach run sql with Options & performs each every row of query results
-func (c *Conn) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, sql string, args ...interface{}) error {
-	return c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, sql string, args ...any) error {
+	return c.query(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			if each != nil {
+				return each(values, columns)
+			}
+
+			return nil
+		},
+		sql,
+		args)
+}
+
+// SelectAndPerformRaw run sql with args & run each every row with values in text format
+func (c *Conn) SelectAndPerformRaw(ctx context.Context, each dbEngine.FncRawRow, sql string, args ...any) error {
+	return c.query(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			raw := make([][]byte, len(values))
+			for i, value := range values {
+				raw[i] = dbEngine.FormatRaw(value)
+			}
+
+			return each(raw, columns)
+		},
+		sql,
+		args)
 }
 
 // SelectToMap run sql with args return rows as map[{name_column}]
 // case of executed - gets one record
-func (c *Conn) SelectToMap(ctx context.Context, sql string, args ...interface{}) (map[string]interface{}, error) {
-	return nil, c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) SelectToMap(ctx context.Context, sql string, args ...any) (map[string]any, error) {
+	var row map[string]any
+	err := c.query(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			if row != nil {
+				return nil
+			}
+
+			row = make(map[string]any, len(values))
+			for i, value := range values {
+				row[columns[i].Name()] = value
+			}
+
+			return nil
+		},
+		sql,
+		args)
+	if err != nil {
+		return nil, err
+	}
+
+	// same as memdb & psql
+	if row == nil {
+		return nil, pgx.ErrNoRows
+	}
+
+	return row, nil
 }
 
 // SelectToMaps run sql with args return rows as slice of map[{name_column}]
-func (c *Conn) SelectToMaps(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
-	return nil, c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) SelectToMaps(ctx context.Context, sql string, args ...any) ([]map[string]any, error) {
+	rows := make([]map[string]any, 0)
+	err := c.query(ctx,
+		func(values []any, columns []dbEngine.Column) error {
+			row := make(map[string]any, len(values))
+			for i, value := range values {
+				row[columns[i].Name()] = value
+			}
+			rows = append(rows, row)
+
+			return nil
+		},
+		sql,
+		args)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
 }
 
 // SelectToMultiDimension run sql with args and return rows (slice of record) and columns
-func (c *Conn) SelectToMultiDimension(ctx context.Context, sql string, args ...interface{}) ([][]interface{}, []dbEngine.Column, error) {
-	return nil, nil, c.chkSqlAndArgs(ctx, sql, args)
+func (c *Conn) SelectToMultiDimension(ctx context.Context, sql string, args ...any) ([][]any, []dbEngine.Column, error) {
+	rows := make([][]any, 0)
+	columns, err := c.queryColumns(ctx,
+		func(values []any, _ []dbEngine.Column) error {
+			rows = append(rows, values)
+
+			return nil
+		},
+		sql,
+		args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rows, columns, nil
 }
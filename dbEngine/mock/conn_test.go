@@ -0,0 +1,87 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+func TestConn_Expectations(t *testing.T) {
+	var _ dbEngine.Connection = (*Conn)(nil)
+
+	ctx := context.Background()
+	c := NewConn()
+	c.ExpectQuery(`^SELECT id, name FROM users WHERE id > \$1`).
+		WithArgs(1).
+		WillReturnRows([]string{"id", "name"}, []any{2, "bob"}, []any{3, "carl"})
+	c.ExpectExec(`^UPDATE users`).WithArgs("ann", AnyArg()).WillReturnResult(2)
+	errDenied := errors.New("permission denied")
+	c.ExpectExec(`^DROP TABLE`).WillReturnError(errDenied)
+	c.ExpectQuery(`FROM users`).WillReturnRows([]string{"name"})
+	c.ExpectQuery(`FROM users`).WillReturnRows([]string{"name"})
+	c.ExpectQuery(`FROM users`).WillReturnRows([]string{"name"}, []any{"ann"}, []any{"bob"})
+
+	rows, err := c.SelectToMaps(ctx, "SELECT id, name FROM users WHERE id > $1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": 2, "name": "bob"}, {"id": 3, "name": "carl"}}, rows)
+
+	require.NoError(t, c.ExecDDL(ctx, "UPDATE users SET name=$1 WHERE id=$2", "ann", 3))
+	assert.EqualValues(t, 2, c.LastRowAffected())
+
+	assert.Equal(t, errDenied, c.ExecDDL(ctx, "DROP TABLE users"))
+
+	var name string
+	assert.Equal(t, pgx.ErrNoRows, c.SelectOneAndScan(ctx, &name, "SELECT name FROM users"))
+
+	_, err = c.SelectToMap(ctx, "SELECT name FROM users")
+	assert.Equal(t, pgx.ErrNoRows, err)
+	row, err := c.SelectToMap(ctx, "SELECT name FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "ann"}, row)
+
+	require.NoError(t, c.ExpectationsWereMet())
+}
+
+func TestConn_Unexpected(t *testing.T) {
+	ctx := context.Background()
+	c := NewConn()
+	c.ExpectQuery(`FROM users`).WithArgs(1).WillReturnRows([]string{"id"}, []any{1})
+	c.ExpectExec(`^DELETE`)
+
+	// wrong order
+	err := c.ExecDDL(ctx, "DELETE FROM users")
+	var errCall ErrUnexpectedCall
+	require.ErrorAs(t, err, &errCall)
+	assert.Equal(t, "DELETE FROM users", errCall.SQL)
+
+	// wrong args
+	_, _, err = c.SelectToMultiDimension(ctx, "SELECT id FROM users WHERE id=$1", 2)
+	assert.ErrorAs(t, err, &errCall)
+
+	c.MatchExpectationsInOrder(false)
+	require.NoError(t, c.ExecDDL(ctx, "DELETE FROM users"))
+
+	err = c.ExpectationsWereMet()
+	var unmet ErrUnmetExpectations
+	require.ErrorAs(t, err, &unmet)
+	assert.Len(t, unmet, 1)
+
+	rows, columns, err := c.SelectToMultiDimension(ctx, "SELECT id FROM users WHERE id=$1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]any{{1}}, rows)
+	assert.Equal(t, "id", columns[0].Name())
+
+	_, err = c.SelectToMap(ctx, "SELECT id FROM users")
+	assert.ErrorAs(t, err, &errCall)
+	require.NoError(t, c.ExpectationsWereMet())
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// kinds of expected calls
+const (
+	kindQuery = "query"
+	kindExec  = "exec"
+)
+
+// Argument matches argument of call, use it in WithArgs instead of value (see AnyArg)
+type Argument interface {
+	Match(v any) bool
+}
+
+type anyArg struct{}
+
+// Match implement Argument interface
+func (anyArg) Match(v any) bool {
+	return true
+}
+
+// AnyArg return Argument which matches any value
+func AnyArg() Argument {
+	return anyArg{}
+}
+
+// Expectation describes call of Conn which test waits for & its result
+type Expectation struct {
+	kind         string
+	sql          *regexp.Regexp
+	args         []any
+	hasArgs      bool
+	columns      []dbEngine.Column
+	rows         [][]any
+	err          error
+	rowsAffected int64
+	triggered    bool
+}
+
+// WithArgs set expected arguments of call, expectation without them matches any arguments
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.hasArgs = true
+
+	return e
+}
+
+// WillReturnRows set columns & rows which query returns
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+	e.columns = make([]dbEngine.Column, len(columns))
+	for i, name := range columns {
+		e.columns[i] = dbEngine.NewStringColumn(name, "", false)
+	}
+	e.rows = rows
+
+	return e
+}
+
+// WillReturnResult set number of rows which exec changes (see LastRowAffected)
+func (e *Expectation) WillReturnResult(rowsAffected int64) *Expectation {
+	e.rowsAffected = rowsAffected
+
+	return e
+}
+
+// WillReturnError set error which call returns
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+
+	return e
+}
+
+// String implement fmt.Stringer
+func (e *Expectation) String() string {
+	s := fmt.Sprintf("%s '%s'", e.kind, e.sql)
+	if e.hasArgs {
+		s += fmt.Sprintf(" with args %v", e.args)
+	}
+
+	return s
+}
+
+// match check kind, sql & arguments of call
+func (e *Expectation) match(kind, sql string, args []any) error {
+	if e.kind != kind {
+		return fmt.Errorf("call %s, expected %s", kind, e)
+	}
+
+	if !e.sql.MatchString(sql) {
+		return fmt.Errorf("sql '%s' doesn't match %s", sql, e)
+	}
+
+	if !e.hasArgs {
+		return nil
+	}
+
+	if len(args) != len(e.args) {
+		return fmt.Errorf("%d args %v, expected %s", len(args), args, e)
+	}
+
+	for i, arg := range e.args {
+		if m, ok := arg.(Argument); ok {
+			if !m.Match(args[i]) {
+				return fmt.Errorf("arg %d (%v) doesn't match %s", i+1, args[i], e)
+			}
+		} else if !reflect.DeepEqual(arg, args[i]) {
+			return fmt.Errorf("arg %d is %#v, expected %#v for %s", i+1, args[i], arg, e)
+		}
+	}
+
+	return nil
+}
+
+// ErrUnexpectedCall means that call of Conn doesn't match expectations
+type ErrUnexpectedCall struct {
+	SQL    string
+	Args   []any
+	Reason string
+}
+
+// Error implement error interface
+func (err ErrUnexpectedCall) Error() string {
+	return fmt.Sprintf("unexpected call '%s' %v: %s", err.SQL, err.Args, err.Reason)
+}
+
+// ErrUnmetExpectations consists of expectations which weren't triggered
+type ErrUnmetExpectations []*Expectation
+
+// Error implement error interface
+func (err ErrUnmetExpectations) Error() string {
+	list := make([]string, len(err))
+	for i, e := range err {
+		list[i] = e.String()
+	}
+
+	return "there are unmet expectations: " + strings.Join(list, "; ")
+}
@@ -29,6 +29,36 @@ func (t *Table) Indexes() dbEngine.Indexes {
 	panic("implement me")
 }
 
+// PrimaryKey return columns of primary key of table
+func (t *Table) PrimaryKey() []dbEngine.Column {
+	return dbEngine.PrimaryColumns(t)
+}
+
+// UniqueConstraints return unique constraints of table
+func (t *Table) UniqueConstraints() dbEngine.Constraints {
+	return nil
+}
+
+// Constraints return primary key & unique constraints of table
+func (t *Table) Constraints() dbEngine.Constraints {
+	return dbEngine.KeyConstraints(t)
+}
+
+// ForeignKeys return foreign keys of table
+func (t *Table) ForeignKeys() dbEngine.ForeignKeys {
+	return dbEngine.ColumnsForeignKeys(t)
+}
+
+// Triggers return nil, table doesn't support triggers
+func (t *Table) Triggers() dbEngine.Triggers {
+	return nil
+}
+
+// Validate check values of columns according to their metadata before writing
+func (t *Table) Validate(columns []string, values []any) error {
+	return dbEngine.ValidateValues(t, columns, values)
+}
+
 // ReReadColumn renew properties of column 'name'
 func (t *Table) ReReadColumn(ctx context.Context, name string) dbEngine.Column {
 	//TODO implement me
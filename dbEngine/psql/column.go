@@ -31,6 +31,8 @@ type Column struct {
 	UdtName                string
 	characterMaximumLength int
 	autoInc                bool
+	identity               string
+	generationExpr         string
 	PrimaryKey             bool
 	Constraints            map[string]*dbEngine.ForeignKey
 	IsHidden               bool
@@ -52,8 +54,16 @@ func NewColumnForTableBuf(table *Table) *Column {
 	}
 }
 
-// Foreign return  first foreign key of column
+// Foreign return first foreign key of column (according to order of foreign keys of table if they were read)
 func (col *Column) Foreign() *dbEngine.ForeignKey {
+	if col.table != nil {
+		for _, fk := range col.table.foreignKeys {
+			if c := col.Constraints[fk.Name]; c != nil {
+				return c
+			}
+		}
+	}
+
 	for _, c := range col.Constraints {
 		if c != nil {
 			return c
@@ -70,7 +80,17 @@ func (col *Column) IsNullable() bool {
 
 // AutoIncrement return true if column is autoincrement
 func (col *Column) AutoIncrement() bool {
-	return col.autoInc
+	return col.autoInc || col.identity > ""
+}
+
+// Identity return 'ALWAYS' or 'BY DEFAULT' for identity column & empty string for other
+func (col *Column) Identity() string {
+	return col.identity
+}
+
+// GenerationExpression return expression of generated stored column
+func (col *Column) GenerationExpression() string {
+	return col.generationExpr
 }
 
 // Copy column & return new instance
@@ -87,6 +107,8 @@ func (col *Column) Copy() *Column {
 		UdtName:                col.UdtName,
 		characterMaximumLength: col.characterMaximumLength,
 		autoInc:                col.autoInc,
+		identity:               col.identity,
+		generationExpr:         col.generationExpr,
 		PrimaryKey:             col.PrimaryKey,
 		Constraints:            col.Constraints,
 		IsHidden:               col.IsHidden,
@@ -235,7 +257,12 @@ func (col *Column) Table() dbEngine.Table {
 }
 
 // UdtNameToType return types.BasicKind according to psql udtName
+// or mapping of dbEngine.RegisterType
 func UdtNameToType(udtName string, dbTypes map[string]dbEngine.Types, tables map[string]dbEngine.Table) types.BasicKind {
+	if t, ok := dbEngine.LookupType(udtName); ok {
+		return t.BasicKind
+	}
+
 	switch udtName {
 	case "bool":
 		return types.Bool
@@ -262,7 +289,7 @@ func UdtNameToType(udtName string, dbTypes map[string]dbEngine.Types, tables map
 		return types.String
 	case "bytea", "_bytea":
 		return types.UnsafePointer
-	case "inet", "interval":
+	case "interval":
 		return typesExt.TMap
 	case "anyrange":
 		return typesExt.TStruct
@@ -309,7 +336,10 @@ var dataTypeAlias = map[string][]string{
 
 // CheckAttr check attributes of column on DB schema according to ddl-file
 func (col *Column) CheckAttr(colDefine string) (flags []dbEngine.FlagColumn) {
-	colDefine = strings.ToLower(colDefine)
+	identity, colDefine := dbEngine.DefineIdentity(strings.ToLower(colDefine))
+	if identity != col.identity {
+		flags = append(flags, dbEngine.ChgIdentity)
+	}
 	// todo: add check arrays
 	lenCol := col.CharacterMaximumLength()
 	udtName := col.UdtName
@@ -334,7 +364,8 @@ func (col *Column) CheckAttr(colDefine string) (flags []dbEngine.FlagColumn) {
 		flags = append(flags, dbEngine.ChgType)
 	}
 
-	isNotNull := strings.Contains(colDefine, isNotNullable)
+	// identity column is always not null
+	isNotNull := strings.Contains(colDefine, isNotNullable) || identity > ""
 	if col.isNullable && isNotNull {
 		flags = append(flags, dbEngine.MustNotNull)
 	} else if !col.isNullable && !isNotNull {
@@ -342,7 +373,7 @@ func (col *Column) CheckAttr(colDefine string) (flags []dbEngine.FlagColumn) {
 	}
 
 	colDef, hasDefault := col.Default().(string)
-	if newDef := dbEngine.RegDefault.FindStringSubmatch(strings.ToLower(colDefine)); len(newDef) > 0 && (!hasDefault || strings.ToLower(colDef) != strings.Trim(newDef[1], "'\n")) {
+	if newDef := dbEngine.DefaultDDL(strings.ToLower(colDefine)); newDef > "" && (!hasDefault || strings.ToLower(colDef) != strings.Trim(newDef, "'\n")) {
 		flags = append(flags, dbEngine.ChgDefault)
 	}
 
@@ -402,7 +433,7 @@ func (col *Column) IsArray() bool {
 
 // Required return true if column need a value
 func (col *Column) Required() bool {
-	return !col.isNullable && (col.colDefault == nil)
+	return !col.isNullable && (col.colDefault == nil) && col.identity == "" && col.generationExpr == ""
 }
 
 // SetNullable set nullable flag of column
@@ -472,6 +503,10 @@ func (col *Column) RefColValue(name string) any {
 		return &col.Constraints
 	case "ordinal_position":
 		return &col.Position
+	case "identity_generation":
+		return &col.identity
+	case "generation_expression":
+		return &col.generationExpr
 	default:
 		panic("not implement scan for field " + name)
 	}
@@ -1393,3 +1393,75 @@ func TestTable_readColumnRow(t1 *testing.T) {
 		})
 	}
 }
+
+func TestColumn_Identity(t *testing.T) {
+	tests := []struct {
+		name      string
+		col       *Column
+		colDefine string
+		wantFlags []dbEngine.FlagColumn
+		wantAuto  bool
+		wantGen   bool
+	}{
+		{
+			name:      "identity always",
+			col:       &Column{name: "id", DataType: "integer", UdtName: "int4", identity: dbEngine.IdentityAlways},
+			colDefine: "integer generated always as identity",
+			wantAuto:  true,
+			wantGen:   true,
+		},
+		{
+			name:      "identity by default",
+			col:       &Column{name: "id", DataType: "integer", UdtName: "int4", identity: dbEngine.IdentityByDefault},
+			colDefine: "integer generated by default as identity",
+			wantAuto:  true,
+		},
+		{
+			name:      "add identity",
+			col:       &Column{name: "id", DataType: "integer", UdtName: "int4", colDefault: "id_seq", autoInc: true},
+			colDefine: "integer GENERATED ALWAYS AS IDENTITY",
+			wantFlags: []dbEngine.FlagColumn{dbEngine.ChgIdentity},
+			wantAuto:  true,
+		},
+		{
+			name:      "drop identity",
+			col:       &Column{name: "id", DataType: "integer", UdtName: "int4", identity: dbEngine.IdentityAlways},
+			colDefine: "integer",
+			wantFlags: []dbEngine.FlagColumn{dbEngine.ChgIdentity, dbEngine.Nullable},
+			wantAuto:  true,
+			wantGen:   true,
+		},
+		{
+			name:      "generated stored",
+			col:       &Column{name: "total", DataType: "numeric", UdtName: "numeric", isNullable: true, generationExpr: "(price * 2)"},
+			colDefine: "numeric generated always as (price * 2) stored",
+			wantGen:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantFlags, tt.col.CheckAttr(tt.colDefine))
+			assert.Equal(t, tt.wantAuto, tt.col.AutoIncrement())
+			assert.Equal(t, tt.wantGen, dbEngine.IsGeneratedColumn(tt.col))
+			assert.False(t, tt.col.Required())
+		})
+	}
+}
+
+func TestColumn_Foreign(t *testing.T) {
+	orders := &dbEngine.ForeignKey{Name: "a_orders_fk", Parent: "orders", Columns: []string{"order_id", "shop"},
+		ParentColumns: []string{"id", "shop"}}
+	shops := &dbEngine.ForeignKey{Name: "b_shops_fk", Parent: "shops", Columns: []string{"shop"},
+		ParentColumns: []string{"code"}}
+	table := &Table{name: "items", foreignKeys: dbEngine.ForeignKeys{orders, shops}}
+	col := &Column{table: table, name: "shop", Constraints: map[string]*dbEngine.ForeignKey{
+		"items_pkey":  nil,
+		"b_shops_fk":  shops.ForColumn("shop"),
+		"a_orders_fk": orders.ForColumn("shop"),
+	}}
+	table.columns = []*Column{col}
+
+	assert.Equal(t, orders.ForColumn("shop"), col.Foreign())
+	assert.Equal(t, dbEngine.ForeignKeys{orders, shops}, table.ForeignKeys())
+	assert.Nil(t, (&Column{table: table, name: "price"}).Foreign())
+}
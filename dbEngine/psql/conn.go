@@ -11,13 +11,13 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/pkg/errors"
-	"github.com/valyala/fasthttp"
 	"golang.org/x/net/context"
 
 	"github.com/ruslanBik4/dbEngine/dbEngine/csv"
@@ -61,13 +61,80 @@ func NoticeHandler(fnc pgconn.NoticeHandler) BuildConnOptions {
 	}
 }
 
-// NoticeHandler set same custom method on ApiRoute
+// Channels set list of channels for listening
 func Channels(channels ...string) BuildConnOptions {
 	return func(c *Conn) {
 		c.channels = channels
 	}
 }
 
+// MinConns set minimum size of connections pool
+func MinConns(n int32) BuildConnOptions {
+	return func(c *Conn) {
+		c.minConns = n
+	}
+}
+
+// MaxConns set maximum size of connections pool
+func MaxConns(n int32) BuildConnOptions {
+	return func(c *Conn) {
+		c.maxConns = n
+	}
+}
+
+// MaxConnLifetime set duration since creation after which a connection will be automatically closed
+func MaxConnLifetime(d time.Duration) BuildConnOptions {
+	return func(c *Conn) {
+		c.maxConnLifetime = d
+	}
+}
+
+// MaxConnIdleTime set duration after which an idle connection will be automatically closed
+func MaxConnIdleTime(d time.Duration) BuildConnOptions {
+	return func(c *Conn) {
+		c.maxConnIdleTime = d
+	}
+}
+
+// HealthCheckPeriod set duration between checks of the health of idle connections
+func HealthCheckPeriod(d time.Duration) BuildConnOptions {
+	return func(c *Conn) {
+		c.healthCheckPeriod = d
+	}
+}
+
+// AcquireTimeout set max duration of waiting free connection of pool,
+// zero value means no limit (except SelectOneAndScan, which waits 5s), negative value disables limit everywhere
+func AcquireTimeout(d time.Duration) BuildConnOptions {
+	return func(c *Conn) {
+		c.acquireTimeout = d
+	}
+}
+
+// StatementTimeout set 'statement_timeout' for every connection of pool
+func StatementTimeout(d time.Duration) BuildConnOptions {
+	return func(c *Conn) {
+		c.statementTimeout = d
+	}
+}
+
+// ApplicationName set 'application_name' for every connection of pool
+func ApplicationName(name string) BuildConnOptions {
+	return func(c *Conn) {
+		c.appName = name
+	}
+}
+
+// SearchPath set 'search_path' for every connection of pool
+func SearchPath(schemas ...string) BuildConnOptions {
+	return func(c *Conn) {
+		c.searchPath = strings.Join(schemas, ",")
+	}
+}
+
+// defaultAcquireTimeout uses by SelectOneAndScan when AcquireTimeout not set
+const defaultAcquireTimeout = time.Second * 5
+
 // Conn implement connection to DB over pgx
 type Conn struct {
 	*pgxpool.Pool
@@ -82,6 +149,21 @@ type Conn struct {
 	lastComTag     pgconn.CommandTag
 	Cancel         context.CancelFunc
 	lock           sync.RWMutex
+
+	minConns, maxConns                int32
+	maxConnLifetime, maxConnIdleTime  time.Duration
+	healthCheckPeriod, acquireTimeout time.Duration
+	statementTimeout                  time.Duration
+	appName, searchPath               string
+
+	listening map[string]bool
+	lastErr   error
+	lastErrAt time.Time
+
+	inFlight  atomic.Int64
+	listeners sync.WaitGroup
+	copyPools map[string]FncFlush
+	closed    atomic.Bool
 }
 
 // NewConn create new instance
@@ -113,24 +195,25 @@ func (c *Conn) InitConn(ctx context.Context, dbURL string) error {
 		return errors.Wrap(err, "cannot parse config")
 	}
 
-	// schema := os.Getenv("PGX_DB_SCHEMA")
-	// if schema > "" {
-	// 	poolCfg.
-	// }
-	maxConns := os.Getenv("PGX_MAX_CONNS")
-	if maxConns > "" {
-		i, err := strconv.Atoi(maxConns)
-		if err != nil {
-			logs.ErrorLog(err, maxConns)
-		} else {
-			poolCfg.MaxConns = int32(i)
-		}
+	if cfg, ok := ctx.Value(dbEngine.DB_SETTING).(dbEngine.CfgDB); ok {
+		c.applyCfgDB(&cfg)
 	}
+	c.setPoolConfig(poolCfg)
 
 	poolCfg.ConnConfig.LogLevel = SetLogLevel(os.Getenv("PGX_LOG"))
 	poolCfg.ConnConfig.Logger = &pgxLog{c}
 
-	poolCfg.AfterConnect = c.AfterConnect
+	poolCfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		if err := RegisterDataTypes(ctx, conn); err != nil {
+			return err
+		}
+
+		if c.AfterConnect != nil {
+			return c.AfterConnect(ctx, conn)
+		}
+
+		return nil
+	}
 	poolCfg.BeforeAcquire = c.BeforeAcquire
 	poolCfg.ConnConfig.OnNotice = func(conn *pgconn.PgConn, notice *pgconn.Notice) {
 		c.addNotice(conn.PID(), notice)
@@ -159,6 +242,124 @@ func (c *Conn) InitConn(ctx context.Context, dbURL string) error {
 	return nil
 }
 
+// applyCfgDB fills settings of pool from cfg, which were not set by BuildConnOptions
+func (c *Conn) applyCfgDB(cfg *dbEngine.CfgDB) {
+	if c.minConns == 0 {
+		c.minConns = cfg.MinConns
+	}
+	if c.maxConns == 0 {
+		c.maxConns = cfg.MaxConns
+	}
+	if c.maxConnLifetime == 0 {
+		c.maxConnLifetime = cfg.MaxConnLifetime
+	}
+	if c.maxConnIdleTime == 0 {
+		c.maxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if c.healthCheckPeriod == 0 {
+		c.healthCheckPeriod = cfg.HealthCheckPeriod
+	}
+	if c.acquireTimeout == 0 {
+		c.acquireTimeout = cfg.AcquireTimeout
+	}
+	if c.statementTimeout == 0 {
+		c.statementTimeout = cfg.StatementTimeout
+	}
+	if c.appName == "" {
+		c.appName = cfg.ApplicationName
+	}
+	if c.searchPath == "" {
+		c.searchPath = cfg.SearchPath
+	}
+}
+
+// setPoolConfig put settings of Conn into poolCfg
+// PGX_MAX_CONNS uses only if max connections not set otherwise
+func (c *Conn) setPoolConfig(poolCfg *pgxpool.Config) {
+	if maxConns := os.Getenv("PGX_MAX_CONNS"); maxConns > "" && c.maxConns == 0 {
+		i, err := strconv.Atoi(maxConns)
+		if err != nil {
+			logs.ErrorLog(err, maxConns)
+		} else {
+			poolCfg.MaxConns = int32(i)
+		}
+	}
+
+	if c.maxConns > 0 {
+		poolCfg.MaxConns = c.maxConns
+	}
+	if c.minConns > 0 {
+		poolCfg.MinConns = c.minConns
+	}
+	if c.maxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = c.maxConnLifetime
+	}
+	if c.maxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = c.maxConnIdleTime
+	}
+	if c.healthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = c.healthCheckPeriod
+	}
+
+	if poolCfg.ConnConfig.RuntimeParams == nil {
+		poolCfg.ConnConfig.RuntimeParams = make(map[string]string)
+	}
+	if c.statementTimeout > 0 {
+		poolCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(c.statementTimeout.Milliseconds(), 10)
+	}
+	if c.appName > "" {
+		poolCfg.ConnConfig.RuntimeParams["application_name"] = c.appName
+	}
+	if c.searchPath > "" {
+		poolCfg.ConnConfig.RuntimeParams["search_path"] = c.searchPath
+	}
+}
+
+// acquire get connection from pool with limit of waiting according to acquireTimeout
+func (c *Conn) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	return c.acquireWithin(ctx, c.acquireTimeout)
+}
+
+// acquireWithin get connection from pool waiting it no longer than timeout, zero or negative timeout means no limit
+func (c *Conn) acquireWithin(ctx context.Context, timeout time.Duration) (*pgxpool.Conn, error) {
+	if timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		ctx = timeoutCtx
+	}
+
+	conn, err := c.Acquire(ctx)
+	if err != nil {
+		c.setLastErr(err)
+		return nil, errors.Wrap(err, "c.Acquire")
+	}
+	c.inFlight.Add(1)
+
+	return conn, nil
+}
+
+// release return conn into pool & finish its in-flight query
+func (c *Conn) release(conn *pgxpool.Conn) {
+	conn.Release()
+	c.inFlight.Add(-1)
+}
+
+// exec run sql on pool as in-flight query
+func (c *Conn) exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
+	return c.Exec(ctx, sql, args...)
+}
+
+// setLastErr store err as last error of connection for HealthReport
+func (c *Conn) setLastErr(err error) {
+	c.lock.Lock()
+	c.lastErr = err
+	c.lastErrAt = time.Now()
+	c.lock.Unlock()
+}
+
 func (c *Conn) addNotice(pid uint32, notice *pgconn.Notice) {
 	c.lock.Lock()
 	c.NoticeMap[pid] = notice
@@ -262,6 +463,21 @@ func (c *Conn) GetTablesProp(ctx context.Context, dbTypes map[string]dbEngine.Ty
 				return errors.Wrap(err, "during get indexes")
 			}
 
+			err = t.GetConstraints(ctx)
+			if err != nil {
+				return errors.Wrap(err, "during get constraints")
+			}
+
+			err = t.GetForeignKeys(ctx)
+			if err != nil {
+				return errors.Wrap(err, "during get foreign keys")
+			}
+
+			err = t.GetTriggers(ctx)
+			if err != nil {
+				return errors.Wrap(err, "during get triggers")
+			}
+
 			tables[t.Name()] = t
 
 			return nil
@@ -359,6 +575,9 @@ func (c *Conn) GetRoutines(ctx context.Context, dbTypes map[string]dbEngine.Type
 			}
 
 			row.Comment, _ = values[5].(string)
+			if oid, ok := values[6].(int64); ok {
+				row.ID = int(oid)
+			}
 			name := values[1].(string)
 
 			fnc, ok := routines[name].(*Routine)
@@ -378,6 +597,24 @@ func (c *Conn) GetRoutines(ctx context.Context, dbTypes map[string]dbEngine.Type
 	return
 }
 
+// FindRoutine implements dbEngine.RoutineFinder, it return routine of DB with signature
+// 'name(types of input arguments)' or nil, DB resolves types by to_regprocedure
+func (c *Conn) FindRoutine(ctx context.Context, signature string) (dbEngine.RoutineDefinition, error) {
+	var id int64
+	err := c.SelectOneAndScan(ctx, &id, sqlFindRoutine, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, signature)
+	}
+
+	if id == 0 {
+		return nil, nil
+	}
+
+	name, _, _ := strings.Cut(signature, "(")
+
+	return &Routine{conn: c, name: name, ID: int(id), lock: &sync.RWMutex{}}, nil
+}
+
 // NewTable create new empty Table with name & type
 func (c *Conn) NewTable(name, typ string) dbEngine.Table {
 	return &Table{conn: c, name: name, Type: typ}
@@ -416,20 +653,36 @@ func (c *Conn) NewTableWithCheck(ctx context.Context, name string) (*Table, erro
 		return nil, errors.Wrap(err, "during get indexes")
 	}
 
+	err = table.GetConstraints(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "during get constraints")
+	}
+
+	err = table.GetForeignKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "during get foreign keys")
+	}
+
+	err = table.GetTriggers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "during get triggers")
+	}
+
 	return table, nil
 }
 
 // SelectAndPerformRaw  run sql with args & run each every row
 func (c *Conn) SelectAndPerformRaw(ctx context.Context, each dbEngine.FncRawRow, sql string, args ...any) error {
-	conn, err := c.Acquire(ctx)
+	conn, err := c.acquire(ctx)
 	if err != nil {
-		return errors.Wrap(err, "c.Acquire")
+		return err
 	}
 
-	defer conn.Release()
+	defer c.release(conn)
 
 	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
+		c.setLastErr(err)
 		logs.DebugLog(c.addNoticeToErrLog(conn, sql, args)...)
 		return err
 	}
@@ -449,6 +702,7 @@ func (c *Conn) SelectAndPerformRaw(ctx context.Context, each dbEngine.FncRawRow,
 
 	if rows.Err() != nil {
 		err = rows.Err()
+		c.setLastErr(err)
 	}
 
 	if err != nil {
@@ -463,15 +717,16 @@ func (c *Conn) SelectAndPerformRaw(ctx context.Context, each dbEngine.FncRawRow,
 func (c *Conn) SelectAndScanEach(ctx context.Context, each func() error, rowValue dbEngine.RowScanner,
 	sql string, args ...any) error {
 
-	conn, err := c.Acquire(ctx)
+	conn, err := c.acquire(ctx)
 	if err != nil {
-		return errors.Wrap(err, "c.Acquire")
+		return err
 	}
 
-	defer conn.Release()
+	defer c.release(conn)
 
 	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
+		c.setLastErr(err)
 		logs.DebugLog(c.addNoticeToErrLog(conn, sql, args)...)
 		return err
 	}
@@ -496,6 +751,7 @@ func (c *Conn) SelectAndScanEach(ctx context.Context, each func() error, rowValu
 
 	if rows.Err() != nil {
 		err = rows.Err()
+		c.setLastErr(err)
 	}
 
 	if err != nil {
@@ -516,16 +772,22 @@ func (c *Conn) SelectOneAndScan(ctx context.Context, rowValues any, sql string,
 		}
 	}
 
-	timeoutCtx, _ := context.WithTimeout(ctx, time.Second*5)
-	conn, err := c.Acquire(timeoutCtx)
+	// query of one row keeps its own limit of waiting free connection if AcquireTimeout isn't set
+	timeout := c.acquireTimeout
+	if timeout == 0 {
+		timeout = defaultAcquireTimeout
+	}
+
+	conn, err := c.acquireWithin(ctx, timeout)
 	if err != nil {
-		return errors.Wrap(err, "c.Acquire")
+		return err
 	}
 
-	defer conn.Release()
+	defer c.release(conn)
 
 	row, err := conn.Query(ctx, sql, args...)
 	if err != nil {
+		c.setLastErr(err)
 		logs.DebugLog(c.addNoticeToErrLog(conn, sql, args)...)
 		return err
 	}
@@ -553,12 +815,13 @@ func (c *Conn) SelectOneAndScan(ctx context.Context, rowValues any, sql string,
 	return row.Scan(dest...)
 }
 
-func (c *Conn) CopyCSV(ctx *fasthttp.RequestCtx, csv *csv.CsvReader) (string, error) {
-	conn, err := c.Acquire(ctx)
+// CopyCSV copies data of csv into its table by COPY FROM STDIN
+func (c *Conn) CopyCSV(ctx context.Context, csv *csv.CsvReader) (string, error) {
+	conn, err := c.acquire(ctx)
 	if err != nil {
 		return "", err
 	}
-	defer conn.Release()
+	defer c.release(conn)
 	b := &dbEngine.SQLBuilder{}
 	dbEngine.ColumnsForSelect(csv.Columns...)(b)
 
@@ -752,15 +1015,16 @@ func (c *Conn) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, s
 func (c *Conn) selectAndRunEach(ctx context.Context, each dbEngine.FncEachRow,
 	sql string, args ...any) error {
 
-	conn, err := c.Acquire(ctx)
+	conn, err := c.acquire(ctx)
 	if err != nil {
-		return errors.Wrap(err, "c.Acquire")
+		return err
 	}
 
-	defer conn.Release()
+	defer c.release(conn)
 
 	rows, err := conn.Query(ctx, sql, args...)
 	if err != nil {
+		c.setLastErr(err)
 		logs.DebugLog(c.addNoticeToErrLog(conn, sql, args)...)
 		return err
 	}
@@ -789,6 +1053,7 @@ func (c *Conn) selectAndRunEach(ctx context.Context, each dbEngine.FncEachRow,
 
 	if rows.Err() != nil {
 		err = rows.Err()
+		c.setLastErr(err)
 	}
 
 	if err != nil {
@@ -817,23 +1082,23 @@ func (c *Conn) getColumns(rows pgx.Rows, conn *pgxpool.Conn) []dbEngine.Column {
 
 // GetStat return stats of Pool
 func (c *Conn) GetStat() string {
-	s := c.Pool.Stat()
+	s := c.PoolStat()
 	return fmt.Sprintf("Acquired: %d/%d %v idle: %d, total: %d, max: %d",
-		s.AcquiredConns(),
-		s.AcquireCount(),
-		s.AcquireDuration(),
-		s.IdleConns(),
-		s.TotalConns(),
-		s.MaxConns(),
+		s.AcquiredConns,
+		s.AcquireCount,
+		s.AcquireDuration,
+		s.IdleConns,
+		s.TotalConns,
+		s.MaxConns,
 	)
 }
 
 // ExecDDL execute sql
 func (c *Conn) ExecDDL(ctx context.Context, sql string, args ...any) error {
-	comTag, err := c.Exec(ctx, sql, args...)
-	// if err != nil {
-	// 	logs.DebugLog("%v '%s' %s", comTag., err, strings.Split(sqlTypesList, "\n")[0])
-	// }
+	comTag, err := c.exec(ctx, sql, args...)
+	if err != nil {
+		c.setLastErr(err)
+	}
 
 	c.lastComTag = comTag
 
@@ -843,10 +1108,24 @@ func (c *Conn) ExecDDL(ctx context.Context, sql string, args ...any) error {
 // StartChannels starts listeners of PSQL channels according to list of channels
 func (c *Conn) StartChannels() {
 	for _, ch := range c.channels {
-		go c.listen(ch)
+		c.setListening(ch, false)
+		c.listeners.Add(1)
+		go func() {
+			defer c.listeners.Done()
+			c.listen(ch)
+		}()
 	}
 }
 
+func (c *Conn) setListening(ch string, ok bool) {
+	c.lock.Lock()
+	if c.listening == nil {
+		c.listening = make(map[string]bool, len(c.channels))
+	}
+	c.listening[ch] = ok
+	c.lock.Unlock()
+}
+
 // GetNotice return last notice of conn
 func (c *Conn) GetNotice(conn *pgxpool.Conn) (n *pgconn.Notice, ok bool) {
 	c.lock.RLock()
@@ -857,19 +1136,23 @@ func (c *Conn) GetNotice(conn *pgxpool.Conn) (n *pgconn.Notice, ok bool) {
 }
 
 func (c *Conn) listen(ch string) {
-	conn, err := c.Acquire(c.ctxPool)
+	conn, err := c.acquire(c.ctxPool)
 	if err != nil {
 		logs.ErrorLog(err, "Error acquiring connection:")
 		return
 	}
-	defer conn.Release()
+	defer c.release(conn)
 
 	cTag, err := conn.Exec(c.ctxPool, "listen "+ch)
 	if err != nil {
+		c.setLastErr(err)
 		logs.ErrorLog(err, "cannot open listen channel")
 		return
 	}
 
+	c.setListening(ch, true)
+	defer c.setListening(ch, false)
+
 	logs.StatusLog("%s chan %s", cTag, ch)
 	defer func() {
 		err := recover()
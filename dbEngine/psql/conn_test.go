@@ -0,0 +1,207 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package psql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+func TestConn_setPoolConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []BuildConnOptions
+		cfg     *dbEngine.CfgDB
+		env     string
+		check   func(t *testing.T, poolCfg *pgxpool.Config)
+	}{
+		{
+			name: "options",
+			options: []BuildConnOptions{
+				MinConns(2),
+				MaxConns(10),
+				MaxConnLifetime(time.Hour),
+				MaxConnIdleTime(time.Minute),
+				HealthCheckPeriod(time.Second * 30),
+				StatementTimeout(time.Second * 3),
+				ApplicationName("dbEngine"),
+				SearchPath("public", "ext"),
+			},
+			check: func(t *testing.T, poolCfg *pgxpool.Config) {
+				assert.Equal(t, int32(2), poolCfg.MinConns)
+				assert.Equal(t, int32(10), poolCfg.MaxConns)
+				assert.Equal(t, time.Hour, poolCfg.MaxConnLifetime)
+				assert.Equal(t, time.Minute, poolCfg.MaxConnIdleTime)
+				assert.Equal(t, time.Second*30, poolCfg.HealthCheckPeriod)
+				assert.Equal(t, "3000", poolCfg.ConnConfig.RuntimeParams["statement_timeout"])
+				assert.Equal(t, "dbEngine", poolCfg.ConnConfig.RuntimeParams["application_name"])
+				assert.Equal(t, "public,ext", poolCfg.ConnConfig.RuntimeParams["search_path"])
+			},
+		},
+		{
+			name:    "options override CfgDB",
+			options: []BuildConnOptions{MaxConns(7)},
+			cfg: &dbEngine.CfgDB{
+				MaxConns:        20,
+				MinConns:        3,
+				ApplicationName: "from cfg",
+			},
+			check: func(t *testing.T, poolCfg *pgxpool.Config) {
+				assert.Equal(t, int32(7), poolCfg.MaxConns)
+				assert.Equal(t, int32(3), poolCfg.MinConns)
+				assert.Equal(t, "from cfg", poolCfg.ConnConfig.RuntimeParams["application_name"])
+			},
+		},
+		{
+			name: "env PGX_MAX_CONNS",
+			env:  "12",
+			check: func(t *testing.T, poolCfg *pgxpool.Config) {
+				assert.Equal(t, int32(12), poolCfg.MaxConns)
+				assert.NotContains(t, poolCfg.ConnConfig.RuntimeParams, "statement_timeout")
+			},
+		},
+		{
+			name: "CfgDB override env PGX_MAX_CONNS",
+			env:  "12",
+			cfg:  &dbEngine.CfgDB{MaxConns: 5},
+			check: func(t *testing.T, poolCfg *pgxpool.Config) {
+				assert.Equal(t, int32(5), poolCfg.MaxConns)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PGX_MAX_CONNS", tt.env)
+			poolCfg, err := pgxpool.ParseConfig("postgres://localhost:5432/test")
+			require.NoError(t, err)
+
+			c := NewConnWithOptions(tt.options...)
+			if tt.cfg != nil {
+				c.applyCfgDB(tt.cfg)
+			}
+			c.setPoolConfig(poolCfg)
+			tt.check(t, poolCfg)
+		})
+	}
+}
+
+func TestConn_HealthReport(t *testing.T) {
+	c := NewConnWithOptions(Channels("events"))
+	c.setListening("events", true)
+	c.setLastErr(errors.New("last failure"))
+
+	report, err := c.HealthReport(context.Background())
+	assert.ErrorIs(t, err, ErrPoolNotInit)
+	require.NotNil(t, report)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, map[string]bool{"events": true}, report.Channels)
+	assert.Equal(t, ErrPoolNotInit.Error(), report.LastError)
+	assert.NotNil(t, report.LastErrorAt)
+
+	ctx := &fasthttp.RequestCtx{}
+	c.HealthHandler(ctx)
+	assert.Equal(t, fasthttp.StatusServiceUnavailable, ctx.Response.StatusCode())
+	assert.Contains(t, string(ctx.Response.Body()), `"healthy":false`)
+
+	ctx = &fasthttp.RequestCtx{}
+	c.PingHandler(ctx)
+	assert.Equal(t, fasthttp.StatusServiceUnavailable, ctx.Response.StatusCode())
+}
+
+func TestConn_Close(t *testing.T) {
+	c := NewConnWithOptions()
+	flushed := make([]string, 0)
+	c.RegisterCopyPool("users", func(ctx context.Context) error {
+		flushed = append(flushed, "users")
+		return nil
+	})
+	c.RegisterCopyPool("orders", func(ctx context.Context) error {
+		flushed = append(flushed, "orders")
+		return errors.New("duplicate key")
+	})
+	c.RegisterCopyPool("removed", func(ctx context.Context) error {
+		t.Error("unregistered pool must not be flushed")
+		return nil
+	})
+	c.UnregisterCopyPool("removed")
+
+	err := c.Close(context.Background())
+	var notFlushed ErrNotFlushed
+	require.ErrorAs(t, err, &notFlushed)
+	assert.Len(t, notFlushed.Pools, 1)
+	assert.Contains(t, notFlushed.Pools, "orders")
+	assert.ElementsMatch(t, []string{"users", "orders"}, flushed)
+	assert.Equal(t, "copy pools not flushed - orders: duplicate key", err.Error())
+
+	assert.NoError(t, c.Close(context.Background()), "second Close is no-op")
+
+	c = NewConnWithOptions()
+	c.inFlight.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, c.Close(ctx), context.DeadlineExceeded)
+}
+
+func TestConn_chkTimeout(t *testing.T) {
+	c := NewConnWithOptions(StatementTimeout(time.Second))
+
+	ctx, cancel, timeout := c.withTimeout(context.Background(), 0)
+	defer cancel()
+	assert.Equal(t, time.Second, timeout, "StatementTimeout is default")
+	_, ok := ctx.Deadline()
+	assert.True(t, ok)
+
+	ctx, cancel, timeout = c.withTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.Equal(t, time.Millisecond, timeout)
+	<-ctx.Done()
+
+	err := c.chkTimeout(ctx, errors.New("timeout: context deadline exceeded"), "users", timeout)
+	var errTimeout *dbEngine.ErrQueryTimeout
+	require.ErrorAs(t, err, &errTimeout)
+	assert.Equal(t, "users", errTimeout.Name)
+	assert.Equal(t, time.Millisecond, errTimeout.Timeout)
+
+	canceled := errors.Wrap(&pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"}, "sql")
+	err = c.chkTimeout(ctx, canceled, "get_users", timeout)
+	require.ErrorAs(t, err, &errTimeout)
+	assert.Equal(t, "get_users", errTimeout.Name)
+
+	// statement_timeout or pg_cancel_backend on server isn't timeout of query
+	ctx, cancel, timeout = c.withTimeout(context.Background(), 0)
+	defer cancel()
+	canceled = errors.Wrap(&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"}, "sql")
+	assert.Equal(t, canceled, c.chkTimeout(ctx, canceled, "get_users", timeout))
+
+	// deadline of caller isn't timeout of query
+	callerCtx, callerCancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer callerCancel()
+	ctx, cancel, timeout = c.withTimeout(callerCtx, time.Hour)
+	defer cancel()
+	<-ctx.Done()
+	deadlineErr := errors.New("timeout: context deadline exceeded")
+	assert.Equal(t, deadlineErr, c.chkTimeout(ctx, deadlineErr, "users", timeout))
+
+	someErr := errors.New("syntax error")
+	assert.Equal(t, someErr, c.chkTimeout(context.Background(), someErr, "users", 0))
+	assert.NoError(t, c.chkTimeout(ctx, nil, "users", 0))
+
+	c = NewConnWithOptions()
+	ctx = context.Background()
+	newCtx, cancel, timeout := c.withTimeout(ctx, 0)
+	defer cancel()
+	assert.Equal(t, ctx, newCtx, "no deadline without timeouts")
+	assert.Zero(t, timeout)
+}
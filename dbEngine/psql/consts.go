@@ -15,6 +15,10 @@ const (
        current_setting('work_mem') as work_mem, current_setting('datestyle') as datestyle,
        current_setting('port') as db_port,
        current_user as db_user`
+	sqlHealthReport = `SELECT current_setting('server_version'), pg_is_in_recovery(),
+       CASE WHEN pg_is_in_recovery()
+           THEN EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())::float8
+       END`
 	sqlTableList = `SELECT table_name, table_type,
 						COALESCE(pg_catalog.col_description((SELECT ('"' || TABLE_NAME || '"')::regclass::oid), 0), '')
 							AS comment
@@ -34,9 +38,10 @@ const (
 						FROM pg_catalog.pg_class c
 						WHERE c.relkind = 'm' AND c.relname = $1`
 	sqlRoutineList = `select specific_name, routine_name, routine_type, data_type, type_udt_name, 
-							(select d.description from pg_description d where d.objoid = p.oid FETCH FIRST 1 ROW ONLY)
+							(select d.description from pg_description d where d.objoid = p.oid FETCH FIRST 1 ROW ONLY),
+							p.oid::int8 as routine_oid
 					FROM INFORMATION_SCHEMA.routines r 
-							JOIN pg_proc p ON p.proname = r.routine_name
+							JOIN pg_proc p ON p.oid = (regexp_match(r.specific_name, '_(\d+)$'))[1]::oid
 							LEFT join pg_language l on p.prolang = l.oid
 					WHERE specific_schema = 'public' AND prokind != 'a'  and coalesce(data_type, 'null') != 'trigger' 
 							AND l.lanname = 'plpgsql' AND routine_name !~'(_final$)|(_state$)'
@@ -57,7 +62,9 @@ const (
 				LEFT JOIN INFORMATION_SCHEMA.key_column_usage kcu ON rc.unique_constraint_name = kcu.constraint_name
 			WHERE ( k.table_name=c.table_name AND k.column_name = c.column_name)
 		) as keys,
-		c.ordinal_position
+		c.ordinal_position,
+		COALESCE(c.identity_generation, '') identity_generation,
+		COALESCE(c.generation_expression, '') generation_expression
 FROM INFORMATION_SCHEMA.COLUMNS c
 WHERE c.table_schema='public' AND c.table_name=$1
 UNION ALL
@@ -79,7 +86,7 @@ UNION ALL
 		NULL,  true,  '', -1, 
         COALESCE(bt.typname, t.typname)::information_schema.sql_identifier,
 		COALESCE(pg_catalog.col_description((SELECT ('"' || $1 || '"')::regclass::oid), ordinal_position::int), ''),
-		NULL::json, ordinal_position
+		NULL::json, ordinal_position, '', ''
 	FROM pg_attribute a
 		JOIN LATERAL CAST(a.attnum as information_schema.cardinal_number) ordinal_position ON true
          JOIN (pg_class c JOIN pg_namespace nc ON c.relnamespace = nc.oid) ON a.attrelid = c.oid
@@ -115,7 +122,9 @@ ORDER BY ordinal_position`
 							WHERE ( k.table_name=c.table_name AND k.column_name = c.column_name)
 							) as keys,
 							COALESCE(pg_catalog.col_description((SELECT ('"' || $1 || '"')::regclass::oid), c.ordinal_position::int), '')
-							   AS column_comment
+							   AS column_comment,
+							COALESCE(c.identity_generation, '') identity_generation,
+							COALESCE(c.generation_expression, '') generation_expression
 						FROM INFORMATION_SCHEMA.COLUMNS C
 						WHERE C.table_schema='public' AND C.table_name=$1 AND C.COLUMN_NAME = $2`
 	sqlTypesList = `SELECT pg_type.oid, typname, typtype, relkind,
@@ -185,23 +194,118 @@ where nc.nspname='public' AND pg_type.typcategory != 'A' AND (typtype = ANY(arra
 	sqlGetIndexes = `SELECT i.relname as index_name,
 	   COALESCE( pg_get_expr( ix.indexprs, ix.indrelid ), '') as ind_expr,
        ix.indisunique as ind_unique,
+       COALESCE(am.amname, '') as ind_method,
        array_agg(a.attname order by array_positions(ix.indkey, a.attnum)) filter ( where a.attname > '' )  :: text[] as column_names
 FROM pg_index ix left join pg_class t on t.oid = ix.indrelid
      left join pg_class i on i.oid = ix.indexrelid
+     left join pg_am am on am.oid = i.relam
      left join  pg_attribute a on (a.attrelid = t.oid AND a.attnum = ANY(ix.indkey))
 where t.relname = $1
-group by 1,2,3
+group by 1,2,3,4
 UNION
 SELECT
     tc.constraint_name, 
     '',
     false,
+    '',
     array_agg(kcu.column_name)
 FROM
     information_schema.table_constraints AS tc
         JOIN information_schema.key_column_usage AS kcu
              USING (constraint_schema, constraint_name, table_name)
 WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_name=$1
-group by 1,2,3
+group by 1,2,3,4
 order by 1`
+	sqlGetConstraints = `SELECT c.conname as constraint_name, c.contype::text as constraint_type,
+		COALESCE(array_agg(a.attname::text ORDER BY k.ord) FILTER (WHERE a.attname IS NOT NULL), '{}') as column_names,
+		pg_get_constraintdef(c.oid) as definition
+FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	LEFT JOIN LATERAL unnest(c.conkey) WITH ORDINALITY k(attnum, ord) ON true
+	LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = 'public' AND t.relname = $1 AND c.contype IN ('p', 'u', 'c', 'x')
+GROUP BY c.oid, c.conname, c.contype
+ORDER BY c.contype, c.conname`
+	sqlGetForeignKeys = `SELECT c.conname as constraint_name, p.relname::text as parent,
+		array_agg(a.attname::text ORDER BY k.ord) as column_names,
+		array_agg(pa.attname::text ORDER BY k.ord) as parent_columns,
+		CASE c.confupdtype WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL'
+			WHEN 'd' THEN 'SET DEFAULT' ELSE 'NO ACTION' END as update_rule,
+		CASE c.confdeltype WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL'
+			WHEN 'd' THEN 'SET DEFAULT' ELSE 'NO ACTION' END as delete_rule,
+		CASE c.confmatchtype WHEN 'f' THEN 'FULL' WHEN 'p' THEN 'PARTIAL' ELSE 'SIMPLE' END as match_type,
+		c.condeferrable as deferrable, c.condeferred as initially_deferred
+FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	JOIN pg_class p ON p.oid = c.confrelid
+	CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY k(attnum, parent_attnum, ord)
+	JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+	JOIN pg_attribute pa ON pa.attrelid = c.confrelid AND pa.attnum = k.parent_attnum
+WHERE n.nspname = 'public' AND t.relname = $1 AND c.contype = 'f'
+GROUP BY c.oid, c.conname, p.relname
+ORDER BY c.conname`
+	sqlIsRoutineSignature   = `SELECT COALESCE(to_regprocedure($1)::oid::int8 = $2, false)`
+	sqlFindRoutine          = `SELECT COALESCE(to_regprocedure($1)::oid::int8, 0)`
+	sqlGetRoutineDefinition = `SELECT pg_get_functiondef($1::int8::oid)`
+	sqlGetRoutineDependents = `SELECT array_cat(
+	ARRAY(SELECT format('REVOKE ALL ON %s %s FROM PUBLIC', s.kind, s.signature)
+		WHERE p.proacl IS NOT NULL),
+	array_cat(
+		ARRAY(SELECT format('GRANT %s ON %s %s TO %s%s', a.privilege_type, s.kind, s.signature,
+				CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE quote_ident(r.rolname) END,
+				CASE WHEN a.is_grantable THEN ' WITH GRANT OPTION' ELSE '' END)
+			FROM aclexplode(p.proacl) a
+				LEFT JOIN pg_roles r ON r.oid = a.grantee
+			WHERE a.grantee <> p.proowner
+			ORDER BY 1),
+		ARRAY(SELECT format('COMMENT ON %s %s IS %L', s.kind, s.signature, d.description)
+			FROM pg_description d
+			WHERE d.objoid = p.oid AND d.classoid = 'pg_proc'::regclass)))
+FROM pg_proc p
+	CROSS JOIN LATERAL (SELECT CASE p.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END as kind,
+		p.oid::regprocedure::text as signature) s
+WHERE p.oid = $1::int8::oid`
+	sqlGetTriggers = `SELECT tg.tgname::text as trigger_name,
+		CASE WHEN tg.tgtype::int & 2 > 0 THEN 'BEFORE' WHEN tg.tgtype::int & 64 > 0 THEN 'INSTEAD OF' ELSE 'AFTER' END as timing,
+		array_remove(ARRAY[CASE WHEN tg.tgtype::int & 4 > 0 THEN 'INSERT' END, CASE WHEN tg.tgtype::int & 16 > 0 THEN 'UPDATE' END,
+			CASE WHEN tg.tgtype::int & 8 > 0 THEN 'DELETE' END, CASE WHEN tg.tgtype::int & 32 > 0 THEN 'TRUNCATE' END], NULL) as events,
+		tg.tgtype::int & 1 > 0 as for_each_row,
+		f.proname::text as function_name,
+		tg.tgenabled <> 'D' as enabled,
+		pg_get_triggerdef(tg.oid) as definition
+FROM pg_trigger tg
+	JOIN pg_class t ON t.oid = tg.tgrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	JOIN pg_proc f ON f.oid = tg.tgfoid
+WHERE n.nspname = 'public' AND t.relname = $1 AND NOT tg.tgisinternal
+ORDER BY tg.tgname`
+	sqlGetTypesOID = `SELECT oid, typname FROM pg_type WHERE typname = ANY($1)`
+	// name of constraint for normalizing definition of check by DB, it's added in transaction which is rolled back
+	tmpCheckDefinition    = "dbengine_check_definition"
+	sqlGetCheckDefinition = `SELECT pg_get_constraintdef(c.oid)
+FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = 'public' AND t.relname = $1 AND c.conname = '` + tmpCheckDefinition + `'`
+	// name of temporary view for normalizing definition of materialized view by DB
+	tmpMatViewDefinition    = "dbengine_matview_definition"
+	sqlCmpMatViewDefinition = `SELECT m.definition = pg_get_viewdef('pg_temp.` + tmpMatViewDefinition + `'::regclass)
+FROM pg_matviews m
+WHERE m.schemaname = 'public' AND m.matviewname = $1`
+	sqlGetMatViewDependents = `SELECT array_cat(
+	ARRAY(SELECT i.indexdef FROM pg_indexes i
+		WHERE i.schemaname = 'public' AND i.tablename = $1
+		ORDER BY i.indexname),
+	ARRAY(SELECT format('GRANT %s ON %s TO %s%s', a.privilege_type, quote_ident(c.relname),
+			CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE quote_ident(r.rolname) END,
+			CASE WHEN a.is_grantable THEN ' WITH GRANT OPTION' ELSE '' END)
+		FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			CROSS JOIN LATERAL aclexplode(c.relacl) a
+			LEFT JOIN pg_roles r ON r.oid = a.grantee
+		WHERE n.nspname = 'public' AND c.relname = $1 AND a.grantee <> c.relowner
+		ORDER BY 1)
+)`
 )
@@ -11,4 +11,5 @@ var (
 	ErrUnknownType               = errors.New("Can't define unknown type!")
 	ErrUnknownRoutineType        = errors.New("Can't add routine unknown type!")
 	ErrFunctionWithoutResultType = errors.New("Can't add function without results type!")
+	ErrPoolNotInit               = errors.New("Pool of connections isn't initialized!")
 )
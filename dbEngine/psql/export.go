@@ -0,0 +1,226 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package psql
+
+import (
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// ExportFormat is format of data for Table.Export
+type ExportFormat uint8
+
+const (
+	// ExportCSV writes header & rows as CSV, NULL writes as empty field
+	ExportCSV ExportFormat = iota
+	// ExportJSONLines writes every row as JSON object on separate line
+	ExportJSONLines
+	// ExportGob writes records by slices as gob values, such stream reads SaveDataToTable of generated Database
+	ExportGob
+)
+
+// ErrExportRecord means that gob export needs constructor of records
+var ErrExportRecord = errors.New("export into gob needs constructor of record")
+
+// Export writes rows of table selected according to Options into w with format,
+// it returns count of written rows
+func (t *Table) Export(ctx context.Context, w io.Writer, format ExportFormat, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	return t.ExportRecords(ctx, w, format, nil, Options...)
+}
+
+// ExportRecords is same as Export, newRecord creates record for every row during gob export,
+// it must return same type as elements of DoCopyPoll of generated table for compatibility with SaveDataToTable
+func (t *Table) ExportRecords(ctx context.Context, w io.Writer, format ExportFormat, newRecord func() dbEngine.RowScanner,
+	Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	b, err := dbEngine.NewSQLBuilder(t, Options...)
+	if err != nil {
+		return 0, errors.Wrap(err, "setOption")
+	}
+
+	sql, err := b.SelectSql()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	var count int64
+	switch format {
+	case ExportCSV:
+		count, err = t.exportCSV(ctx, w, b, sql)
+	case ExportJSONLines:
+		count, err = t.exportJSONLines(ctx, w, b, sql)
+	case ExportGob:
+		if newRecord == nil {
+			return 0, ErrExportRecord
+		}
+		count, err = t.exportGob(ctx, w, newRecord, b, sql)
+	default:
+		return 0, dbEngine.NewErrWrongType("ExportFormat", t.name, "format")
+	}
+
+	return count, t.conn.chkTimeout(ctx, err, t.name, timeout)
+}
+
+func (t *Table) exportCSV(ctx context.Context, w io.Writer, b *dbEngine.SQLBuilder, sql string) (int64, error) {
+	columns := b.SelectColumns()
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name()
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	count := int64(0)
+	record := make([]string, len(columns))
+	// simple protocol returns values in text format
+	args := append([]any{pgx.QuerySimpleProtocol(true)}, b.Args...)
+	err := t.conn.SelectAndPerformRaw(ctx,
+		func(values [][]byte, _ []dbEngine.Column) error {
+			for i, value := range values {
+				record[i] = string(value)
+			}
+			count++
+
+			return writer.Write(record)
+		},
+		sql,
+		args...)
+	if err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+
+	return count, writer.Error()
+}
+
+func (t *Table) exportJSONLines(ctx context.Context, w io.Writer, b *dbEngine.SQLBuilder, sql string) (int64, error) {
+	count := int64(0)
+	columns := b.SelectColumns()
+	enc := json.NewEncoder(w)
+	err := t.conn.selectAndRunEach(ctx,
+		func(values []any, _ []dbEngine.Column) error {
+			row := make(orderedRow, len(values))
+			for i, value := range values {
+				row[i] = rowField{name: columns[i].Name(), value: value}
+			}
+			count++
+
+			return enc.Encode(row)
+		},
+		sql,
+		b.Args...)
+
+	return count, err
+}
+
+func (t *Table) exportGob(ctx context.Context, w io.Writer, newRecord func() dbEngine.RowScanner, b *dbEngine.SQLBuilder, sql string) (int64, error) {
+	count := int64(0)
+	scanner := &recordsScanner{
+		newRecord: newRecord,
+		records:   reflectRecords(newRecord),
+		enc:       gob.NewEncoder(w),
+	}
+	err := t.conn.SelectAndScanEach(ctx,
+		func() error {
+			count++
+
+			return scanner.flush(gobBatchSize)
+		},
+		scanner,
+		sql,
+		b.Args...)
+	if err != nil {
+		return count, err
+	}
+
+	// rest of records, table without rows writes empty slice as before
+	if scanner.records.Len() > 0 || count == 0 {
+		err = scanner.flush(0)
+	}
+
+	return count, err
+}
+
+// gobBatchSize is count of records which exportGob encodes as one gob value
+const gobBatchSize = 1000
+
+// reflectRecords create empty slice of records type
+func reflectRecords(newRecord func() dbEngine.RowScanner) reflect.Value {
+	return reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(newRecord())), 0, gobBatchSize)
+}
+
+// recordsScanner collects new record for every row of query & encodes them by batches
+type recordsScanner struct {
+	newRecord func() dbEngine.RowScanner
+	records   reflect.Value
+	enc       *gob.Encoder
+}
+
+// GetFields implement dbEngine.RowScanner interface
+func (r *recordsScanner) GetFields(columns []dbEngine.Column) []any {
+	record := r.newRecord()
+	r.records = reflect.Append(r.records, reflect.ValueOf(record))
+
+	return record.GetFields(columns)
+}
+
+// flush encodes collected records as one slice if there are at least size of them
+func (r *recordsScanner) flush(size int) error {
+	if r.records.Len() < size {
+		return nil
+	}
+
+	err := r.enc.Encode(r.records.Interface())
+	r.records = r.records.Slice(0, 0)
+
+	return err
+}
+
+type rowField struct {
+	name  string
+	value any
+}
+
+// orderedRow marshals into JSON object with keys in order of columns
+type orderedRow []rowField
+
+// MarshalJSON implement json.Marshaler
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, field := range r {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, errors.Wrap(err, field.name)
+		}
+
+		buf = append(append(append(buf, name...), ':'), value...)
+	}
+
+	return append(buf, '}'), nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package psql
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+type exportFields struct {
+	Id   int32
+	Name string
+}
+
+func (r *exportFields) GetFields(columns []dbEngine.Column) []any {
+	return []any{&r.Id, &r.Name}
+}
+
+func TestOrderedRow_MarshalJSON(t *testing.T) {
+	row := orderedRow{
+		{name: "name", value: "test"},
+		{name: "id", value: 1},
+		{name: "memo", value: nil},
+	}
+	b, err := json.Marshal(row)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"test","id":1,"memo":null}`, string(b))
+}
+
+// decodeRecords reads gob stream same as SaveDataToTable of generated Database
+func decodeRecords(t *testing.T, r io.Reader) []*exportFields {
+	var poll []*exportFields
+	dec := gob.NewDecoder(r)
+	for {
+		var records []*exportFields
+		err := dec.Decode(&records)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		poll = append(poll, records...)
+	}
+
+	return poll
+}
+
+func TestRecordsScanner_gob(t *testing.T) {
+	buf := &bytes.Buffer{}
+	scanner := &recordsScanner{
+		newRecord: func() dbEngine.RowScanner {
+			return &exportFields{}
+		},
+		enc: gob.NewEncoder(buf),
+	}
+	scanner.records = reflectRecords(scanner.newRecord)
+	for i, name := range []string{"first", "second", "third"} {
+		fields := scanner.GetFields(nil)
+		*(fields[0].(*int32)) = int32(i + 1)
+		*(fields[1].(*string)) = name
+		require.NoError(t, scanner.flush(2))
+	}
+	require.NoError(t, scanner.flush(0))
+
+	assert.Equal(t,
+		[]*exportFields{{Id: 1, Name: "first"}, {Id: 2, Name: "second"}, {Id: 3, Name: "third"}},
+		decodeRecords(t, buf))
+}
+
+func TestDecodeRecords_slice(t *testing.T) {
+	// stream of previous versions has one slice of all records
+	want := []*exportFields{{Id: 1, Name: "first"}, {Id: 2, Name: "second"}}
+	buf := &bytes.Buffer{}
+	require.NoError(t, gob.NewEncoder(buf).Encode(want))
+
+	assert.Equal(t, want, decodeRecords(t, buf))
+}
+
+func TestTable_ExportRecords(t *testing.T) {
+	table := &Table{conn: NewConnWithOptions(), name: "test"}
+	_, err := table.ExportRecords(context.Background(), &bytes.Buffer{}, ExportGob, nil)
+	assert.ErrorIs(t, err, ErrExportRecord)
+}
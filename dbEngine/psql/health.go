@@ -0,0 +1,137 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package psql
+
+import (
+	"encoding/json"
+	"maps"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/logs"
+)
+
+// PoolStat consists of statistics of connections pool
+type PoolStat struct {
+	AcquiredConns   int32         `json:"acquired_conns"`
+	AcquireCount    int64         `json:"acquire_count"`
+	AcquireDuration time.Duration `json:"acquire_duration"`
+	IdleConns       int32         `json:"idle_conns"`
+	TotalConns      int32         `json:"total_conns"`
+	MaxConns        int32         `json:"max_conns"`
+}
+
+// HealthReport consists of state of connection to DB for readiness probes
+type HealthReport struct {
+	Healthy        bool            `json:"healthy"`
+	Pool           PoolStat        `json:"pool"`
+	ServerVersion  string          `json:"server_version,omitempty"`
+	IsReplica      bool            `json:"is_replica"`
+	ReplicationLag time.Duration   `json:"replication_lag,omitempty"`
+	Channels       map[string]bool `json:"channels,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time      `json:"last_error_at,omitempty"`
+}
+
+// PoolStat return statistics of connections pool
+func (c *Conn) PoolStat() PoolStat {
+	if c.Pool == nil {
+		return PoolStat{}
+	}
+
+	s := c.Pool.Stat()
+	return PoolStat{
+		AcquiredConns:   s.AcquiredConns(),
+		AcquireCount:    s.AcquireCount(),
+		AcquireDuration: s.AcquireDuration(),
+		IdleConns:       s.IdleConns(),
+		TotalConns:      s.TotalConns(),
+		MaxConns:        s.MaxConns(),
+	}
+}
+
+// Ping check connection to DB
+func (c *Conn) Ping(ctx context.Context) error {
+	if c.Pool == nil {
+		return ErrPoolNotInit
+	}
+
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.release(conn)
+
+	err = conn.Ping(ctx)
+	if err != nil {
+		c.setLastErr(err)
+	}
+
+	return err
+}
+
+// HealthReport collect state of pool, server & listening channels
+// it returns report with Healthy=false & error if DB is not available
+func (c *Conn) HealthReport(ctx context.Context) (*HealthReport, error) {
+	report := &HealthReport{
+		Pool: c.PoolStat(),
+	}
+
+	c.lock.RLock()
+	report.Channels = maps.Clone(c.listening)
+	if c.lastErr != nil {
+		report.LastError = c.lastErr.Error()
+		report.LastErrorAt = new(c.lastErrAt)
+	}
+	c.lock.RUnlock()
+
+	if err := c.Ping(ctx); err != nil {
+		report.LastError = err.Error()
+		return report, err
+	}
+
+	var lag *float64
+	err := c.SelectOneAndScan(ctx,
+		[]any{&report.ServerVersion, &report.IsReplica, &lag},
+		sqlHealthReport)
+	if err != nil {
+		report.LastError = err.Error()
+		return report, err
+	}
+
+	if lag != nil {
+		report.ReplicationLag = time.Duration(*lag * float64(time.Second))
+	}
+	report.Healthy = true
+
+	return report, nil
+}
+
+// HealthHandler is fasthttp handler for readiness probe,
+// it writes HealthReport as JSON with status 503 if DB is not available
+func (c *Conn) HealthHandler(ctx *fasthttp.RequestCtx) {
+	report, err := c.HealthReport(ctx)
+	if err != nil {
+		logs.ErrorLog(err, "HealthReport")
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	}
+
+	ctx.SetContentType("application/json")
+	if err := json.NewEncoder(ctx).Encode(report); err != nil {
+		logs.ErrorLog(err, "encode HealthReport")
+	}
+}
+
+// PingHandler is fasthttp handler for liveness probe
+func (c *Conn) PingHandler(ctx *fasthttp.RequestCtx) {
+	if err := c.Ping(ctx); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusServiceUnavailable)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+}
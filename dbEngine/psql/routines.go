@@ -72,6 +72,66 @@ func (r *Routine) Overlay() dbEngine.Routine {
 	return r.overlay
 }
 
+// IsSignature implements dbEngine.RoutineDefinition, DB resolves types of signature by to_regprocedure
+func (r *Routine) IsSignature(ctx context.Context, signature string) (bool, error) {
+	is := false
+	err := r.conn.SelectOneAndScan(ctx, &is, sqlIsRoutineSignature, signature, int64(r.ID))
+	if err != nil {
+		return false, errors.Wrap(err, signature)
+	}
+
+	return is, nil
+}
+
+// DefinitionChanged implements dbEngine.RoutineDefinition,
+// it compares pg_get_functiondef before & after performing ddl in transaction which is rolled back
+func (r *Routine) DefinitionChanged(ctx context.Context, ddl string) (bool, error) {
+	conn, err := r.conn.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.conn.release(conn)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, r.name)
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var before, after string
+	err = tx.QueryRow(ctx, sqlGetRoutineDefinition, int64(r.ID)).Scan(&before)
+	if err != nil {
+		return false, errors.Wrap(err, r.name)
+	}
+
+	_, err = tx.Exec(ctx, ddl)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.QueryRow(ctx, sqlGetRoutineDefinition, int64(r.ID)).Scan(&after)
+	if err != nil {
+		return false, errors.Wrap(err, r.name)
+	}
+
+	return before != after, nil
+}
+
+// DependentDDL implements dbEngine.RoutineDefinition, it returns grants & comment of routine
+func (r *Routine) DependentDDL(ctx context.Context) ([]string, error) {
+	ddl := make([]string, 0)
+	err := r.conn.SelectOneAndScan(ctx, &ddl, sqlGetRoutineDependents, int64(r.ID))
+	if err != nil {
+		return nil, errors.Wrap(err, r.name)
+	}
+
+	return ddl, nil
+}
+
 // Columns of Routine
 func (r *Routine) Columns() []dbEngine.Column {
 	res := make([]dbEngine.Column, len(r.columns))
@@ -115,10 +175,13 @@ func (r *Routine) Call(ctx context.Context, args ...any) error {
 
 	logs.SetDebug(true)
 	logs.DebugLog(sql)
-	comTag, err := r.conn.Exec(ctx, sql, args...)
+	ctx, cancel, timeout := r.conn.withTimeout(ctx, 0)
+	defer cancel()
+
+	comTag, err := r.conn.exec(ctx, sql, args...)
 	if err != nil {
 		logs.ErrorLog(err, "'%s' %s", comTag, strings.Split(sql, "\n")[0])
-		return err
+		return r.conn.chkTimeout(ctx, err, r.name, timeout)
 	}
 	if mes := comTag.String(); mes != "CALL" {
 		return errors.New(mes)
@@ -199,16 +262,29 @@ func (r *Routine) GetParams(ctx context.Context, types map[string]dbEngine.Types
 // SelectAndScanEach run sql of table with Options & return every row into rowValues & run each
 func (r *Routine) SelectAndScanEach(ctx context.Context, each func() error, row dbEngine.RowScanner, Options ...dbEngine.BuildSqlOptions) error {
 
-	sql, args, err := r.BuildSql(Options...)
+	sql, b, err := r.buildSql(Options...)
 	if err != nil {
 		return err
 	}
 
-	return r.conn.SelectAndScanEach(ctx, each, row, sql, args...)
+	ctx, cancel, timeout := r.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	return r.conn.chkTimeout(ctx, r.conn.SelectAndScanEach(ctx, each, row, sql, b.Args...), r.name, timeout)
 }
 
 // BuildSql create sql query & arg for call conn.Select...
 func (r *Routine) BuildSql(Options ...dbEngine.BuildSqlOptions) (string, []any, error) {
+	sql, b, err := r.buildSql(Options...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sql, b.Args, nil
+}
+
+// buildSql create sql query & return SQLBuilder with its args & options
+func (r *Routine) buildSql(Options ...dbEngine.BuildSqlOptions) (string, *dbEngine.SQLBuilder, error) {
 	b, err := dbEngine.NewSQLBuilder(r.newTableForSQLBuilder(), Options...)
 	if err != nil {
 		return "", nil, errors.Wrap(err, "setOption")
@@ -223,7 +299,7 @@ func (r *Routine) BuildSql(Options ...dbEngine.BuildSqlOptions) (string, []any,
 
 	switch r.Type {
 	case ROUTINE_TYPE_PROC:
-		return "CALL " + (b.Table).(*Table).name, b.Args, nil
+		return "CALL " + (b.Table).(*Table).name, b, nil
 
 	case ROUTINE_TYPE_FUNC:
 		sql, err := b.SelectSql()
@@ -231,7 +307,7 @@ func (r *Routine) BuildSql(Options ...dbEngine.BuildSqlOptions) (string, []any,
 			return "", nil, err
 		}
 
-		return sql, b.Args, nil
+		return sql, b, nil
 	default:
 		return "", nil, dbEngine.ErrWrongType{
 			Name:     r.name,
@@ -271,26 +347,34 @@ func (r *Routine) checkArgs(tableName string, args []any) error {
 
 // SelectAndRunEach run sql of table with Options & performs each every row of query results
 func (r *Routine) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow, Options ...dbEngine.BuildSqlOptions) error {
-	sql, args, err := r.BuildSql(Options...)
+	sql, b, err := r.buildSql(Options...)
 	if err != nil {
 		return err
 	}
 
-	return r.conn.selectAndRunEach(
+	ctx, cancel, timeout := r.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	err = r.conn.selectAndRunEach(
 		ctx,
 		each,
 		sql,
-		args...)
+		b.Args...)
+
+	return r.conn.chkTimeout(ctx, err, r.name, timeout)
 }
 
 // SelectOneAndScan run sqlof table  with Options & return rows into rowValues
 func (r *Routine) SelectOneAndScan(ctx context.Context, row any, Options ...dbEngine.BuildSqlOptions) error {
-	sql, args, err := r.BuildSql(Options...)
+	sql, b, err := r.buildSql(Options...)
 	if err != nil {
 		return err
 	}
 
-	return r.conn.SelectOneAndScan(ctx, row, sql, args...)
+	ctx, cancel, timeout := r.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	return r.conn.chkTimeout(ctx, r.conn.SelectOneAndScan(ctx, row, sql, b.Args...), r.name, timeout)
 }
 
 func (r *Routine) newTableForSQLBuilder() *Table {
@@ -0,0 +1,131 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package psql
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/logs"
+)
+
+// FncFlush is type of function which writes pending records of copy pool into DB
+type FncFlush func(ctx context.Context) error
+
+// ErrNotFlushed consists of errors of copy pools, which could not be flushed during Close
+type ErrNotFlushed struct {
+	Pools map[string]error
+}
+
+// Error implement error interface
+func (err ErrNotFlushed) Error() string {
+	names := slices.Sorted(maps.Keys(err.Pools))
+	msg := make([]string, len(names))
+	for i, name := range names {
+		msg[i] = fmt.Sprintf("%s: %v", name, err.Pools[name])
+	}
+
+	return "copy pools not flushed - " + strings.Join(msg, "; ")
+}
+
+// waitInFlightPeriod is period of checking in-flight queries during Close
+const waitInFlightPeriod = 10 * time.Millisecond
+
+// RegisterCopyPool add flush function of copy pool 'name', it will be called during Close,
+// flush must stop & wait for goroutines of pool which write its records before writing them itself
+func (c *Conn) RegisterCopyPool(name string, flush FncFlush) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.copyPools == nil {
+		c.copyPools = make(map[string]FncFlush)
+	}
+	c.copyPools[name] = flush
+}
+
+// UnregisterCopyPool remove flush function of copy pool 'name'
+func (c *Conn) UnregisterCopyPool(name string) {
+	c.lock.Lock()
+	delete(c.copyPools, name)
+	c.lock.Unlock()
+}
+
+// Done returns a channel that's closed when Conn begins closing or its context is cancelled
+func (c *Conn) Done() <-chan struct{} {
+	if c.ctxPool == nil {
+		return nil
+	}
+
+	return c.ctxPool.Done()
+}
+
+// Close stops listeners, waits for in-flight queries & flushes registered copy pools,
+// after that closes pool of connections.
+// It returns ErrNotFlushed if some copy pools could not write their records
+func (c *Conn) Close(ctx context.Context) error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	if c.Cancel != nil {
+		c.Cancel()
+	}
+	c.listeners.Wait()
+
+	err := c.waitInFlight(ctx)
+	if err != nil {
+		logs.ErrorLog(err, "waiting for in-flight queries")
+	}
+
+	c.lock.RLock()
+	pools := maps.Clone(c.copyPools)
+	c.lock.RUnlock()
+
+	notFlushed := ErrNotFlushed{Pools: make(map[string]error)}
+	for name, flush := range pools {
+		if err := flush(ctx); err != nil {
+			notFlushed.Pools[name] = err
+		}
+	}
+
+	if c.Pool != nil {
+		c.Pool.Close()
+	}
+
+	if len(notFlushed.Pools) > 0 {
+		return notFlushed
+	}
+
+	return err
+}
+
+func (c *Conn) waitInFlight(ctx context.Context) error {
+	ticker := time.NewTicker(waitInFlightPeriod)
+	defer ticker.Stop()
+
+	for c.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// copyFrom run CopyFrom on pool as in-flight query
+func (c *Conn) copyFrom(ctx context.Context, tableName pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
+	return c.Pool.CopyFrom(ctx, tableName, columns, src)
+}
@@ -7,6 +7,7 @@ package psql
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
@@ -19,15 +20,18 @@ import (
 
 // Table implement dbEngine interface Table for PostgreSQL
 type Table struct {
-	conn       *Conn
-	name, Type string
-	ID         int
-	comment    string
-	columns    []*Column
-	indexes    dbEngine.Indexes
-	PK         string
-	buf        *Column
-	lock       sync.RWMutex
+	conn        *Conn
+	name, Type  string
+	ID          int
+	comment     string
+	columns     []*Column
+	indexes     dbEngine.Indexes
+	constraints dbEngine.Constraints
+	foreignKeys dbEngine.ForeignKeys
+	triggers    dbEngine.Triggers
+	PK          string
+	buf         *Column
+	lock        sync.RWMutex
 }
 
 // DoCopy run CopyFrom PSQL use src interface
@@ -39,7 +43,21 @@ func (t *Table) DoCopy(ctx context.Context, src pgx.CopyFromSource, columns ...s
 		}
 	}
 
-	return t.conn.Pool.CopyFrom(ctx, pgx.Identifier{t.name}, columns, src)
+	return t.conn.copyFrom(ctx, pgx.Identifier{t.name}, columns, src)
+}
+
+// RegisterCopyPool add flush of copy pool of table, Conn will call it during Close
+func (t *Table) RegisterCopyPool(flush FncFlush) {
+	t.conn.RegisterCopyPool(t.name, flush)
+}
+
+// Done returns a channel that's closed when connection of table begins closing
+func (t *Table) Done() <-chan struct{} {
+	if t.conn == nil {
+		return nil
+	}
+
+	return t.conn.Done()
 }
 
 // Comment of Table
@@ -97,9 +115,12 @@ func (t *Table) Delete(ctx context.Context, Options ...dbEngine.BuildSqlOptions)
 		return 0, err
 	}
 
-	comTag, err := t.conn.Exec(ctx, sql, b.Args...)
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	comTag, err := t.conn.exec(ctx, sql, b.Args...)
 	if err != nil {
-		return -1, errors.Wrap(err, sql)
+		return -1, errors.Wrap(t.conn.chkTimeout(ctx, err, t.name, timeout), sql)
 	}
 
 	return comTag.RowsAffected(), nil
@@ -112,12 +133,20 @@ func (t *Table) Insert(ctx context.Context, Options ...dbEngine.BuildSqlOptions)
 		return 0, errors.Wrap(err, "setOption")
 	}
 
+	b.SkipGeneratedColumns()
+	if err := b.ValidateArgs(true); err != nil {
+		return 0, err
+	}
+
 	sql, err := b.InsertSql()
 	if err != nil {
 		return 0, err
 	}
 
-	return t.doInsertReturning(ctx, sql, b.Args...)
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	return t.doInsertReturning(ctx, timeout, sql, b.Args...)
 }
 
 // Update table according to Options
@@ -127,14 +156,22 @@ func (t *Table) Update(ctx context.Context, Options ...dbEngine.BuildSqlOptions)
 		return 0, errors.Wrap(err, "setOption")
 	}
 
+	b.SkipGeneratedColumns()
+	if err := b.ValidateArgs(false); err != nil {
+		return 0, err
+	}
+
 	sql, err := b.UpdateSql()
 	if err != nil {
 		return 0, err
 	}
 
-	comTag, err := t.conn.Exec(ctx, sql, b.Args...)
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	comTag, err := t.conn.exec(ctx, sql, b.Args...)
 	if err != nil {
-		return -1, errors.Wrap(err, sql)
+		return -1, errors.Wrap(t.conn.chkTimeout(ctx, err, t.name, timeout), sql)
 	}
 
 	return comTag.RowsAffected(), nil
@@ -147,30 +184,37 @@ func (t *Table) Upsert(ctx context.Context, Options ...dbEngine.BuildSqlOptions)
 		return 0, errors.Wrap(err, "setOption")
 	}
 
+	b.SkipGeneratedColumns()
+	if err := b.ValidateArgs(true); err != nil {
+		return 0, err
+	}
+
 	sql, err := b.UpsertSql()
 	if err != nil {
 		return 0, err
 	}
 
-	return t.doInsertReturning(ctx, sql, b.Args...)
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	return t.doInsertReturning(ctx, timeout, sql, b.Args...)
 }
 
-func (t *Table) doInsertReturning(ctx context.Context, sql string, args ...any) (int64, error) {
-	for _, col := range t.columns {
-		if col.Primary() && col.autoInc {
-			sql += " RETURNING " + col.Name()
-			id := int64(-1)
-			err := t.conn.SelectOneAndScan(ctx, &id, sql, args...)
-			if err == pgx.ErrNoRows {
-				err = nil
-			}
-			return id, errors.Wrap(err, sql)
+func (t *Table) doInsertReturning(ctx context.Context, timeout time.Duration, sql string, args ...any) (int64, error) {
+	// only single column key may be returned as ID, otherwise rowsAffected
+	if pk := t.PrimaryKey(); len(pk) == 1 && pk[0].AutoIncrement() {
+		sql += " RETURNING " + pk[0].Name()
+		id := int64(-1)
+		err := t.conn.SelectOneAndScan(ctx, &id, sql, args...)
+		if err == pgx.ErrNoRows {
+			err = nil
 		}
+		return id, errors.Wrap(t.conn.chkTimeout(ctx, err, t.name, timeout), sql)
 	}
 
-	comTag, err := t.conn.Exec(ctx, sql, args...)
+	comTag, err := t.conn.exec(ctx, sql, args...)
 	if err != nil {
-		return -1, errors.Wrap(err, sql)
+		return -1, errors.Wrap(t.conn.chkTimeout(ctx, err, t.name, timeout), sql)
 	}
 
 	return comTag.RowsAffected(), nil
@@ -193,9 +237,12 @@ func (t *Table) Select(ctx context.Context, Options ...dbEngine.BuildSqlOptions)
 		return err
 	}
 
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
 	_, err = t.conn.Query(ctx, sql, b.Args...)
 
-	return err
+	return t.conn.chkTimeout(ctx, err, t.name, timeout)
 }
 
 // SelectOneAndScan run sql of table  with Options & return rows into rowValues
@@ -210,7 +257,10 @@ func (t *Table) SelectOneAndScan(ctx context.Context, row any, Options ...dbEngi
 		return err
 	}
 
-	return t.conn.SelectOneAndScan(ctx, row, sql, b.Args...)
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	return t.conn.chkTimeout(ctx, t.conn.SelectOneAndScan(ctx, row, sql, b.Args...), t.name, timeout)
 }
 
 // SelectAndScanEach run sql of table with Options & return every row into rowValues & run each
@@ -225,8 +275,11 @@ func (t *Table) SelectAndScanEach(ctx context.Context, each func() error, row db
 		return err
 	}
 
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
 	logs.DebugLog(sql)
-	return t.conn.SelectAndScanEach(ctx, each, row, sql, b.Args...)
+	return t.conn.chkTimeout(ctx, t.conn.SelectAndScanEach(ctx, each, row, sql, b.Args...), t.name, timeout)
 }
 
 // SelectAndRunEach run sql of table with Options & performs each every row of query results
@@ -241,7 +294,10 @@ func (t *Table) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow,
 		return err
 	}
 
-	return t.conn.selectAndRunEach(
+	ctx, cancel, timeout := t.conn.withTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	err = t.conn.selectAndRunEach(
 		ctx,
 		func(values []any, columns []dbEngine.Column) error {
 			if each != nil {
@@ -252,6 +308,8 @@ func (t *Table) SelectAndRunEach(ctx context.Context, each dbEngine.FncEachRow,
 		},
 		sql,
 		b.Args...)
+
+	return t.conn.chkTimeout(ctx, err, t.name, timeout)
 }
 
 // FindColumn return column 'name' on Table or nil
@@ -317,6 +375,96 @@ func (t *Table) GetIndexes(ctx context.Context) error {
 			&t.indexes, sqlGetIndexes, t.Name()), t.Name())
 }
 
+// GetConstraints collect primary key, unique, check & exclusion constraints of table
+func (t *Table) GetConstraints(ctx context.Context) error {
+	t.constraints = make(dbEngine.Constraints, 0)
+
+	return errors.Wrap(
+		t.conn.SelectAndScanEach(ctx, nil, &t.constraints, sqlGetConstraints, t.Name()),
+		t.Name())
+}
+
+// PrimaryKey return columns of primary key of table (composite key consists of several columns)
+func (t *Table) PrimaryKey() []dbEngine.Column {
+	pk := t.constraints.Filter(dbEngine.ConstraintPrimary)
+	if len(pk) == 0 {
+		// constraints weren't read
+		return dbEngine.PrimaryColumns(t)
+	}
+
+	columns := make([]dbEngine.Column, 0, len(pk[0].Columns))
+	for _, name := range pk[0].Columns {
+		if col := t.FindColumn(name); col != nil {
+			columns = append(columns, col)
+		}
+	}
+
+	return columns
+}
+
+// UniqueConstraints return unique constraints of table
+func (t *Table) UniqueConstraints() dbEngine.Constraints {
+	if t.constraints == nil {
+		return dbEngine.UniqueIndexes(t)
+	}
+
+	return t.constraints.Filter(dbEngine.ConstraintUnique)
+}
+
+// Constraints return primary key, unique, check & exclusion constraints of table
+func (t *Table) Constraints() dbEngine.Constraints {
+	if t.constraints == nil {
+		return dbEngine.KeyConstraints(t)
+	}
+
+	return t.constraints
+}
+
+// GetForeignKeys collect foreign keys of table & set them to its columns
+func (t *Table) GetForeignKeys(ctx context.Context) error {
+	t.foreignKeys = make(dbEngine.ForeignKeys, 0)
+	err := t.conn.SelectAndScanEach(ctx, nil, &t.foreignKeys, sqlGetForeignKeys, t.Name())
+	if err != nil {
+		return errors.Wrap(err, t.Name())
+	}
+
+	for _, fk := range t.foreignKeys {
+		for _, name := range fk.Columns {
+			if col := t.findColumn(name); col != nil {
+				if col.Constraints == nil {
+					col.Constraints = make(map[string]*dbEngine.ForeignKey)
+				}
+				col.Constraints[fk.Name] = fk.ForColumn(name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ForeignKeys return foreign keys of table
+func (t *Table) ForeignKeys() dbEngine.ForeignKeys {
+	if t.foreignKeys == nil {
+		return dbEngine.ColumnsForeignKeys(t)
+	}
+
+	return t.foreignKeys
+}
+
+// GetTriggers collect triggers of table except internal ones (of foreign keys)
+func (t *Table) GetTriggers(ctx context.Context) error {
+	t.triggers = make(dbEngine.Triggers, 0)
+
+	return errors.Wrap(
+		t.conn.SelectAndScanEach(ctx, nil, &t.triggers, sqlGetTriggers, t.Name()),
+		t.Name())
+}
+
+// Triggers return triggers of table
+func (t *Table) Triggers() dbEngine.Triggers {
+	return t.triggers
+}
+
 // FindIndex get index according to name
 func (t *Table) FindIndex(name string) *dbEngine.Index {
 	for _, ind := range t.indexes {
@@ -333,6 +481,93 @@ func (t *Table) Indexes() dbEngine.Indexes {
 	return t.indexes
 }
 
+// DefinitionChanged implements dbEngine.MaterializedView,
+// DB normalizes query by temporary view (it is rolled back) & compares it with pg_matviews.definition
+func (t *Table) DefinitionChanged(ctx context.Context, columns []string, query string) (bool, error) {
+	conn, err := t.conn.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer t.conn.release(conn)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, t.Name())
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	ddl := "CREATE TEMP VIEW " + tmpMatViewDefinition
+	if len(columns) > 0 {
+		ddl += " (" + strings.Join(columns, ", ") + ")"
+	}
+
+	_, err = tx.Exec(ctx, ddl+" AS "+query)
+	if err != nil {
+		return false, errors.Wrap(err, t.Name())
+	}
+
+	equal := false
+	err = tx.QueryRow(ctx, sqlCmpMatViewDefinition, t.Name()).Scan(&equal)
+	if err != nil {
+		return false, errors.Wrap(err, t.Name())
+	}
+
+	return !equal, nil
+}
+
+// CheckDefinition implements dbEngine.CheckDefinition,
+// it adds check as 'not valid' constraint in transaction which is rolled back, so rows of table aren't checked
+func (t *Table) CheckDefinition(ctx context.Context, definition string) (string, error) {
+	conn, err := t.conn.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer t.conn.release(conn)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, t.Name())
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, "ALTER TABLE "+t.Name()+" ADD CONSTRAINT "+tmpCheckDefinition+" "+definition+" NOT VALID")
+	if err != nil {
+		return "", errors.Wrap(err, t.Name())
+	}
+
+	def := ""
+	err = tx.QueryRow(ctx, sqlGetCheckDefinition, t.Name()).Scan(&def)
+	if err != nil {
+		return "", errors.Wrap(err, t.Name())
+	}
+
+	return strings.TrimSuffix(def, " NOT VALID"), nil
+}
+
+// DependentDDL implements dbEngine.MaterializedView, it returns definitions of indexes & grants of view
+func (t *Table) DependentDDL(ctx context.Context) ([]string, error) {
+	ddl := make([]string, 0)
+	err := t.conn.SelectOneAndScan(ctx, &ddl, sqlGetMatViewDependents, t.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, t.Name())
+	}
+
+	return ddl, nil
+}
+
+// Validate check values of columns according to their metadata before writing
+func (t *Table) Validate(columns []string, values []any) error {
+	return dbEngine.ValidateValues(t, columns, values)
+}
+
 // ReReadColumn renew properties of column 'name'
 func (t *Table) ReReadColumn(ctx context.Context, name string) dbEngine.Column {
 	t.lock.RLock()
@@ -0,0 +1,47 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package psql
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/ruslanBik4/dbEngine/dbEngine"
+)
+
+// queryDeadline is key of ctx value with deadline which withTimeout sets for query
+type queryDeadline struct{}
+
+// withTimeout return ctx with deadline of query - timeout of SQLBuilder or StatementTimeout of Conn
+func (c *Conn) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, time.Duration) {
+	if timeout == 0 {
+		timeout = c.statementTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}, 0
+	}
+
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+
+	return context.WithValue(ctx, queryDeadline{}, deadline), cancel, timeout
+}
+
+// chkTimeout return ErrQueryTimeout if query of table/routine 'name' was cancelled by deadline of withTimeout,
+// errors of caller's ctx and cancelling on server (statement_timeout, pg_cancel_backend) are returned as is
+func (c *Conn) chkTimeout(ctx context.Context, err error, name string, timeout time.Duration) error {
+	if err == nil || timeout <= 0 || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+
+	// ctx has deadline of caller if it's earlier than deadline of query
+	own, ok := ctx.Value(queryDeadline{}).(time.Time)
+	if deadline, _ := ctx.Deadline(); !ok || !deadline.Equal(own) {
+		return err
+	}
+
+	return dbEngine.NewErrQueryTimeout(name, timeout, err)
+}
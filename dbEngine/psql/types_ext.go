@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
 
 	"github.com/ruslanBik4/dbEngine/dbEngine"
 	"github.com/ruslanBik4/logs"
@@ -152,3 +153,41 @@ func ChkDataType(ctx context.Context, db *dbEngine.DB, typeCol string) (*pgtype.
 	defer conn.Release()
 	return conn.Conn().ConnInfo().DataTypeForName(typeCol)
 }
+
+// RegisterDataTypes adds decoders of dbEngine.RegisteredTypes into ConnInfo of conn
+// for types, which are unknown for pgx (types of extensions as hstore, ltree, etc.)
+func RegisterDataTypes(ctx context.Context, conn *pgx.Conn) error {
+	ci := conn.ConnInfo()
+	decoders := make(map[string]dbEngine.RegisteredType)
+	names := make([]string, 0)
+	for _, t := range dbEngine.RegisteredTypes() {
+		if _, ok := ci.DataTypeForName(t.UdtName); !ok && t.Decoder != nil {
+			decoders[t.UdtName] = t
+			names = append(names, t.UdtName)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	rows, err := conn.Query(ctx, sqlGetTypesOID, names)
+	if err != nil {
+		return errors.Wrap(err, "RegisterDataTypes")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			oid  uint32
+			name string
+		)
+		if err := rows.Scan(&oid, &name); err != nil {
+			return errors.Wrap(err, "RegisterDataTypes")
+		}
+
+		ci.RegisterDataType(pgtype.DataType{Value: decoders[name].NewValue(), Name: name, OID: oid})
+	}
+
+	return rows.Err()
+}
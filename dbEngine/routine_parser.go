@@ -0,0 +1,124 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"strings"
+)
+
+// syncRoutinesDDL performs statements of routines file, errors of statements are logged
+func (db *DB) syncRoutinesDDL(fileName, ddl string) {
+	logErr := func(err error, ddl string) {
+		if e, ok := err.(*ErrDDLSyntax); ok {
+			e.File = fileName
+		}
+		logError(err, ddl, fileName)
+	}
+
+	statements, err := SplitStatementsDDL(ddl)
+	if err != nil {
+		logErr(err, ddl)
+	}
+
+	for _, source := range statements {
+		stmt, err := ParseStatementDDL(source)
+		if err == nil {
+			if s, ok := stmt.(*CreateRoutineStmt); ok {
+				err = db.syncRoutine(s)
+			} else if err = db.Conn.ExecDDL(db.ctx, source.Text); IsErrorAlreadyExists(err) {
+				err = nil
+			}
+		}
+
+		if err != nil {
+			logErr(err, source.Text)
+		}
+	}
+}
+
+// syncRoutine creates new routine or replaces routine with same signature if its definition is changed,
+// routine which DB can't replace is recreated with its grants & comment
+func (db *DB) syncRoutine(s *CreateRoutineStmt) error {
+	routine, err := db.findRoutine(s)
+	if err != nil {
+		return err
+	}
+
+	if routine == nil {
+		err = db.Conn.ExecDDL(db.ctx, s.Text)
+		switch {
+		case err == nil:
+			db.FuncsAdded = append(db.FuncsAdded, s.Name)
+			return nil
+		case IsErrorAlreadyExists(err):
+			return nil
+		default:
+			// routine isn't found, so it isn't dropped without its grants & comment
+			return err
+		}
+	}
+
+	changed, err := routine.DefinitionChanged(db.ctx, s.Text)
+	switch {
+	case IsErrorForReplace(err):
+		dependents, err := routine.DependentDDL(db.ctx)
+		if err != nil {
+			return err
+		}
+
+		return db.recreateRoutine(s, dependents)
+
+	case IsErrorAlreadyExists(err):
+		return nil
+	case err != nil:
+		return err
+	case !changed:
+		return nil
+	}
+
+	if err = db.Conn.ExecDDL(db.ctx, s.Text); err != nil {
+		return err
+	}
+	db.FuncsReplaced = append(db.FuncsReplaced, s.Name)
+
+	return nil
+}
+
+// findRoutine return routine of DB which has signature of s,
+// connection without RoutineFinder is checked by routines of schema & their Overlay
+func (db *DB) findRoutine(s *CreateRoutineStmt) (RoutineDefinition, error) {
+	if finder, ok := db.Conn.(RoutineFinder); ok {
+		return finder.FindRoutine(db.ctx, s.Signature())
+	}
+
+	for routine := db.Routines[s.Name]; routine != nil; routine = routine.Overlay() {
+		r, ok := routine.(RoutineDefinition)
+		if !ok {
+			continue
+		}
+
+		is, err := r.IsSignature(db.ctx, s.Signature())
+		if err != nil {
+			return nil, err
+		}
+		if is {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recreateRoutine drops routine & creates it again, statements run in one transaction,
+// so DB keeps old routine if other objects depend on it
+func (db *DB) recreateRoutine(s *CreateRoutineStmt, dependents []string) error {
+	ddl := append([]string{"DROP " + s.Object + " " + s.Signature(), s.Text}, dependents...)
+	if err := db.Conn.ExecDDL(db.ctx, strings.Join(ddl, ";\n")); err != nil {
+		return err
+	}
+	db.FuncsReplaced = append(db.FuncsReplaced, s.Name)
+
+	return nil
+}
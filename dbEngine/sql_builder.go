@@ -6,13 +6,11 @@ package dbEngine
 
 import (
 	"fmt"
-	"go/types"
 	"regexp"
 	"slices"
 	"strings"
 	"time"
 
-	"github.com/jackc/pgtype"
 	"github.com/pkg/errors"
 
 	"github.com/ruslanBik4/logs"
@@ -29,6 +27,9 @@ type SQLBuilder struct {
 	onConflict    string
 	OrderBy       []string
 	Offset, Limit int
+	Timeout       time.Duration
+	validate      bool
+	fullText      *fullTextSearch
 }
 
 // NewSQLBuilder create SQLBuilder for table
@@ -41,6 +42,12 @@ func NewSQLBuilder(t Table, Options ...BuildSqlOptions) (*SQLBuilder, error) {
 		}
 	}
 
+	if b.fullText != nil {
+		// condition of full text search follows all other conditions
+		b.filter = append(b.filter, b.fullText.condition())
+		b.Args = append(b.Args, b.fullText.query)
+	}
+
 	return b, nil
 }
 
@@ -85,15 +92,12 @@ func (b SQLBuilder) UpsertSql() (string, error) {
 	}
 
 	if len(b.filter) == 0 {
-		b.filter = make([]string, 0)
-
-		for _, name := range b.columns {
-			if col := b.Table.FindColumn(name); col == (Column)(nil) {
-				return "", NewErrNotFoundColumn(b.Table.Name(), name)
-			} else if col.Primary() {
-				b.filter = append(b.filter, name)
-			}
+		keys, err := b.conflictKeys()
+		if err != nil {
+			return "", err
 		}
+
+		b.filter = keys
 		if len(b.filter) == 0 {
 			for _, ind := range b.Table.Indexes() {
 				// we get first unique index for onConflict
@@ -129,6 +133,37 @@ func (b SQLBuilder) UpsertSql() (string, error) {
 	return s + u, nil
 }
 
+// conflictKeys return columns of primary key or of unique constraint of table, which are all written by query
+func (b *SQLBuilder) conflictKeys() ([]string, error) {
+	for _, name := range b.columns {
+		if b.Table.FindColumn(name) == nil {
+			return nil, NewErrNotFoundColumn(b.Table.Name(), name)
+		}
+	}
+
+	isWritten := func(keys []string) bool {
+		return len(keys) > 0 && !slices.ContainsFunc(keys, func(name string) bool {
+			return !slices.Contains(b.columns, name)
+		})
+	}
+
+	pk := make([]string, 0)
+	for _, col := range b.Table.PrimaryKey() {
+		pk = append(pk, col.Name())
+	}
+	if isWritten(pk) {
+		return pk, nil
+	}
+
+	for _, constraint := range b.Table.UniqueConstraints() {
+		if isWritten(constraint.Columns) {
+			return constraint.Columns, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // DeleteSql construct delete sql
 func (b SQLBuilder) DeleteSql() (string, error) {
 	// todo check routine
@@ -158,11 +193,26 @@ func (b *SQLBuilder) SelectSql() (string, error) {
 		}
 	}
 
-	sql := "SELECT " + b.Select() + " FROM " + b.Table.Name() + b.Where()
+	// WHERE clause sets number of parameter of full text query
+	where := b.Where()
+	sql := "SELECT " + b.Select()
+	if b.fullText != nil && b.fullText.headline > "" {
+		sql += "," + b.fullText.headlineExpr()
+	}
+	sql += " FROM " + b.Table.Name() + where
+
+	if b.fullText != nil && b.fullText.rank {
+		sql += " order by " + b.fullText.rankExpr()
+		if len(b.OrderBy) > 0 {
+			sql += ","
+		}
+	} else if len(b.OrderBy) > 0 {
+		sql += " order by "
+	}
 
 	if len(b.OrderBy) > 0 {
 		// todo add column checking
-		sql += " order by " + strings.Join(slices.Collect(func(yield func(string) bool) {
+		sql += strings.Join(slices.Collect(func(yield func(string) bool) {
 			for _, order := range b.OrderBy {
 				name, hasDesc := strings.CutSuffix(order, " desc")
 				if b.Table.FindColumn(name) == nil {
@@ -206,11 +256,16 @@ func (b *SQLBuilder) SelectColumns() []Column {
 			selectColumns[i] = col
 		}
 
-		return selectColumns
+		return b.appendHeadline(selectColumns)
 	}
 
 	selectColumns := make([]Column, len(b.columns))
 	for i, name := range b.columns {
+		if col, ok := b.jsonProjection(name); ok {
+			selectColumns[i] = col
+			continue
+		}
+
 		col, ok := CheckColumn(name, b.Table)
 		if ok {
 			selectColumns[i] = col
@@ -219,7 +274,7 @@ func (b *SQLBuilder) SelectColumns() []Column {
 		}
 	}
 
-	return selectColumns
+	return b.appendHeadline(selectColumns)
 }
 
 // CheckColumn check ddl for consists any columns of table
@@ -271,7 +326,7 @@ func shrinkColName(name string) string {
 func (b *SQLBuilder) Select() string {
 	if len(b.columns) == 0 {
 		if b.Table != nil && len(b.Table.Columns()) > 0 {
-			b.fillColumnsFromTable()
+			b.fillColumnsFromTable(false)
 		} else {
 			// todo - chk for insert request
 			return "*"
@@ -298,14 +353,20 @@ func (b *SQLBuilder) convertColumnName(name string) string {
 func (b *SQLBuilder) isComplexWhereTerm(name string) bool {
 	return strings.Contains(name, " as ") ||
 		strings.Contains(name, " is ") ||
+		regJSONTerm.MatchString(name) ||
+		regAnyAllTerm.MatchString(name) ||
 		slices.ContainsFunc(operatorSymbols, func(r rune) bool {
 			return strings.IndexRune(name, r) > 0
 		})
 }
 
-func (b *SQLBuilder) fillColumnsFromTable() {
+// fillColumnsFromTable sets columns of table, skipGenerated means columns for writing (see IsGeneratedColumn)
+func (b *SQLBuilder) fillColumnsFromTable(skipGenerated bool) {
 	b.columns = slices.Collect(func(yield func(string) bool) {
 		for _, col := range b.Table.Columns() {
+			if skipGenerated && IsGeneratedColumn(col) {
+				continue
+			}
 			if !yield(col.Name()) {
 				return
 			}
@@ -313,6 +374,31 @@ func (b *SQLBuilder) fillColumnsFromTable() {
 	})
 }
 
+// SkipGeneratedColumns removes generated columns (see IsGeneratedColumn) & their values
+// from written columns of INSERT & UPDATE queries
+func (b *SQLBuilder) SkipGeneratedColumns() {
+	if b.Table == nil || len(b.Args) < len(b.columns) {
+		return
+	}
+
+	columns, args := make([]string, 0, len(b.columns)), make([]any, 0, len(b.Args))
+	for i, name := range b.columns {
+		if IsGeneratedColumn(b.Table.FindColumn(name)) {
+			logs.DebugLog("column %s is generated, its value is skipped", name)
+			continue
+		}
+
+		columns = append(columns, name)
+		args = append(args, b.Args[i])
+	}
+
+	if len(columns) < len(b.columns) {
+		// arguments of WHERE clause follow values of columns
+		b.Args = append(args, b.Args[len(b.columns):]...)
+		b.columns = columns
+	}
+}
+
 // Set return SET clause of SQL update query
 func (b *SQLBuilder) Set() (string, error) {
 	s, comma := " SET ", ""
@@ -322,6 +408,7 @@ func (b *SQLBuilder) Set() (string, error) {
 	}
 
 	for _, name := range b.columns {
+		b.convertArg(b.posFilter, name)
 		b.posFilter++
 		s += fmt.Sprintf(comma+" %s=$%d", name, b.posFilter)
 		comma = ","
@@ -335,7 +422,7 @@ func (b *SQLBuilder) SetUpsert() (string, error) {
 	s, comma := " SET", " "
 	if len(b.columns) == 0 {
 		if b.Table != nil && len(b.Table.Columns()) > 0 {
-			b.fillColumnsFromTable()
+			b.fillColumnsFromTable(true)
 		} else {
 			return "", errors.Wrap(NewErrWrongType("columns list", "table", "nil"),
 				"SetUpsert")
@@ -386,6 +473,9 @@ func (b *SQLBuilder) Where() string {
 			}
 
 			b.posFilter++
+			if b.isFullTextTerm(name) {
+				b.fullText.pos = b.posFilter
+			}
 
 			if !yield(b.writeCondition(name, hasTpl)) {
 				return
@@ -417,6 +507,11 @@ func (b *SQLBuilder) writeCondition(name string, hasTpl bool) string {
 		}
 
 		name = b.convertColumnName(name)
+		switch preStr {
+		case "@>", "<@", "&&":
+			return b.containCondition(name, preStr)
+		}
+
 		switch pre {
 		case '$':
 			return fmt.Sprintf("%s ~ concat('.*', $%d, '$')", name, b.posFilter)
@@ -438,49 +533,35 @@ func (b *SQLBuilder) writeCondition(name string, hasTpl bool) string {
 // chkSpecialParams get condition for WHERE include complex params as:
 // 'is null, 'is not null'
 // in (select ... from ... where field = {param})
+// arrays, ranges & lists of values according to type of column (see columnCondition)
 func (b *SQLBuilder) chkSpecialParams(name string, hasTpl bool) string {
+	if hasTpl && (b.isJSONTerm(name) || b.isFullTextTerm(name)) {
+		// arguments of JSON operators & full text query are passed as is
+		return fmt.Sprintf(name, fmt.Sprintf("$%d", b.posFilter))
+	}
+
+	if cond, ok := b.anyAllCondition(name); hasTpl && ok {
+		return cond
+	}
 
-	cond := "$%[1]d"
-	var isArray bool
 	var column Column
 	if table := b.Table; table != nil {
 		column = table.FindColumn(name)
-		col, ok := column.(interface{ IsArray() bool })
-		isArray = ok && col.IsArray()
 	}
 
 	if !hasTpl {
 		name = b.convertColumnName(name)
 	}
+
+	cond := "$%[1]d"
 	switch arg := b.Args[b.posFilter-1].(type) {
 	case nil:
 		cond = "is null"
 
-	case []int, []int8, []int16, []int32, []int64, []float32, []float64, []string, types.Slice, []time.Time, []*time.Time,
-		pgtype.ArrayType, pgtype.Int2Array, pgtype.Int4Array, pgtype.Int8Array, pgtype.DateArray,
-		pgtype.TimestampArray, pgtype.TimestamptzArray,
-		pgtype.Float4Array, pgtype.Float8Array, pgtype.NumericArray, pgtype.BPCharArray, pgtype.TextArray:
-		// todo: chk column type
-		if isArray {
-			return fmt.Sprintf("%s@>$%d", name, b.posFilter)
-		}
-		cond = "ANY($%[1]d)"
-
-	case pgtype.Numrange, pgtype.Int4range, pgtype.Int8range, *pgtype.Numrange, *pgtype.Int4range, *pgtype.Int8range:
-		return fmt.Sprintf("%s::numeric<@($%d::numrange)", name, b.posFilter)
-
-	case pgtype.Daterange:
-		return b.dateRangeChk(name, &arg, column)
-
-	case *pgtype.Daterange:
-		return b.dateRangeChk(name, arg, column)
-
 	case string:
 		if strings.Contains(arg, "is ") {
 			cond = arg
 		}
-	default:
-		//logs.StatusLog("%T %[1]t", arg)
 	}
 
 	if strings.Contains(cond, "is ") {
@@ -491,6 +572,15 @@ func (b *SQLBuilder) chkSpecialParams(name string, hasTpl bool) string {
 		return name + " " + cond
 	}
 
+	if !hasTpl {
+		if cond, ok := b.columnCondition(name, column); ok {
+			return cond
+		}
+		b.convertArg(b.posFilter-1, name)
+	} else if isListArg(b.Args[b.posFilter-1]) {
+		cond = "ANY($%[1]d)"
+	}
+
 	// format tpl
 	if hasTpl {
 		cond = fmt.Sprintf(name, cond)
@@ -501,52 +591,10 @@ func (b *SQLBuilder) chkSpecialParams(name string, hasTpl bool) string {
 	return fmt.Sprintf(cond, b.posFilter)
 }
 
-func (b *SQLBuilder) dateRangeChk(name string, arg *pgtype.Daterange, column Column) string {
-	switch column.Type() {
-	case "date":
-		return fmt.Sprintf("%s<@($%d::daterange)", name, b.posFilter)
-
-	case "timestamptz":
-		b.Args[b.posFilter-1] = &pgtype.Tstzrange{
-			Lower: pgtype.Timestamptz{
-				Time:             arg.Lower.Time,
-				Status:           arg.Lower.Status,
-				InfinityModifier: arg.Lower.InfinityModifier,
-			},
-			Upper: pgtype.Timestamptz{
-				Time:             arg.Upper.Time,
-				Status:           arg.Upper.Status,
-				InfinityModifier: arg.Upper.InfinityModifier,
-			},
-			LowerType: arg.LowerType,
-			UpperType: arg.UpperType,
-			Status:    arg.Status,
-		}
-		return fmt.Sprintf("%s<@$%d::tsrange", name, b.posFilter)
-
-	case "timestamp":
-		b.Args[b.posFilter-1] = &pgtype.Tsrange{
-			Lower: pgtype.Timestamp{
-				Time:             arg.Lower.Time,
-				Status:           arg.Lower.Status,
-				InfinityModifier: arg.Lower.InfinityModifier,
-			},
-			Upper: pgtype.Timestamp{
-				Time:             arg.Upper.Time,
-				Status:           arg.Upper.Status,
-				InfinityModifier: arg.Upper.InfinityModifier,
-			},
-			LowerType: arg.LowerType,
-			UpperType: arg.UpperType,
-			Status:    arg.Status,
-		}
-		return fmt.Sprintf("%s<@$%d::tsrange", name, b.posFilter)
-
-	case "daterange":
-		return fmt.Sprintf("%s=$%d::daterange", name, b.posFilter)
-
-	default:
-		return ""
+// convertArg replaces argument on position pos by value of registered type of column 'name' (see ConvertArg)
+func (b *SQLBuilder) convertArg(pos int, name string) {
+	if b.Table != nil && pos < len(b.Args) {
+		b.Args[pos] = ConvertArg(b.Table.FindColumn(strings.Trim(name, `"`)), b.Args[pos])
 	}
 }
 
@@ -573,7 +621,10 @@ func (b *SQLBuilder) OnConflict() string {
 
 func (b *SQLBuilder) values() string {
 	s, comma := "", ""
-	for range b.Args {
+	for i := range b.Args {
+		if i < len(b.columns) {
+			b.convertArg(b.posFilter, b.columns[i])
+		}
 		b.posFilter++
 		s += fmt.Sprintf("%s$%d", comma, b.posFilter)
 		comma = ","
@@ -586,7 +637,7 @@ var operatorSymbols = []rune{'>', '<', '$', '~', '^', '@', '&', '+', '-', '*', '
 
 func isOperatorPre(s uint8) bool {
 	switch s {
-	case '=', '>', '<', '&', '|', '#', '*', '!':
+	case '=', '>', '<', '&', '|', '#', '*', '!', '@':
 		return true
 	default:
 		return false
@@ -672,6 +723,18 @@ func Where(columns ...string) BuildSqlOptions {
 		b.filter = make([]string, len(columns))
 		if b.Table != nil {
 			for _, column := range columns {
+				if isJSON, err := b.checkJSONTerm(column); err != nil {
+					return err
+				} else if isJSON {
+					continue
+				}
+
+				if isAnyAll, err := b.checkAnyAllTerm(column); err != nil {
+					return err
+				} else if isAnyAll {
+					continue
+				}
+
 				for _, token := range strings.Split(column, " ") {
 					switch strings.ToUpper(token) {
 					case "IN", "IS", "OR", "AND", "CASE", "WHEN", "THEN", "ELSE", "END", "FROM", "WHERE", "=", "(", ")", "TRUE", "FALSE":
@@ -790,3 +853,27 @@ func Offset(i int) BuildSqlOptions {
 		return nil
 	}
 }
+
+// ValidateBeforeWrite turns on checking of values by metadata of columns before Insert, Update & Upsert
+// (see ValidateValues), so writes return ErrValidation instead of errors of DB
+func ValidateBeforeWrite() BuildSqlOptions {
+	return func(b *SQLBuilder) error {
+
+		b.validate = true
+
+		return nil
+	}
+}
+
+// Timeout set deadline for executing of sql query
+func Timeout(d time.Duration) BuildSqlOptions {
+	return func(b *SQLBuilder) error {
+		if d < 0 {
+			return NewErrWrongType("duration", "timeout", d.String())
+		}
+
+		b.Timeout = d
+
+		return nil
+	}
+}
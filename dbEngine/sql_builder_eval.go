@@ -0,0 +1,663 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// operators of Condition which don't use argument
+const (
+	OpIsNull    = "is null"
+	OpIsNotNull = "is not null"
+	OpAny       = "any"
+)
+
+// Condition is term of WHERE clause for evaluating rows without DB (csv, memory tables)
+type Condition struct {
+	Column   string
+	Operator string
+	Arg      any
+}
+
+// ColumnNames return names of selected columns - according to ColumnsForSelect or all columns of Table
+func (b *SQLBuilder) ColumnNames() []string {
+	if len(b.columns) > 0 || b.Table == nil {
+		return b.columns
+	}
+
+	names := make([]string, len(b.Table.Columns()))
+	for i, col := range b.Table.Columns() {
+		names[i] = col.Name()
+	}
+
+	return names
+}
+
+// Conditions return terms of WHERE clause with their arguments,
+// it handles prefix operators same as Where & 'is null'/'is not null' terms
+func (b *SQLBuilder) Conditions() ([]Condition, error) {
+	return b.conditions(0)
+}
+
+// UpdateValues return names & values of updated columns and terms of WHERE clause,
+// arguments of Update consist of values of columns & after them arguments of WHERE
+func (b *SQLBuilder) UpdateValues() ([]string, []any, []Condition, error) {
+	if len(b.columns) > len(b.Args) {
+		return nil, nil, nil, NewErrWrongArgsLen(b.Table.Name(), b.columns, b.Args)
+	}
+
+	conditions, err := b.conditions(len(b.columns))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return b.columns, b.Args[:len(b.columns)], conditions, nil
+}
+
+// UpsertKeys return columns of conflict target same as UpsertSql chooses them:
+// InsertOnConflict, Where, primary columns among inserted or first unique index,
+// doNothing is true for InsertOnConflictDoNothing
+func (b *SQLBuilder) UpsertKeys() (keys []string, doNothing bool, err error) {
+	switch b.onConflict {
+	case "DO NOTHING":
+		doNothing = true
+	case "":
+	default:
+		keys = strings.Split(b.onConflict, ",")
+		for i, name := range keys {
+			keys[i] = strings.TrimSpace(name)
+		}
+		return keys, false, nil
+	}
+
+	if len(b.filter) > 0 {
+		return b.filter, doNothing, nil
+	}
+
+	keys, err = b.conflictKeys()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(keys) == 0 {
+		for _, ind := range b.Table.Indexes() {
+			if ind.Unique && strings.TrimSpace(ind.Expr) == "" {
+				return ind.Columns, doNothing, nil
+			}
+		}
+	}
+
+	return keys, doNothing, nil
+}
+
+func (b *SQLBuilder) conditions(pos int) ([]Condition, error) {
+	conditions := make([]Condition, 0, len(b.filter))
+	for _, name := range b.filter {
+		if isComplex, hasTpl := b.isComplexCondition(name); isComplex {
+			col, cond, ok := strings.Cut(name, " is ")
+			if !ok || hasTpl {
+				return nil, NewErrUnsupportedCondition(name)
+			}
+
+			op := "is " + strings.ToLower(strings.TrimSpace(cond))
+			if op != OpIsNull && op != OpIsNotNull {
+				return nil, NewErrUnsupportedCondition(name)
+			}
+			conditions = append(conditions, Condition{Column: strings.TrimSpace(col), Operator: op})
+			continue
+		}
+
+		if pos >= len(b.Args) {
+			return nil, NewErrWrongArgsLen(b.Table.Name(), b.filter, b.Args)
+		}
+
+		cond := Condition{Column: name, Operator: "=", Arg: b.Args[pos]}
+		pos++
+
+		if pre := name[0]; isOperator(pre) && len(name) > 1 {
+			cond.Operator = string(pre)
+			if isOperatorPre(name[1]) {
+				cond.Operator += string(name[1])
+				cond.Column = name[2:]
+			} else {
+				cond.Column = name[1:]
+			}
+		} else {
+			switch arg := cond.Arg.(type) {
+			case nil:
+				cond.Operator = OpIsNull
+			case string:
+				if strings.Contains(arg, "is ") {
+					cond.Operator, cond.Arg = strings.ToLower(strings.TrimSpace(arg)), nil
+				}
+			case []byte:
+			default:
+				if reflect.ValueOf(arg).Kind() == reflect.Slice {
+					cond.Operator = OpAny
+				}
+			}
+		}
+
+		conditions = append(conditions, cond)
+	}
+
+	return conditions, nil
+}
+
+// Match check value of column according to condition
+func (c Condition) Match(value any) (bool, error) {
+	switch c.Operator {
+	case OpIsNull:
+		return value == nil, nil
+	case OpIsNotNull:
+		return value != nil, nil
+	}
+
+	// NULL isn't equal to anything
+	if value == nil {
+		return false, nil
+	}
+
+	// same as SQLBuilder does for columns of arrays
+	if isListArg(value) {
+		switch {
+		case c.Operator == "=" && !isListArg(c.Arg):
+			return matchArrays("@>", value, []any{c.Arg}, c.Column)
+		case c.Operator == "=", c.Operator == OpAny:
+			return matchArrays("@>", value, c.Arg, c.Column)
+		}
+	}
+
+	switch c.Operator {
+	case "=", ">", ">=", "<", "<=", "<>":
+		res, err := CompareValues(value, c.Arg)
+		if err != nil {
+			return false, errors.Wrap(err, c.Column)
+		}
+
+		switch c.Operator {
+		case "=":
+			return res == 0, nil
+		case ">":
+			return res > 0, nil
+		case ">=":
+			return res >= 0, nil
+		case "<":
+			return res < 0, nil
+		case "<=":
+			return res <= 0, nil
+		default:
+			return res != 0, nil
+		}
+
+	case OpAny:
+		arg := reflect.ValueOf(c.Arg)
+		for i := 0; i < arg.Len(); i++ {
+			res, err := CompareValues(value, arg.Index(i).Interface())
+			if err != nil {
+				return false, errors.Wrap(err, c.Column)
+			}
+			if res == 0 {
+				return true, nil
+			}
+		}
+
+		return false, nil
+
+	case "@>", "<@", "&&":
+		return matchArrays(c.Operator, value, c.Arg, c.Column)
+
+	// same patterns as in writeCondition
+	case "~", "~*", "$", "^", "*":
+		pattern := fmt.Sprint(c.Arg)
+		switch c.Operator {
+		case "~*":
+			pattern = "(?i)" + pattern
+		case "$":
+			pattern = ".*" + pattern + "$"
+		case "^":
+			pattern = "^.*" + pattern
+		case "*":
+			pattern = "(?i)^.*" + pattern
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, errors.Wrap(err, c.Column)
+		}
+
+		return re.MatchString(fmt.Sprint(value)), nil
+
+	default:
+		return false, NewErrUnsupportedCondition(c.Operator + c.Column)
+	}
+}
+
+// matchArrays check containment (@>, <@) & overlap (&&) of arrays, ranges aren't supported without DB
+func matchArrays(operator string, value, arg any, column string) (bool, error) {
+	a, b := reflect.ValueOf(value), reflect.ValueOf(arg)
+	if !isListArg(value) || !isListArg(arg) || a.Kind() == reflect.Struct || b.Kind() == reflect.Struct {
+		return false, NewErrUnsupportedCondition(operator + column)
+	}
+
+	if operator == "<@" {
+		a, b = b, a
+	}
+
+	contains := func(list reflect.Value, v any) (bool, error) {
+		for i := 0; i < list.Len(); i++ {
+			res, err := CompareValues(list.Index(i).Interface(), v)
+			if err != nil {
+				return false, errors.Wrap(err, column)
+			}
+			if res == 0 {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	for i := 0; i < b.Len(); i++ {
+		ok, err := contains(a, b.Index(i).Interface())
+		switch {
+		case err != nil:
+			return false, err
+		case operator == "&&" && ok:
+			return true, nil
+		case operator != "&&" && !ok:
+			return false, nil
+		}
+	}
+
+	return operator != "&&", nil
+}
+
+// MatchConditions check all conditions for row, value return value of column by name
+func MatchConditions(conditions []Condition, value func(name string) (any, error)) (bool, error) {
+	for _, cond := range conditions {
+		v, err := value(cond.Column)
+		if err != nil {
+			return false, err
+		}
+
+		ok, err := cond.Match(v)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// CompareValues compares a & b converting numbers, bool, time & text between each other,
+// it returns -1 if a < b, 0 if a == b, +1 if a > b, NULL is less than any value
+func CompareValues(a, b any) (int, error) {
+	switch {
+	case a == nil && b == nil:
+		return 0, nil
+	case a == nil:
+		return -1, nil
+	case b == nil:
+		return 1, nil
+	}
+
+	switch x := a.(type) {
+	case time.Time:
+		y, err := toTime(b)
+		if err != nil {
+			return 0, err
+		}
+		return x.Compare(y), nil
+
+	case bool:
+		y, err := toBool(b)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(boolToInt(x), boolToInt(y)), nil
+
+	case string:
+		switch y := b.(type) {
+		case string:
+			return strings.Compare(x, y), nil
+		case time.Time, bool:
+			res, err := CompareValues(b, a)
+			return -res, err
+		}
+	}
+
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			return cmp.Compare(x, y), nil
+		}
+		return 0, NewErrWrongType(fmt.Sprintf("%T", b), fmt.Sprint(b), "number")
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)), nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func toBool(v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return false, NewErrWrongType(fmt.Sprintf("%T", v), fmt.Sprint(v), "bool")
+	}
+}
+
+// TimeLayouts are layouts for parsing text values as time
+var TimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05", time.DateOnly}
+
+// ParseTime parse s according to TimeLayouts
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, NewErrWrongType("time", s, "layout")
+}
+
+func toTime(v any) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		return *v, nil
+	case string:
+		return ParseTime(v)
+	default:
+		return time.Time{}, NewErrWrongType(fmt.Sprintf("%T", v), fmt.Sprint(v), "time")
+	}
+}
+
+func toNumber(v any) (float64, bool) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// SortRows sorts rows according to OrderBy of SQLBuilder ('name' or 'name desc'),
+// value return value of column by name for row
+func SortRows[T any](b *SQLBuilder, rows []T, value func(row T, name string) (any, error)) (err error) {
+	if len(b.OrderBy) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(rows, func(x, y T) int {
+		for _, order := range b.OrderBy {
+			name, desc := strings.CutSuffix(strings.TrimSpace(order), " desc")
+			name = strings.TrimSuffix(name, " asc")
+			vx, e := value(x, name)
+			if e != nil {
+				err = e
+				return 0
+			}
+			vy, e := value(y, name)
+			if e != nil {
+				err = e
+				return 0
+			}
+
+			res, e := CompareValues(vx, vy)
+			if e != nil {
+				err = e
+				return 0
+			}
+			if res != 0 {
+				if desc {
+					return -res
+				}
+				return res
+			}
+		}
+
+		return 0
+	})
+
+	return err
+}
+
+// PageRows return part of rows according to Offset & FetchOnlyRows of SQLBuilder
+func PageRows[T any](b *SQLBuilder, rows []T) []T {
+	if b.Offset > 0 {
+		if b.Offset >= len(rows) {
+			return rows[:0]
+		}
+		rows = rows[b.Offset:]
+	}
+
+	if b.Limit > 0 && b.Limit < len(rows) {
+		rows = rows[:b.Limit]
+	}
+
+	return rows
+}
+
+// ColumnIndex return index of column 'name' in columns or -1
+func ColumnIndex(columns []Column, name string) int {
+	return slices.IndexFunc(columns, func(col Column) bool {
+		return col.Name() == name
+	})
+}
+
+// RowValue return value of column 'name' from row, which has values of all columns of table t
+func RowValue(t Table, row []any, name string) (any, error) {
+	i := ColumnIndex(t.Columns(), name)
+	if i < 0 {
+		return nil, NewErrNotFoundColumn(t.Name(), name)
+	}
+
+	return row[i], nil
+}
+
+// SelectRows return selected columns & their values of rows according to SQLBuilder
+// (Where with prefix operators, OrderBy, Offset, FetchOnlyRows),
+// rows have values of all columns of table t, caller must lock them
+func SelectRows(b *SQLBuilder, t Table, rows [][]any) ([]Column, [][]any, error) {
+	conditions, err := b.Conditions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := func(row []any, name string) (any, error) {
+		return RowValue(t, row, name)
+	}
+
+	matched := make([][]any, 0, len(rows))
+	for _, row := range rows {
+		ok, err := MatchConditions(conditions, func(name string) (any, error) {
+			return value(row, name)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			matched = append(matched, row)
+		}
+	}
+
+	if err := SortRows(b, matched, value); err != nil {
+		return nil, nil, err
+	}
+	matched = PageRows(b, matched)
+
+	names := b.ColumnNames()
+	columns := make([]Column, len(names))
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		indexes[i] = ColumnIndex(t.Columns(), name)
+		if indexes[i] < 0 {
+			return nil, nil, NewErrNotFoundColumn(t.Name(), name)
+		}
+		columns[i] = t.Columns()[indexes[i]]
+	}
+
+	result := make([][]any, len(matched))
+	for i, row := range matched {
+		result[i] = make([]any, len(indexes))
+		for j, ind := range indexes {
+			result[i][j] = row[ind]
+		}
+	}
+
+	return columns, result, nil
+}
+
+// ScanRow writes values of row into dest with converting of types (see ScanValue)
+func ScanRow(dest []any, values []any, columns []Column) error {
+	if len(dest) != len(values) {
+		return NewErrWrongType(fmt.Sprintf("%d values", len(values)), "dest", fmt.Sprintf("%d fields", len(dest)))
+	}
+
+	for i, value := range values {
+		if err := ScanValue(dest[i], value); err != nil {
+			return errors.Wrap(err, columns[i].Name())
+		}
+	}
+
+	return nil
+}
+
+// FormatRaw return value in text format of PostgreSQL
+func FormatRaw(value any) []byte {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return v
+	case bool:
+		if v {
+			return []byte("t")
+		}
+		return []byte("f")
+	case time.Time:
+		return []byte(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
+
+// ScanValue writes value into dest (pointer or sql.Scanner) with converting of types
+func ScanValue(dest, value any) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return NewErrWrongType(fmt.Sprintf("%T", dest), "dest", "pointer")
+	}
+
+	elem := dv.Elem()
+	if value == nil {
+		elem.SetZero()
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Type().AssignableTo(elem.Type()):
+		elem.Set(rv)
+
+	case elem.Kind() == reflect.Pointer:
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		return ScanValue(elem.Interface(), value)
+
+	case elem.Kind() == reflect.String:
+		elem.SetString(fmt.Sprint(value))
+
+	case rv.Kind() == reflect.String:
+		return scanText(elem, rv.String())
+
+	case rv.CanConvert(elem.Type()) && rv.Kind() != reflect.Bool && elem.Kind() != reflect.Bool:
+		elem.Set(rv.Convert(elem.Type()))
+
+	default:
+		return NewErrWrongType(fmt.Sprintf("%T", value), fmt.Sprintf("%T", dest), "scan")
+	}
+
+	return nil
+}
+
+// scanText parses text into numbers, bool or time
+func scanText(elem reflect.Value, s string) error {
+	switch elem.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, elem.Type().Bits())
+		if err == nil {
+			elem.SetInt(i)
+		}
+		return err
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, elem.Type().Bits())
+		if err == nil {
+			elem.SetUint(i)
+		}
+		return err
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, elem.Type().Bits())
+		if err == nil {
+			elem.SetFloat(f)
+		}
+		return err
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err == nil {
+			elem.SetBool(b)
+		}
+		return err
+	}
+
+	if elem.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := ParseTime(s)
+		if err == nil {
+			elem.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+
+	return NewErrWrongType("string", elem.Type().String(), "scan")
+}
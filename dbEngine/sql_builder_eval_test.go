@@ -0,0 +1,121 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareValues(t *testing.T) {
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		a, b any
+		want int
+	}{
+		{"ints", 1, int64(2), -1},
+		{"int & float", int32(2), 1.5, 1},
+		{"number & text", int64(10), "10", 0},
+		{"text", "b", "a", 1},
+		{"bool", true, "false", 1},
+		{"time & text", date, "2024-01-02", 0},
+		{"text & time", "2024-01-01", date, -1},
+		{"null", nil, 1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareValues(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := CompareValues(1, "one")
+	assert.Error(t, err)
+}
+
+func TestSQLBuilder_Conditions(t *testing.T) {
+	b := &SQLBuilder{
+		filter: []string{">=id", "name", "memo is not null", "tags", "~*title", "deleted"},
+		Args:   []any{1, nil, []int{1, 2}, "^a", "is null"},
+	}
+	conditions, err := b.Conditions()
+	require.NoError(t, err)
+	assert.Equal(t, []Condition{
+		{Column: "id", Operator: ">=", Arg: 1},
+		{Column: "name", Operator: OpIsNull},
+		{Column: "memo", Operator: OpIsNotNull},
+		{Column: "tags", Operator: OpAny, Arg: []int{1, 2}},
+		{Column: "title", Operator: "~*", Arg: "^a"},
+		{Column: "deleted", Operator: OpIsNull},
+	}, conditions)
+
+	ok, err := conditions[4].Match("Apple")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	b.filter = []string{"(id + 1) = %s"}
+	_, err = b.Conditions()
+	assert.ErrorAs(t, err, new(*ErrUnsupportedCondition))
+}
+
+func TestScanValue(t *testing.T) {
+	var (
+		i int32
+		s string
+		p *float64
+		d time.Time
+		v any
+	)
+	require.NoError(t, ScanValue(&i, int64(5)))
+	assert.Equal(t, int32(5), i)
+	require.NoError(t, ScanValue(&s, 1.5))
+	assert.Equal(t, "1.5", s)
+	require.NoError(t, ScanValue(&p, 2.5))
+	assert.Equal(t, 2.5, *p)
+	require.NoError(t, ScanValue(&p, nil))
+	assert.Nil(t, p)
+	require.NoError(t, ScanValue(&d, "2024-01-02"))
+	assert.Equal(t, 2024, d.Year())
+	require.NoError(t, ScanValue(&v, "text"))
+	assert.Equal(t, "text", v)
+	assert.Error(t, ScanValue(i, 1))
+}
+
+func TestSelectRows(t *testing.T) {
+	table := NewTableString("goods", "", []Column{
+		NewNumberColumn("id", "", false),
+		NewStringColumn("name", "", true),
+	}, nil, nil)
+	rows := [][]any{{int64(1), "apple"}, {int64(2), nil}, {int64(3), "cherry"}}
+
+	b, err := NewSQLBuilder(table, ColumnsForSelect("name"), WhereForSelect(">id"), ArgsForSelect(1),
+		OrderBy("id desc"))
+	require.NoError(t, err)
+	columns, got, err := SelectRows(b, table, rows)
+	require.NoError(t, err)
+	require.Len(t, columns, 1)
+	assert.Equal(t, "name", columns[0].Name())
+	assert.Equal(t, [][]any{{"cherry"}, {nil}}, got)
+
+	var name *string
+	require.NoError(t, ScanRow([]any{&name}, got[1], columns))
+	assert.Nil(t, name)
+	assert.Error(t, ScanRow([]any{&name}, rows[0], columns))
+
+	_, err = RowValue(table, rows[0], "price")
+	assert.ErrorAs(t, err, new(*ErrNotFoundColumn))
+}
+
+func TestFormatRaw(t *testing.T) {
+	assert.Nil(t, FormatRaw(nil))
+	assert.Equal(t, []byte("t"), FormatRaw(true))
+	assert.Equal(t, []byte("1.5"), FormatRaw(1.5))
+	assert.Equal(t, []byte("2024-01-02 03:04:05Z"), FormatRaw(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrFullTextNotSet means that options of ranking or headline are used without FullText
+var ErrFullTextNotSet = errors.New("condition FullText must be set before")
+
+// ErrWrongTextSearchConfig means that config of FullText isn't name of text search configuration
+var ErrWrongTextSearchConfig = errors.New("wrong name of text search config")
+
+// regTextSearchConfig matches name of text search configuration with optional schema,
+// config is written into SQL as literal, so it mustn't have other symbols
+var regTextSearchConfig = regexp.MustCompile(`^[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?$`)
+
+// fullTextSearch consists of parameters of condition FullText
+type fullTextSearch struct {
+	expr, query, config string
+	headline            string
+	rank                bool
+	// pos is number of sql parameter with query
+	pos int
+}
+
+// tsVector return expression of document
+func (fts *fullTextSearch) tsVector() string {
+	if fts.config == "" {
+		return fmt.Sprintf("to_tsvector(%s)", fts.expr)
+	}
+
+	return fmt.Sprintf("to_tsvector('%s', %s)", fts.config, fts.expr)
+}
+
+// tsQuery return expression of query with parameter param
+func (fts *fullTextSearch) tsQuery(param string) string {
+	if fts.config == "" {
+		return fmt.Sprintf("websearch_to_tsquery(%s)", param)
+	}
+
+	return fmt.Sprintf("websearch_to_tsquery('%s', %s)", fts.config, param)
+}
+
+// condition return term of WHERE clause with template for parameter
+func (fts *fullTextSearch) condition() string {
+	return fts.tsVector() + " @@ " + fts.tsQuery("%s")
+}
+
+// rankExpr return term of ORDER BY clause with ranking by query
+func (fts *fullTextSearch) rankExpr() string {
+	return fmt.Sprintf("ts_rank(%s, %s) desc", fts.tsVector(), fts.tsQuery(fmt.Sprintf("$%d", fts.pos)))
+}
+
+// headlineExpr return column of select list with fragments of document which match query
+func (fts *fullTextSearch) headlineExpr() string {
+	if fts.config == "" {
+		return fmt.Sprintf("ts_headline(%s, %s) as %s", fts.expr, fts.tsQuery(fmt.Sprintf("$%d", fts.pos)), fts.headline)
+	}
+
+	return fmt.Sprintf("ts_headline('%s', %s, %s) as %s",
+		fts.config, fts.expr, fts.tsQuery(fmt.Sprintf("$%d", fts.pos)), fts.headline)
+}
+
+// FullText adds condition of full text search to WHERE clause:
+//
+//	to_tsvector(config, expr) @@ websearch_to_tsquery(config, query)
+//
+// expr is column or expression with columns of table (it must be same as expression of GIN index),
+// empty config means default_text_search_config of DB, otherwise it must be name of text search configuration,
+// query is appended to arguments after arguments of other conditions
+func FullText(expr, query, config string) BuildSqlOptions {
+	return func(b *SQLBuilder) error {
+		expr = strings.TrimSpace(expr)
+		if b.Table != nil {
+			if _, ok := CheckColumn(expr, b.Table); !ok {
+				return NewErrNotFoundColumn(b.Table.Name(), expr)
+			}
+		}
+
+		config = strings.Trim(config, "'")
+		if config > "" && !regTextSearchConfig.MatchString(config) {
+			return errors.Wrap(ErrWrongTextSearchConfig, config)
+		}
+
+		b.fullText = &fullTextSearch{expr: expr, query: query, config: config}
+
+		return nil
+	}
+}
+
+// OrderByRank sorts rows by ts_rank of FullText before columns of OrderBy
+func OrderByRank() BuildSqlOptions {
+	return func(b *SQLBuilder) error {
+		if b.fullText == nil {
+			return errors.Wrap(ErrFullTextNotSet, "OrderByRank")
+		}
+
+		b.fullText.rank = true
+
+		return nil
+	}
+}
+
+// Headline adds ts_headline of FullText into select list as column 'alias'
+func Headline(alias string) BuildSqlOptions {
+	return func(b *SQLBuilder) error {
+		if b.fullText == nil {
+			return errors.Wrap(ErrFullTextNotSet, "Headline")
+		}
+
+		b.fullText.headline = alias
+
+		return nil
+	}
+}
+
+// appendHeadline adds column of Headline to selected columns
+func (b *SQLBuilder) appendHeadline(columns []Column) []Column {
+	if b.fullText == nil || b.fullText.headline == "" {
+		return columns
+	}
+
+	return append(columns, NewStringColumn(b.fullText.headline, b.fullText.headlineExpr(), false))
+}
+
+// isFullTextTerm return true if term of WHERE clause is condition of FullText
+func (b *SQLBuilder) isFullTextTerm(term string) bool {
+	return b.fullText != nil && term == b.fullText.condition()
+}
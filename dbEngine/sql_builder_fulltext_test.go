@@ -0,0 +1,70 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLBuilder_FullText(t *testing.T) {
+	table := NewTableString("articles", "", SimpleColumns("id", "title", "body"), nil, nil)
+	expr := "coalesce(title,'') || ' ' || coalesce(body,'')"
+
+	b, err := NewSQLBuilder(table,
+		ColumnsForSelect("id", "title"),
+		FullText(expr, "what is postgres", "simple"),
+		OrderByRank(),
+		Headline("fragment"),
+		WhereForSelect("id"),
+		ArgsForSelect(nil),
+		OrderBy("title"),
+	)
+	require.NoError(t, err)
+
+	sql, err := b.SelectSql()
+	require.NoError(t, err)
+	tsQuery := "websearch_to_tsquery('simple', $1)"
+	assert.Equal(t, "SELECT id,title,ts_headline('simple', "+expr+", "+tsQuery+") as fragment FROM articles"+
+		" WHERE id is null AND to_tsvector('simple', "+expr+") @@ "+tsQuery+
+		" order by ts_rank(to_tsvector('simple', "+expr+"), "+tsQuery+") desc,title", sql)
+	assert.Equal(t, []any{"what is postgres"}, b.Args)
+
+	columns := b.SelectColumns()
+	require.Len(t, columns, 3)
+	assert.Equal(t, "fragment", columns[2].Name())
+
+	b, err = NewSQLBuilder(table, FullText("title", "postgres", ""), WhereForSelect("id"), ArgsForSelect(1))
+	require.NoError(t, err)
+	sql, err = b.DeleteSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM articles WHERE id=$1 AND to_tsvector(title) @@ websearch_to_tsquery($2)", sql)
+
+	_, err = NewSQLBuilder(table, OrderByRank())
+	assert.ErrorIs(t, err, ErrFullTextNotSet)
+
+	_, err = NewSQLBuilder(table, FullText("unknown", "postgres", ""))
+	var errColumn *ErrNotFoundColumn
+	assert.ErrorAs(t, err, &errColumn)
+
+	_, err = NewSQLBuilder(table, FullText("title", "postgres", "english'); drop table articles; --"))
+	assert.ErrorIs(t, err, ErrWrongTextSearchConfig)
+
+	b, err = NewSQLBuilder(table, FullText("title", "postgres", "public.english"), WhereForSelect("id"), ArgsForSelect(1))
+	require.NoError(t, err)
+	sql, err = b.DeleteSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM articles WHERE id=$1 AND to_tsvector('public.english', title) @@ websearch_to_tsquery('public.english', $2)", sql)
+}
+
+func TestNormalizeIndexExpr(t *testing.T) {
+	assert.Equal(t,
+		normalizeIndexExpr("to_tsvector('simple', coalesce(title,''))"),
+		normalizeIndexExpr("to_tsvector('simple'::regconfig, COALESCE(title, ''::text))"))
+	assert.True(t, (&Index{Method: "gin", Expr: "to_tsvector(title)"}).IsFullText())
+	assert.False(t, (&Index{Expr: "to_tsvector(title)"}).IsFullText())
+}
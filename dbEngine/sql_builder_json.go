@@ -0,0 +1,150 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regJSONTerm recognizes terms of WHERE & SELECT clauses with JSON operators,
+// groups are name of column as argument of jsonb_path_exists or name of column & operator
+var regJSONTerm = regexp.MustCompile(`^(?:jsonb_path_exists\((\w+)\s*,|(\w+)\s*(->>?|#>>?|@>|\?[|&]?))`)
+
+// regJSONProjection recognizes JSON sub-path in select list as 'column->'key' as alias'
+var regJSONProjection = regexp.MustCompile(`^(\w+)\s*(->>?|#>>?)[^;]*\s+as\s+(\w+)$`)
+
+// JSONField return expression of json value from column by keys path: column->'key'->'sub_key'
+// numeric keys are indexes of arrays
+func JSONField(column string, keys ...string) string {
+	return column + jsonKeys(keys, "->")
+}
+
+// JSONFieldText return expression of text value from column by keys path: column->'key'->>'sub_key'
+func JSONFieldText(column string, keys ...string) string {
+	if len(keys) == 0 {
+		return column
+	}
+
+	return column + jsonKeys(keys[:len(keys)-1], "->") + jsonKeys(keys[len(keys)-1:], "->>")
+}
+
+// JSONPathText return expression of text value from column by path: column#>>'{key,sub_key}'
+func JSONPathText(column string, path ...string) string {
+	return fmt.Sprintf("%s#>>'{%s}'", column, strings.ReplaceAll(strings.Join(path, ","), "'", "''"))
+}
+
+// JSONAs return JSON expression (see JSONField, JSONFieldText, JSONPathText) with alias
+// for ColumnsForSelect, so RowScanner gets sub-path as column 'alias'
+func JSONAs(expr, alias string) string {
+	return expr + " as " + alias
+}
+
+// JSONCompare return condition for WhereForSelect which compares JSON expression with argument by operator
+func JSONCompare(expr, operator string) string {
+	return fmt.Sprintf("%s %s %%s", expr, operator)
+}
+
+// JSONContains return condition for WhereForSelect: json of column contains argument (@>)
+func JSONContains(column string) string {
+	return column + " @> %s"
+}
+
+// JSONHasKey return condition for WhereForSelect: argument is top-level key of column (?)
+func JSONHasKey(column string) string {
+	return column + " ? %s"
+}
+
+// JSONHasAnyKeys return condition for WhereForSelect: any of argument's strings is top-level key of column (?|)
+func JSONHasAnyKeys(column string) string {
+	return column + " ?| %s"
+}
+
+// JSONHasAllKeys return condition for WhereForSelect: all of argument's strings are top-level keys of column (?&)
+func JSONHasAllKeys(column string) string {
+	return column + " ?& %s"
+}
+
+// JSONPathExists return condition for WhereForSelect: jsonpath of argument returns any item for column
+func JSONPathExists(column string) string {
+	return "jsonb_path_exists(" + column + ", %s)"
+}
+
+func jsonKeys(keys []string, operator string) string {
+	s := ""
+	for _, key := range keys {
+		if regPSQLDigits.MatchString(key) && !strings.ContainsAny(key, ".,") {
+			s += operator + key
+		} else {
+			s += operator + "'" + strings.ReplaceAll(key, "'", "''") + "'"
+		}
+	}
+
+	return s
+}
+
+// IsJSONColumn return true if column has type json or jsonb
+func IsJSONColumn(col Column) bool {
+	if col == nil {
+		return false
+	}
+
+	switch col.Type() {
+	case "json", "jsonb":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkJSONTerm return true if term has JSON operator for column with json type,
+// error if operator may be used for json only
+func (b *SQLBuilder) checkJSONTerm(term string) (bool, error) {
+	m := regJSONTerm.FindStringSubmatch(term)
+	if len(m) == 0 || b.Table == nil {
+		return false, nil
+	}
+
+	name, operator := m[1]+m[2], m[3]
+	if operator == "" {
+		operator = "jsonb_path_exists"
+	}
+
+	col := b.Table.FindColumn(name)
+	switch {
+	case col == nil:
+		return false, NewErrNotFoundColumn(b.Table.Name(), name)
+	case IsJSONColumn(col):
+		return true, nil
+	case operator == "@>":
+		// containment of arrays
+		return false, nil
+	default:
+		return false, NewErrWrongType(col.Type(), name, "json operator "+operator)
+	}
+}
+
+// isJSONTerm return true if term has JSON operator for column with json type
+func (b *SQLBuilder) isJSONTerm(term string) bool {
+	ok, err := b.checkJSONTerm(term)
+	return ok && err == nil
+}
+
+// jsonProjection return column for JSON sub-path with alias
+func (b *SQLBuilder) jsonProjection(name string) (Column, bool) {
+	m := regJSONProjection.FindStringSubmatch(name)
+	if len(m) == 0 || !b.isJSONTerm(name) {
+		return nil, false
+	}
+
+	// type of result depends on last operator
+	expr, _, _ := strings.Cut(name, " as ")
+	if strings.LastIndex(expr, ">>") > strings.LastIndex(expr, "->") {
+		return NewStringColumn(m[3], name, false), true
+	}
+
+	return NewJSONBColumn(m[3], name, false), true
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLBuilder_JSON(t *testing.T) {
+	table := NewTableString("events", "", []Column{
+		NewNumberColumn("id", "", true),
+		NewJSONBColumn("data", "", false),
+		NewArrayColumn("tags", "", false, NewStringColumn("tags", "", false)),
+	}, nil, nil)
+
+	assert.Equal(t, "data->'user'->>'name'", JSONFieldText("data", "user", "name"))
+	assert.Equal(t, "data->'items'->0", JSONField("data", "items", "0"))
+	assert.Equal(t, "data#>>'{user,o''name}'", JSONPathText("data", "user", "o'name"))
+
+	b, err := NewSQLBuilder(table,
+		ColumnsForSelect("id", JSONAs(JSONFieldText("data", "user", "name"), "user_name"),
+			JSONAs(JSONField("data", "items"), "items")),
+		WhereForSelect(
+			JSONCompare(JSONPathText("data", "user", "age"), ">"),
+			JSONContains("data"),
+			JSONHasKey("data"),
+			JSONHasAnyKeys("data"),
+			JSONHasAllKeys("data"),
+			JSONPathExists("data"),
+			"tags",
+		),
+		ArgsForSelect(18, `{"active":true}`, "user", []string{"a", "b"}, []string{"c", "d"}, "$.items[*]", []string{"x"}),
+	)
+	require.NoError(t, err)
+
+	sql, err := b.SelectSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id,data->'user'->>'name' as user_name,data->'items' as items FROM events"+
+		" WHERE data#>>'{user,age}' > $1 AND data @> $2 AND data ? $3 AND data ?| $4 AND data ?& $5"+
+		" AND jsonb_path_exists(data, $6) AND tags@>$7", sql)
+
+	columns := b.SelectColumns()
+	require.Len(t, columns, 3)
+	assert.Equal(t, "user_name", columns[1].Name())
+	assert.Equal(t, "string", columns[1].Type())
+	assert.Equal(t, "items", columns[2].Name())
+	assert.True(t, IsJSONColumn(columns[2]))
+
+	_, err = NewSQLBuilder(table, WhereForSelect(JSONHasKey("tags")))
+	var errType *ErrWrongType
+	assert.ErrorAs(t, err, &errType)
+
+	_, err = NewSQLBuilder(table, WhereForSelect(JSONPathExists("unknown")))
+	var errColumn *ErrNotFoundColumn
+	assert.ErrorAs(t, err, &errColumn)
+}
@@ -0,0 +1,340 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgtype"
+)
+
+// rangeTypes consists of range types of PostgreSQL according to types of their elements
+var rangeTypes = map[string]string{
+	"int2":        "int4range",
+	"int4":        "int4range",
+	"int8":        "int8range",
+	"float4":      "numrange",
+	"float8":      "numrange",
+	"numeric":     "numrange",
+	"money":       "numrange",
+	"date":        "daterange",
+	"timestamp":   "tsrange",
+	"timestamptz": "tstzrange",
+}
+
+// rangeElemTypes consists of types of elements of range types
+var rangeElemTypes = map[string]string{
+	"int4range": "int4",
+	"int8range": "int8",
+	"numrange":  "numeric",
+	"daterange": "date",
+	"tsrange":   "timestamp",
+	"tstzrange": "timestamptz",
+}
+
+// udtAliases consists of udt names of PostgreSQL for types of core columns & DDL
+var udtAliases = map[string]string{
+	"int":              "int4",
+	"integer":          "int4",
+	"smallint":         "int2",
+	"bigint":           "int8",
+	"real":             "float4",
+	"double precision": "float8",
+	"decimal":          "numeric",
+	"boolean":          "bool",
+	"string":           "text",
+}
+
+// regAnyAllTerm recognizes conditions of AnyOf & AllOf
+var regAnyAllTerm = regexp.MustCompile(`^(\w+)\s*(?:=|<>|!=|<=?|>=?|!?~\*?|(?i:(?:not\s+)?i?like))\s*(?:ANY|ALL)\(%s\)$`)
+
+// AnyOf return condition for WhereForSelect: 'column operator ANY(argument)',
+// argument is slice of values for elements of column type
+func AnyOf(column, operator string) string {
+	return fmt.Sprintf("%s %s ANY(%%s)", column, operator)
+}
+
+// AllOf return condition for WhereForSelect: 'column operator ALL(argument)',
+// argument is slice of values for elements of column type
+func AllOf(column, operator string) string {
+	return fmt.Sprintf("%s %s ALL(%%s)", column, operator)
+}
+
+// columnUdtName return udt name of column type or type of its elements for arrays
+func columnUdtName(col Column) string {
+	typ := col.Type()
+	if isArrayColumn(col) {
+		typ = strings.TrimPrefix(typ, "_")
+	}
+
+	if alias, ok := udtAliases[typ]; ok {
+		return alias
+	}
+
+	return typ
+}
+
+func isArrayColumn(col Column) bool {
+	c, ok := col.(interface{ IsArray() bool })
+	return ok && c.IsArray()
+}
+
+func isRangeColumn(col Column) bool {
+	_, ok := rangeElemTypes[col.Type()]
+	return ok
+}
+
+// arrayCast return cast for argument with array of column's elements,
+// arrays of user defined types (enums, etc.) are passed as text[] because pgx doesn't know their OID
+func arrayCast(col Column) string {
+	if col == nil || col.UserDefinedType() == nil {
+		return ""
+	}
+
+	return "::text[]::" + columnUdtName(col) + "[]"
+}
+
+// isListArg return true if argument is array of values
+func isListArg(arg any) bool {
+	switch arg.(type) {
+	case nil, []byte, string:
+		return false
+	case pgtype.ArrayType, pgtype.Int2Array, pgtype.Int4Array, pgtype.Int8Array, pgtype.DateArray,
+		pgtype.TimestampArray, pgtype.TimestamptzArray,
+		pgtype.Float4Array, pgtype.Float8Array, pgtype.NumericArray, pgtype.BPCharArray, pgtype.TextArray:
+		return true
+	}
+
+	switch reflect.ValueOf(arg).Kind() {
+	case reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// rangeValue return value of range argument & name of its range type
+func rangeValue(arg any) (any, string, bool) {
+	switch a := arg.(type) {
+	case pgtype.Int4range:
+		return a, "int4range", true
+	case *pgtype.Int4range:
+		return *a, "int4range", true
+	case pgtype.Int8range:
+		return a, "int8range", true
+	case *pgtype.Int8range:
+		return *a, "int8range", true
+	case pgtype.Numrange:
+		return a, "numrange", true
+	case *pgtype.Numrange:
+		return *a, "numrange", true
+	case pgtype.Daterange:
+		return a, "daterange", true
+	case *pgtype.Daterange:
+		return *a, "daterange", true
+	case pgtype.Tsrange:
+		return a, "tsrange", true
+	case *pgtype.Tsrange:
+		return *a, "tsrange", true
+	case pgtype.Tstzrange:
+		return a, "tstzrange", true
+	case *pgtype.Tstzrange:
+		return *a, "tstzrange", true
+	default:
+		return nil, "", false
+	}
+}
+
+// convertRange convert value of range into rangeType because pgx encodes ranges according to their Go type
+func convertRange(arg any, rangeType string) any {
+	switch a := arg.(type) {
+	case pgtype.Int4range:
+		switch rangeType {
+		case "int8range":
+			return pgtype.Int8range{
+				Lower:     pgtype.Int8{Int: int64(a.Lower.Int), Status: a.Lower.Status},
+				Upper:     pgtype.Int8{Int: int64(a.Upper.Int), Status: a.Upper.Status},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		case "numrange":
+			return pgtype.Numrange{
+				Lower:     pgtype.Numeric{Int: big.NewInt(int64(a.Lower.Int)), Status: a.Lower.Status},
+				Upper:     pgtype.Numeric{Int: big.NewInt(int64(a.Upper.Int)), Status: a.Upper.Status},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		}
+
+	case pgtype.Int8range:
+		switch rangeType {
+		case "int4range":
+			return pgtype.Int4range{
+				Lower:     pgtype.Int4{Int: int32(a.Lower.Int), Status: a.Lower.Status},
+				Upper:     pgtype.Int4{Int: int32(a.Upper.Int), Status: a.Upper.Status},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		case "numrange":
+			return pgtype.Numrange{
+				Lower:     pgtype.Numeric{Int: big.NewInt(a.Lower.Int), Status: a.Lower.Status},
+				Upper:     pgtype.Numeric{Int: big.NewInt(a.Upper.Int), Status: a.Upper.Status},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		}
+
+	case pgtype.Daterange:
+		switch rangeType {
+		case "tsrange":
+			return pgtype.Tsrange{
+				Lower:     pgtype.Timestamp{Time: a.Lower.Time, Status: a.Lower.Status, InfinityModifier: a.Lower.InfinityModifier},
+				Upper:     pgtype.Timestamp{Time: a.Upper.Time, Status: a.Upper.Status, InfinityModifier: a.Upper.InfinityModifier},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		case "tstzrange":
+			return pgtype.Tstzrange{
+				Lower:     pgtype.Timestamptz{Time: a.Lower.Time, Status: a.Lower.Status, InfinityModifier: a.Lower.InfinityModifier},
+				Upper:     pgtype.Timestamptz{Time: a.Upper.Time, Status: a.Upper.Status, InfinityModifier: a.Upper.InfinityModifier},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		}
+
+	case pgtype.Tsrange:
+		if rangeType == "tstzrange" {
+			return pgtype.Tstzrange{
+				Lower:     pgtype.Timestamptz{Time: a.Lower.Time, Status: a.Lower.Status, InfinityModifier: a.Lower.InfinityModifier},
+				Upper:     pgtype.Timestamptz{Time: a.Upper.Time, Status: a.Upper.Status, InfinityModifier: a.Upper.InfinityModifier},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		}
+
+	case pgtype.Tstzrange:
+		if rangeType == "tsrange" {
+			return pgtype.Tsrange{
+				Lower:     pgtype.Timestamp{Time: a.Lower.Time, Status: a.Lower.Status, InfinityModifier: a.Lower.InfinityModifier},
+				Upper:     pgtype.Timestamp{Time: a.Upper.Time, Status: a.Upper.Status, InfinityModifier: a.Upper.InfinityModifier},
+				LowerType: a.LowerType, UpperType: a.UpperType, Status: a.Status,
+			}
+		}
+	}
+
+	return arg
+}
+
+// containCondition return condition with operators of containment (@>, <@) & overlap (&&)
+// for ranges & arrays according to type of column
+func (b *SQLBuilder) containCondition(name, operator string) string {
+	param := fmt.Sprintf("$%d", b.posFilter)
+	var col Column
+	if b.Table != nil {
+		col = b.Table.FindColumn(strings.Trim(name, `"`))
+	}
+
+	switch {
+	case col == nil:
+
+	case isRangeColumn(col):
+		if r, _, ok := rangeValue(b.Args[b.posFilter-1]); ok {
+			b.Args[b.posFilter-1] = convertRange(r, col.Type())
+			param += "::" + col.Type()
+		} else if operator == "@>" && !isListArg(b.Args[b.posFilter-1]) {
+			// range contains element
+			param += "::" + rangeElemTypes[col.Type()]
+		} else {
+			param += "::" + col.Type()
+		}
+
+	case isArrayColumn(col):
+		param += arrayCast(col)
+
+	case operator == "<@":
+		// value of column is contained in range of argument
+		if rangeType, ok := rangeTypes[columnUdtName(col)]; ok {
+			if r, _, ok := rangeValue(b.Args[b.posFilter-1]); ok {
+				b.Args[b.posFilter-1] = convertRange(r, rangeType)
+			}
+			param += "::" + rangeType
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s", name, operator, param)
+}
+
+// columnCondition return condition for argument of column according to their types:
+// arrays - containment or ANY, ranges - equality or containment of element,
+// scalar - containment in range or ANY of list
+func (b *SQLBuilder) columnCondition(name string, col Column) (string, bool) {
+	param := fmt.Sprintf("$%d", b.posFilter)
+	arg := b.Args[b.posFilter-1]
+	r, argRangeType, isRange := rangeValue(arg)
+
+	switch {
+	case col == nil:
+		if isRange {
+			return fmt.Sprintf("%s<@%s::%s", name, param, argRangeType), true
+		}
+
+	case isArrayColumn(col):
+		if isListArg(arg) {
+			return name + "@>" + param + arrayCast(col), true
+		}
+
+		return param + "=ANY(" + name + ")", true
+
+	case isRangeColumn(col):
+		if isRange {
+			b.Args[b.posFilter-1] = convertRange(r, col.Type())
+			return fmt.Sprintf("%s=%s::%s", name, param, col.Type()), true
+		}
+
+		return fmt.Sprintf("%s@>%s::%s", name, param, rangeElemTypes[col.Type()]), true
+
+	case isRange:
+		rangeType, ok := rangeTypes[columnUdtName(col)]
+		if !ok {
+			rangeType = argRangeType
+			name += "::" + rangeElemTypes[rangeType]
+		}
+		b.Args[b.posFilter-1] = convertRange(r, rangeType)
+
+		return fmt.Sprintf("%s<@%s::%s", name, param, rangeType), true
+	}
+
+	if isListArg(arg) {
+		return name + "=ANY(" + param + arrayCast(col) + ")", true
+	}
+
+	return "", false
+}
+
+// anyAllCondition return condition of AnyOf & AllOf with cast of argument for user defined types
+func (b *SQLBuilder) anyAllCondition(term string) (string, bool) {
+	m := regAnyAllTerm.FindStringSubmatch(term)
+	if len(m) == 0 {
+		return "", false
+	}
+
+	var col Column
+	if b.Table != nil {
+		col = b.Table.FindColumn(m[1])
+	}
+
+	return fmt.Sprintf(term, fmt.Sprintf("$%d", b.posFilter)+arrayCast(col)), true
+}
+
+// checkAnyAllTerm return true if term is condition of AnyOf & AllOf with column of table
+func (b *SQLBuilder) checkAnyAllTerm(term string) (bool, error) {
+	m := regAnyAllTerm.FindStringSubmatch(term)
+	if len(m) == 0 || b.Table == nil {
+		return false, nil
+	}
+
+	if b.Table.FindColumn(m[1]) == nil {
+		return false, NewErrNotFoundColumn(b.Table.Name(), m[1])
+	}
+
+	return true, nil
+}
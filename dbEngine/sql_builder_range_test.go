@@ -0,0 +1,149 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// typedColumn is column with any type of PostgreSQL
+type typedColumn struct {
+	*StringColumn
+	typ     string
+	isArray bool
+	udt     *Types
+}
+
+func newTypedColumn(name, typ string, udt *Types) typedColumn {
+	return typedColumn{
+		StringColumn: NewStringColumn(name, "", false),
+		typ:          typ,
+		isArray:      typ[0] == '_',
+		udt:          udt,
+	}
+}
+
+func (c typedColumn) Type() string {
+	return c.typ
+}
+
+func (c typedColumn) IsArray() bool {
+	return c.isArray
+}
+
+func (c typedColumn) UserDefinedType() *Types {
+	return c.udt
+}
+
+func TestSQLBuilder_RangesAndArrays(t *testing.T) {
+	status := &Types{Name: "status", Enumerates: []string{"new", "done"}}
+	table := NewTableString("orders", "", []Column{
+		NewNumberColumn("id", "", true),
+		NewStringColumn("name", "", false),
+		NewNumericColumn("price", "", false, 0, 0),
+		NewTimeColumn("created", "", "timestamptz", false),
+		NewTimeColumn("day", "", "date", false),
+		newTypedColumn("status", "status", status),
+		newTypedColumn("tags", "_text", nil),
+		newTypedColumn("statuses", "_status", status),
+		newTypedColumn("period", "tstzrange", nil),
+		newTypedColumn("sizes", "int8range", nil),
+	}, nil, nil)
+
+	now := time.Now()
+	int8range := pgtype.Int8range{Lower: pgtype.Int8{Int: 1, Status: pgtype.Present},
+		Upper: pgtype.Int8{Int: 5, Status: pgtype.Present}, LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive,
+		Status: pgtype.Present}
+	int4range := pgtype.Int4range{Lower: pgtype.Int4{Int: 1, Status: pgtype.Present},
+		Upper: pgtype.Int4{Int: 5, Status: pgtype.Present}, LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive,
+		Status: pgtype.Present}
+	dateRange := &pgtype.Daterange{Lower: pgtype.Date{Time: now, Status: pgtype.Present},
+		Upper: pgtype.Date{Time: now.AddDate(0, 1, 0), Status: pgtype.Present}, LowerType: pgtype.Inclusive,
+		UpperType: pgtype.Exclusive, Status: pgtype.Present}
+	tsRange := pgtype.Tsrange{Lower: pgtype.Timestamp{Time: now, Status: pgtype.Present},
+		Upper: pgtype.Timestamp{Time: now.Add(time.Hour), Status: pgtype.Present}, LowerType: pgtype.Inclusive,
+		UpperType: pgtype.Exclusive, Status: pgtype.Present}
+
+	tests := []struct {
+		name    string
+		filter  string
+		arg     any
+		want    string
+		wantArg any
+	}{
+		{"list for scalar", "id", []int{1, 2}, "id=ANY($1)", nil},
+		{"list for enum", "status", []string{"new"}, "status=ANY($1::text[]::status[])", nil},
+		{"list for array", "tags", []string{"a"}, "tags@>$1", nil},
+		{"element for array", "tags", "a", "$1=ANY(tags)", nil},
+		{"list for array of enum", "statuses", []string{"new"}, "statuses@>$1::text[]::status[]", nil},
+		{"int8range for int4", "id", int8range, "id<@$1::int4range", pgtype.Int4range{}},
+		{"int4range for numeric", "price", &int4range, "price<@$1::numrange", pgtype.Numrange{}},
+		{"daterange for timestamptz", "created", dateRange, "created<@$1::tstzrange", pgtype.Tstzrange{}},
+		{"daterange for date", "day", dateRange, "day<@$1::daterange", pgtype.Daterange{}},
+		{"range for range", "period", tsRange, "period=$1::tstzrange", pgtype.Tstzrange{}},
+		{"element for range", "period", now, "period@>$1::timestamptz", nil},
+		{"overlap of ranges", "&&period", tsRange, "period && $1::tstzrange", pgtype.Tstzrange{}},
+		{"range contains element", "@>period", now, "period @> $1::timestamptz", nil},
+		{"range contained by range", "<@period", tsRange, "period <@ $1::tstzrange", pgtype.Tstzrange{}},
+		{"int8range contains int4range", "@>sizes", int4range, "sizes @> $1::int8range", pgtype.Int8range{}},
+		{"scalar contained by range", "<@id", int8range, "id <@ $1::int4range", pgtype.Int4range{}},
+		{"overlap of arrays", "&&tags", []string{"a", "b"}, "tags && $1", nil},
+		{"overlap of arrays of enum", "&&statuses", []string{"new"}, "statuses && $1::text[]::status[]", nil},
+		{"array contained by list", "<@tags", []string{"a", "b"}, "tags <@ $1", nil},
+		{"any of enum", AnyOf("status", "<>"), []string{"new"}, "status <> ANY($1::text[]::status[])", nil},
+		{"all of list", AllOf("id", ">"), []int{1, 2}, "id > ALL($1)", nil},
+		{"all of patterns", AllOf("name", "not like"), []string{"a%"}, "name not like ALL($1)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewSQLBuilder(table, ColumnsForSelect("id"), WhereForSelect(tt.filter), ArgsForSelect(tt.arg))
+			require.NoError(t, err)
+
+			sql, err := b.SelectSql()
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT id FROM orders WHERE "+tt.want, sql)
+			if tt.wantArg != nil {
+				assert.IsType(t, tt.wantArg, b.Args[0])
+			}
+		})
+	}
+
+	_, err := NewSQLBuilder(table, WhereForSelect(AllOf("unknown", "=")))
+	var errColumn *ErrNotFoundColumn
+	assert.ErrorAs(t, err, &errColumn)
+}
+
+func TestCondition_MatchArrays(t *testing.T) {
+	tags := []string{"a", "b", "c"}
+	tests := []struct {
+		cond Condition
+		want bool
+	}{
+		{Condition{Operator: "@>", Arg: []string{"a", "c"}}, true},
+		{Condition{Operator: "@>", Arg: []string{"a", "d"}}, false},
+		{Condition{Operator: "<@", Arg: []string{"a", "b", "c", "d"}}, true},
+		{Condition{Operator: "<@", Arg: []string{"a"}}, false},
+		{Condition{Operator: "&&", Arg: []string{"d", "c"}}, true},
+		{Condition{Operator: "&&", Arg: []string{"d"}}, false},
+		{Condition{Operator: "=", Arg: "b"}, true},
+		{Condition{Operator: "=", Arg: []string{"a", "c"}}, true},
+		{Condition{Operator: "=", Arg: []string{"a", "d"}}, false},
+		{Condition{Operator: OpAny, Arg: []string{"b", "d"}}, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.cond.Match(tags)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s %v", tt.cond.Operator, tt.cond.Arg)
+	}
+
+	_, err := Condition{Operator: "&&", Arg: pgtype.Int4range{}}.Match(tags)
+	var errCond *ErrUnsupportedCondition
+	assert.ErrorAs(t, err, &errCond)
+}
@@ -1096,3 +1096,108 @@ func TestSQLBuilder_UpsertSql(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeout(t *testing.T) {
+	b, err := NewSQLBuilder(nil, Timeout(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, b.Timeout)
+
+	_, err = NewSQLBuilder(nil, Timeout(-time.Second))
+	assert.Error(t, err)
+}
+
+func TestSQLBuilder_SkipGeneratedColumns(t *testing.T) {
+	table := NewTableString("orders", "", []Column{
+		&identityColumn{NumberColumn: NewNumberColumn("id", "", true), identity: IdentityAlways},
+		&identityColumn{NumberColumn: NewNumberColumn("num", "", true), identity: IdentityByDefault},
+		NewNumberColumn("price", "", true),
+		&identityColumn{NumberColumn: NewNumberColumn("total", "", false), expr: "price * 2"},
+	}, nil, nil)
+
+	columns, args := []string{"id", "num", "price", "total"}, []any{1, 2, 3, 6}
+	b, err := NewSQLBuilder(table, Columns(columns...), Values(args...))
+	require.NoError(t, err)
+
+	b.SkipGeneratedColumns()
+	sql, err := b.InsertSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO orders(num,price) VALUES ($1,$2) ", sql)
+	assert.Equal(t, []any{2, 3}, b.Args)
+	// arguments of caller aren't changed
+	assert.Equal(t, []any{1, 2, 3, 6}, args)
+
+	b, err = NewSQLBuilder(table, Columns("price", "total"), WhereForSelect("id"), Args(3, 6, 1))
+	require.NoError(t, err)
+
+	b.SkipGeneratedColumns()
+	sql, err = b.UpdateSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET  price=$1 WHERE id=$2", sql)
+	assert.Equal(t, []any{3, 1}, b.Args)
+
+	b, err = NewSQLBuilder(table)
+	require.NoError(t, err)
+	assert.Equal(t, "id,num,price,total", b.Select())
+
+	b, err = NewSQLBuilder(table, InsertOnConflict("num"))
+	require.NoError(t, err)
+	sql, err = b.SetUpsert()
+	require.NoError(t, err)
+	assert.Equal(t, " SET price=EXCLUDED.price", sql)
+}
+
+func TestSQLBuilder_UpsertCompositeKeys(t *testing.T) {
+	table := NewTableString("prices", "", []Column{
+		&StringColumn{name: "shop", primary: true},
+		&StringColumn{name: "sku", primary: true},
+		NewStringColumn("code", "", false),
+		NewStringColumn("price", "", false),
+	}, Indexes{
+		{Name: "prices_code_uindex", Unique: true, Columns: []string{"code"}},
+	}, nil)
+
+	assert.Equal(t, []string{"shop", "sku"}, KeyColumns(table))
+	assert.Len(t, table.UniqueConstraints().Filter(ConstraintUnique), 1)
+
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+		wantErr bool
+	}{
+		{
+			"primary key",
+			[]string{"shop", "sku", "price"},
+			"INSERT INTO prices(shop,sku,price) VALUES ($1,$2,$3) ON CONFLICT (shop,sku) DO UPDATE SET price=EXCLUDED.price",
+			false,
+		},
+		{
+			"unique constraint if part of primary key isn't written",
+			[]string{"shop", "code", "price"},
+			"INSERT INTO prices(shop,code,price) VALUES ($1,$2,$3) ON CONFLICT (code) DO UPDATE SET shop=EXCLUDED.shop, price=EXCLUDED.price",
+			false,
+		},
+		{
+			"unknown column",
+			[]string{"shop", "sku", "amount"},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]any, len(tt.columns))
+			b, err := NewSQLBuilder(table, Columns(tt.columns...), Values(args...))
+			require.NoError(t, err)
+
+			got, err := b.UpsertSql()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
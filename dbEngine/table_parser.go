@@ -13,46 +13,143 @@ import (
 )
 
 func (p *ParserCfgDDL) updateTable(ddl string) bool {
-	fields := regTable.FindStringSubmatch(ddl)
-	if len(fields) == 0 {
+	s, ok := p.statement(ddl).(*CreateTableStmt)
+	if !ok || s.PartitionOf > "" || len(s.Columns) == 0 {
 		return false
 	}
 
-	for i, name := range regTable.SubexpNames() {
-		if !(i < len(fields)) {
-			return false
+	if s.Name != p.Name() {
+		p.err = errors.Errorf("bad table name '%s'", s.Name)
+		return false
+	}
+
+	newNotNulls, sqlDefaults := p.chkColumns(s)
+
+	if p.updDLL != nil {
+		if len(newNotNulls) > 0 {
+			p.writeNotNullColumns(newNotNulls)
 		}
 
-		switch name {
-		case "":
-		case "name":
-			if fields[i] != p.Name() {
-				p.err = errors.Errorf("bad table name '%s'", fields[i])
-				return false
-			}
-			p.line++
-		case "builderOpts":
+		p.writeColumns(p.updDLL.String() + sqlDefaults.String())
 
-			newNotNulls, sqlDefaults := p.chkColumns(fields[i])
+		p.updDLL = nil
+		p.err = nil
 
-			if p.updDLL != nil {
-				if len(newNotNulls) > 0 {
-					p.writeNotNullColumns(newNotNulls)
-				}
+		if err := p.Table.GetColumns(p.DB.ctx, p.DB.Types); err != nil {
+			logs.ErrorLog(err, "during reread table columns")
+		}
+	}
+
+	p.updateConstraints(ddl)
+
+	return true
+}
+
+// updateConstraints adds new & replaces changed constraints declared in statement 'create table',
+// constraints of table absent in DDL will be dropped at the end of parsing if setting allows it
+func (p *ParserCfgDDL) updateConstraints(ddl string) {
+	if _, ok := p.statement(ddl).(*CreateTableStmt); !ok {
+		return
+	}
 
-				p.writeColumns(p.updDLL.String() + sqlDefaults.String())
+	alters, absent := p.constraintsDiff(ddl)
+	for _, sql := range alters {
+		p.runDDL(sql)
+	}
 
-				p.updDLL = nil
-				p.err = nil
+	p.absentConstraints = append(p.absentConstraints, absent...)
+}
 
-				if err := p.Table.GetColumns(p.DB.ctx, p.DB.Types); err != nil {
-					logs.ErrorLog(err, "during reread table columns")
-				}
-			}
+// constraintsDiff return statements for adding new & replacing changed constraints declared in DDL
+// and names of constraints of table which DDL doesn't declare
+func (p *ParserCfgDDL) constraintsDiff(ddl string) (alters []string, absent []string) {
+	declared, columns := constraintsDDL(ddl)
+	current := p.Constraints()
+	for _, c := range append(slices.Clone(declared), columns...) {
+		p.normalizeCheck(c, current)
+	}
+
+	matched := make([]*Constraint, 0, len(current))
+	for _, c := range declared {
+		i := slices.IndexFunc(current, func(old *Constraint) bool {
+			return matchConstraint(old, c)
+		})
+		if i < 0 {
+			alters = append(alters, fmt.Sprintf(tplAddConstraint, p.Name(), c.DDL()))
+			continue
+		}
+
+		old := current[i]
+		matched = append(matched, old)
+		if normalizeIndexExpr(old.Definition) != normalizeIndexExpr(c.Definition) {
+			logInfo(preDB_CONFIG, p.filename,
+				fmt.Sprintf("constraint '%s' has new definition '%s' (old ='%s')", old.Name, c.Definition, old.Definition),
+				p.line)
+			alters = append(alters, fmt.Sprintf(tplReplaceConstraint, p.Name(), old.Name, c.DDL()))
 		}
 	}
 
-	return true
+	for _, old := range current {
+		// constraints of columns are declared with columns
+		if slices.Contains(matched, old) || slices.ContainsFunc(columns, func(c *Constraint) bool {
+			return matchConstraint(old, c)
+		}) {
+			continue
+		}
+
+		absent = append(absent, old.Name)
+	}
+
+	return
+}
+
+// normalizeCheck replaces definition of check constraint c with definition which DB stores for it,
+// if no constraint of table has same text, so rewritten expressions (IN, BETWEEN etc.) match
+func (p *ParserCfgDDL) normalizeCheck(c *Constraint, current Constraints) {
+	if c.Type != ConstraintCheck || c.Definition == "" {
+		return
+	}
+
+	t, ok := p.Table.(CheckDefinition)
+	if !ok || slices.ContainsFunc(current, func(old *Constraint) bool {
+		return normalizeIndexExpr(old.Definition) == normalizeIndexExpr(c.Definition)
+	}) {
+		return
+	}
+
+	def, err := t.CheckDefinition(p.DB.ctx, c.Definition)
+	if err != nil {
+		logError(err, c.Definition, p.filename)
+		return
+	}
+
+	c.Definition = def
+}
+
+// dropAbsentConstraints drops constraints of table which DDL doesn't declare if setting DROP_ABSENT_CONSTRAINTS is on,
+// keys referenced by foreign keys of other tables aren't dropped
+func (p *ParserCfgDDL) dropAbsentConstraints() {
+	drop := p.cfgOn(DROP_ABSENT_CONSTRAINTS)
+	for _, name := range p.absentConstraints {
+		if !drop {
+			logWarning(preDB_CONFIG, p.filename,
+				fmt.Sprintf("constraint '%s' isn't present in DDL, it's kept (setting DropAbsentConstraints is off)", name),
+				p.line)
+			continue
+		}
+
+		if fk, table := p.referencingKey(name); fk != nil {
+			logWarning(preDB_CONFIG, p.filename,
+				fmt.Sprintf("constraint '%s' isn't present in DDL, but foreign key '%s' of '%s' references it", name, fk.Name, table),
+				p.line)
+			continue
+		}
+
+		logInfo(preDB_CONFIG, p.filename, fmt.Sprintf("constraint '%s' isn't present in DDL", name), p.line)
+		p.runDDL(fmt.Sprintf(tplDropConstraint, p.Name(), name))
+	}
+
+	p.absentConstraints = nil
 }
 
 func (p *ParserCfgDDL) writeColumns(sql string) {
@@ -93,33 +190,18 @@ func (p *ParserCfgDDL) writeNotNullColumns(newNotNulls []string) {
 	}
 }
 
-func (p *ParserCfgDDL) chkColumns(columns string) (newNotNulls []string, sqlDefaults *strings.Builder) {
-	nameFields := strings.Split(columns, ",\n")
+func (p *ParserCfgDDL) chkColumns(s *CreateTableStmt) (newNotNulls []string, sqlDefaults *strings.Builder) {
 	newNotNulls = make([]string, 0)
 	sqlDefaults = &strings.Builder{}
 
-	for i, name := range nameFields {
-		p.line++
-		title := regField.FindStringSubmatch(name)
-		if len(title) < 3 ||
-			strings.HasPrefix(strings.ToLower(title[1]), "unique") ||
-			strings.HasPrefix(strings.ToLower(title[1]), "primary") ||
-			strings.HasPrefix(strings.ToLower(title[1]), "constraint") {
-			continue
-		}
-
-		sAlter, colName, colDefine := title[0], title[1], title[2]
-		defaults := ""
-		if newDef := RegDefault.FindStringSubmatch(colDefine); len(newDef) > 0 {
-			defaults = newDef[1]
-		}
-		//if strings.IndexRune(colName, ' ') > 0 && !(strings.HasPrefix(colName, `"`) && strings.HasSuffix(colName, `"`)) {
-		//	colName = `"` + colName + `"`
-		//}
-		if strings.HasPrefix(colName, `"`) {
-			colName = strings.Trim(colName, `"`)
-		} else {
-			colName = strings.ToLower(colName)
+	for i, column := range s.Columns {
+		sAlter, pos := s.ColumnDDL(i)
+		p.line = pos.Line
+		colName, colDefine := column.Name, column.Define
+		// 'generated by default as identity' isn't default value
+		defaults := column.Default
+		if identity, _ := DefineIdentity(colDefine); identity > "" {
+			defaults = ""
 		}
 
 		if col := p.FindColumn(colName); col == nil {
@@ -127,13 +209,13 @@ func (p *ParserCfgDDL) chkColumns(columns string) (newNotNulls []string, sqlDefa
 				if defaults > "" {
 					newNotNulls = append(newNotNulls, colName)
 				} else {
-					logWarning("COLUMN", p.filename, colName+" has't default will be add without flag SET NULL", i+1)
+					logWarning("COLUMN", p.filename, colName+" has't default will be add without flag SET NULL", p.line)
 				}
 			}
 			p.addColumn(sAlter)
 
 		} else if flags := col.CheckAttr(colDefine); col.Primary() {
-			p.checkPrimary(col, colDefine, flags)
+			p.checkPrimary(col, colDefine, flags, sqlDefaults)
 		} else {
 			p.checkColumn(col, colDefine, flags, defaults, sqlDefaults)
 		}
@@ -159,17 +241,36 @@ func (p *ParserCfgDDL) addColumn(sAlter string) {
 	p.updDLL.WriteString(" ADD COLUMN " + sAlter)
 }
 
-func (p *ParserCfgDDL) checkPrimary(col Column, colDefine string, flags []FlagColumn) {
+func (p *ParserCfgDDL) checkPrimary(col Column, colDefine string, flags []FlagColumn, sqlDefaults *strings.Builder) {
 	for _, flag := range flags {
-		// change only type
-		if flag == ChgType {
+		switch flag {
+		// change only type & identity
+		case ChgType:
 			p.chkAlterBuilder()
-			_, _ = fmt.Fprintf(p.updDLL, tplAlterColumnType, col.Name(), colDefine)
-			return
+			_, define := DefineIdentity(colDefine)
+			_, _ = fmt.Fprintf(p.updDLL, tplAlterColumnType, col.Name(), strings.TrimSpace(define))
+		case ChgIdentity:
+			p.chkAlterBuilder()
+			p.alterIdentity(col, col.Name(), colDefine, sqlDefaults)
 		}
 	}
 }
 
+// alterIdentity writes changes of identity column according to colDefine
+func (p *ParserCfgDDL) alterIdentity(col Column, colName, colDefine string, sqlDefaults *strings.Builder) {
+	identity, _ := DefineIdentity(colDefine)
+	switch {
+	case identity == "":
+		_, _ = fmt.Fprintf(p.updDLL, tplAlterDropIdentity, colName)
+	case ColumnIdentity(col) == "":
+		_, _ = fmt.Fprintf(p.updDLL, tplAlterAddIdentity, colName, identity)
+		// sequence of identity must continue values of column
+		_, _ = fmt.Fprintf(sqlDefaults, tplRestartIdentity, p.Name(), colName)
+	default:
+		_, _ = fmt.Fprintf(p.updDLL, tplAlterSetIdentity, colName, identity)
+	}
+}
+
 func (p *ParserCfgDDL) checkColumn(col Column, colDefine string, flags []FlagColumn, defaults string, sqlDefaults *strings.Builder) {
 
 	if len(flags) == 0 {
@@ -225,6 +326,10 @@ WHERE %[2]s is null;
 ALTER TABLE %[1]s `+tplAlterNotNull, p.Name(), colName)
 				continue
 			}
+		// add, change or drop identity
+		case ChgIdentity:
+			p.alterIdentity(col, colName, colDefine, sqlDefaults)
+
 		// set nullable
 		case Nullable:
 			_, _ = fmt.Fprintf(p.updDLL, "ALTER COLUMN %s DROP not null", colName)
@@ -238,6 +343,19 @@ ALTER TABLE %[1]s `+tplAlterNotNull, p.Name(), colName)
 	}
 }
 
+// DefineIdentity return kind of identity ('ALWAYS' or 'BY DEFAULT') from definition of column
+// & definition without clause 'GENERATED ... AS IDENTITY'
+func DefineIdentity(colDefine string) (string, string) {
+	m := regIdentity.FindStringSubmatchIndex(colDefine)
+	if len(m) == 0 {
+		return "", colDefine
+	}
+
+	identity := strings.ToUpper(strings.Join(strings.Fields(colDefine[m[2]:m[3]]), " "))
+
+	return identity, colDefine[:m[0]] + colDefine[m[1]:]
+}
+
 func getNewTypeDef(col Column, colDefine string) string {
 	attr := strings.Split(colDefine, " ")
 	typeDef := attr[0]
@@ -266,37 +384,37 @@ func getNewTypeDef(col Column, colDefine string) string {
 }
 
 func (p *ParserCfgDDL) addComment(ddl string) bool {
-	if res := regCommentTable.FindAllStringSubmatch(ddl, -1); len(res) > 0 {
-		tokens := res[0]
-		if tokens[1] != p.Table.Name() {
+	s, ok := p.statement(ddl).(*CommentStmt)
+	if !ok {
+		return false
+	}
+
+	switch s.Object {
+	case "table", "view", "materialized view":
+		if s.Name != p.Table.Name() {
 			logs.StatusLog(ddl)
-			logError(errors.Errorf(errWrongTableName.Error(), tokens[1], "comment table"), ddl, p.filename)
+			logError(errors.Errorf(errWrongTableName.Error(), s.Name, "comment table"), ddl, p.filename)
 			return true
 		}
-		if tokens[2] == p.Table.Comment() {
+		if s.Comment == p.Table.Comment() {
 			return true
 		}
 
 		p.runDDL(ddl)
 		return true
 
-	} else if res := regCommentColumn.FindAllStringSubmatch(ddl, -1); len(res) > 0 {
-		tokens := res[0]
-		if tokens[1] != p.Table.Name() {
-			logError(errors.Errorf(errWrongTableName.Error(), tokens[1], "comment column"), ddl, p.filename)
+	case "column":
+		if s.Table != p.Table.Name() {
+			logError(errors.Errorf(errWrongTableName.Error(), s.Table, "comment column"), ddl, p.filename)
 			return true
 		}
-		colName := strings.ToLower(tokens[2])
-		if strings.HasPrefix(colName, `"`) {
-			colName = strings.Trim(tokens[2], `"`)
-		}
-		col := p.FindColumn(colName)
+		col := p.FindColumn(s.Column)
 		if col == nil {
-			logError(&ErrUnknownSql{Line: p.line, Msg: "not found column " + colName}, ddl, p.filename)
+			logError(&ErrUnknownSql{Line: p.line, Msg: "not found column " + s.Column}, ddl, p.filename)
 			return true
 		}
 
-		if col.Comment() != strings.ReplaceAll(tokens[3], "''", "'") {
+		if col.Comment() != s.Comment {
 			p.runDDL(ddl)
 		}
 		return true
@@ -306,12 +424,12 @@ func (p *ParserCfgDDL) addComment(ddl string) bool {
 }
 
 func (p *ParserCfgDDL) skipPartition(ddl string) bool {
-	fields := regPartitionTable.FindStringSubmatch(strings.ToLower(ddl))
-	if len(fields) == 0 {
+	s, ok := p.statement(ddl).(*CreateTableStmt)
+	if !ok || s.PartitionOf == "" {
 		return false
 	}
 
-	_, ok := p.DB.Tables[fields[1]]
+	_, ok = p.DB.Tables[s.Name]
 	if !ok {
 		p.runDDL(ddl)
 	}
@@ -355,7 +473,7 @@ func (p *ParserCfgDDL) updateIndex(ddl string) bool {
 		}
 
 		if oldInd.Expr != ind.Expr {
-			if strings.Replace(oldInd.Expr, ")", "", -1) == strings.Replace(ind.Expr, ")", "", -1) {
+			if normalizeIndexExpr(oldInd.Expr) == normalizeIndexExpr(ind.Expr) {
 				logWarning(alreadyExists, p.filename,
 					fmt.Sprintf("index '%s' expr has diff: '%s' <-> '%s' but this is some index expression", ind.Name, ind.Expr, oldInd.Expr),
 					p.line)
@@ -374,6 +492,13 @@ func (p *ParserCfgDDL) updateIndex(ddl string) bool {
 				p.line)
 		}
 
+		if indexMethod(oldInd) != indexMethod(ind) {
+			logInfo(preDB_CONFIG, p.filename,
+				fmt.Sprintf("index '%s' has new method '%s' (old ='%s')", ind.Name, indexMethod(ind), indexMethod(oldInd)),
+				p.line)
+			hasChanges = true
+		}
+
 		if oldInd.Unique != ind.Unique {
 			logInfo(preDB_CONFIG, p.filename,
 				fmt.Sprintf("New unique condition '%v' exists! Old  '%v'", ind.Unique, oldInd.Unique),
@@ -384,8 +509,8 @@ func (p *ParserCfgDDL) updateIndex(ddl string) bool {
 
 		if hasChanges {
 			logs.StatusLog(ind)
-			if ind.foreignColumn > "" {
-				p.runDDL("DROP CONSTRAINT " + oldInd.Name)
+			if ind.foreignTable > "" {
+				p.runDDL(fmt.Sprintf(tplDropConstraint, p.Name(), oldInd.Name))
 			} else {
 				p.runDDL("DROP INDEX " + oldInd.Name)
 			}
@@ -396,6 +521,30 @@ func (p *ParserCfgDDL) updateIndex(ddl string) bool {
 	return true
 }
 
+// normalizeIndexExpr return expression of index without casts, parentheses & spaces,
+// so expression from DDL may be compared with result of pg_get_expr, which adds casts as '::regconfig'
+func normalizeIndexExpr(expr string) string {
+	expr = regExprCast.ReplaceAllString(strings.ToLower(expr), "")
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\n', '(', ')':
+			return -1
+		default:
+			return r
+		}
+	}, expr)
+}
+
+// indexMethod return access method of index, btree is default of PostgreSQL
+func indexMethod(ind *Index) string {
+	if ind.Method == "" {
+		return "btree"
+	}
+
+	return ind.Method
+}
+
 func (p *ParserCfgDDL) alterColumn(colName string, sAlter ...string) error {
 	ddl := fmt.Sprintf(`ALTER TABLE %s %s`, p.Name(), strings.Join(sAlter, ","))
 
@@ -408,9 +557,8 @@ func (p *ParserCfgDDL) alterColumn(colName string, sAlter ...string) error {
 		logs.ErrorLog(p.err, `Field %s.%s, different with define: '%s' %v`, p.Name(), ddl)
 
 	case IsErrorNullValues(p.err):
-		defaults := RegDefault.FindStringSubmatch(strings.ToLower(ddl))
-		if len(defaults) > 1 && defaults[1] > "" {
-			p.runDDL(fmt.Sprintf(`UPDATE %s SET %s=$1`, p.Name(), colName), defaults[1])
+		if defaults := DefaultDDL(strings.ToLower(ddl)); defaults > "" {
+			p.runDDL(fmt.Sprintf(`UPDATE %s SET %s=$1`, p.Name(), colName), defaults)
 		}
 
 	default:
@@ -420,10 +568,20 @@ func (p *ParserCfgDDL) alterColumn(colName string, sAlter ...string) error {
 }
 
 func (p *ParserCfgDDL) alterTable(ddl string) bool {
-	if !strings.HasPrefix(strings.ToLower(ddl), "alter table") {
+	s, ok := p.statement(ddl).(*AlterStmt)
+	if !ok || s.Object != "table" {
 		return false
 	}
 
+	// constraint which is added by statement isn't absent in DDL
+	for _, action := range s.Actions {
+		if action.Constraint != nil {
+			p.absentConstraints = slices.DeleteFunc(p.absentConstraints, func(absent string) bool {
+				return absent == action.Constraint.Name
+			})
+		}
+	}
+
 	p.runDDL(ddl)
 
 	return true
@@ -435,3 +593,153 @@ func isDigTYpe(def string) bool {
 		strings.HasPrefix(strings.ToLower(def), "int") ||
 		strings.HasPrefix(strings.ToLower(def), "numeric")
 }
+
+// ColumnDDL consists of properties of column which declared in 'create table' statement
+type ColumnDDL struct {
+	Name    string
+	Type    string
+	Define  string
+	Default string
+	NotNull bool
+	Primary bool
+	Unique  bool
+}
+
+// keywords which finish type of column in DDL
+var ddlTypeEnd = []string{"not", "null", "default", "primary", "unique", "references", "check", "constraint", "collate", "generated"}
+
+// ParseTableDDL parses first statement 'create table' of ddl,
+// return name of table & its columns, constraint 'primary key (...)' marks columns as Primary
+func ParseTableDDL(ddl string) (string, []ColumnDDL, error) {
+	list, err := ParseDDL(ddl)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(list) == 0 {
+		return "", nil, NewErrUnknownSql(ddl, 0)
+	}
+
+	s, ok := list[0].(*CreateTableStmt)
+	if !ok {
+		return "", nil, NewErrUnknownSql(ddl, list[0].Source().Pos.Line)
+	}
+
+	primary := make([]string, 0)
+	for _, c := range s.Constraints {
+		if c.Type == ConstraintPrimary {
+			primary = append(primary, c.Columns...)
+		}
+	}
+
+	columns := slices.Clone(s.Columns)
+	for i, col := range columns {
+		if slices.Contains(primary, col.Name) {
+			columns[i].Primary = true
+		}
+		if columns[i].Primary {
+			columns[i].NotNull = true
+		}
+	}
+
+	return s.Name, columns, nil
+}
+
+// DefaultDDL return expression of clause 'DEFAULT' from define of column in DDL or empty string
+func DefaultDDL(define string) string {
+	list, err := ParseDDL("create table t (c " + define + ")")
+	if err != nil || len(list) == 0 {
+		return ""
+	}
+
+	if s, ok := list[0].(*CreateTableStmt); ok && len(s.Columns) > 0 {
+		return s.Columns[0].Default
+	}
+
+	return ""
+}
+
+// defineType return type from define of column in DDL
+func defineType(define string) string {
+	words := strings.Fields(strings.ToLower(define))
+	end := slices.IndexFunc(words, func(word string) bool {
+		return slices.Contains(ddlTypeEnd, word)
+	})
+	if end < 0 {
+		end = len(words)
+	}
+
+	return strings.Join(words[:end], " ")
+}
+
+// ParseIndexDDL parses statement 'create index' of table, return nil if ddl isn't such statement
+func ParseIndexDDL(table Table, ddl string) (*Index, error) {
+	p := &ParserCfgDDL{Table: table, filename: table.Name() + ".ddl"}
+
+	return p.checkDDLCreateIndex(ddl)
+}
+
+// referencingKey return foreign key of other table & its name, which references key constraint 'name' of table
+func (p *ParserCfgDDL) referencingKey(name string) (*ForeignKey, string) {
+	i := slices.IndexFunc(p.Constraints(), func(c *Constraint) bool {
+		return c.Name == name
+	})
+	if p.DB == nil || i < 0 {
+		return nil, ""
+	}
+
+	c := p.Constraints()[i]
+	if c.Type != ConstraintPrimary && c.Type != ConstraintUnique {
+		return nil, ""
+	}
+
+	columns := slices.Sorted(slices.Values(c.Columns))
+	for _, tableName := range p.DB.ReferencingTables(p.Name()) {
+		for _, fk := range p.DB.Tables[tableName].ForeignKeys() {
+			if fk.Parent == p.Name() && slices.Equal(slices.Sorted(slices.Values(fk.ParentColumns)), columns) {
+				return fk, tableName
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// constraintsDDL return constraints declared in statement 'create table':
+// constraints of table with definitions & constraints of columns (primary key, unique, check) without ones
+func constraintsDDL(ddl string) (declared Constraints, columns Constraints) {
+	if list, _ := ParseDDL(ddl); len(list) > 0 {
+		if s, ok := list[0].(*CreateTableStmt); ok {
+			return s.Constraints, s.ColumnConstraints
+		}
+	}
+
+	return make(Constraints, 0), make(Constraints, 0)
+}
+
+// matchConstraint reports whether constraint of table old is constraint c of DDL:
+// named constraints match by name, constraints of columns match by type & columns,
+// other unnamed constraints match by type & columns or definition
+func matchConstraint(old, c *Constraint) bool {
+	switch {
+	case c.Name > "":
+		return old.Name == c.Name
+	case old.Type != c.Type:
+		return false
+	case c.Definition == "", c.Type == ConstraintUnique:
+		return slices.Equal(old.Columns, c.Columns)
+	case c.Type == ConstraintPrimary:
+		return true
+	default:
+		return normalizeIndexExpr(old.Definition) == normalizeIndexExpr(c.Definition)
+	}
+}
+
+// columnNameDDL return name of column as DB stores it
+func columnNameDDL(name string) string {
+	if strings.HasPrefix(name, `"`) {
+		return strings.Trim(name, `"`)
+	}
+
+	return strings.ToLower(name)
+}
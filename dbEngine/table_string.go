@@ -115,6 +115,36 @@ func (t TableString) Name() string {
 	return t.name
 }
 
+// PrimaryKey return columns of primary key of table
+func (t TableString) PrimaryKey() []Column {
+	return PrimaryColumns(t)
+}
+
+// UniqueConstraints return unique constraints of table
+func (t TableString) UniqueConstraints() Constraints {
+	return UniqueIndexes(t)
+}
+
+// Constraints return primary key & unique constraints of table
+func (t TableString) Constraints() Constraints {
+	return KeyConstraints(t)
+}
+
+// ForeignKeys return foreign keys of table
+func (t TableString) ForeignKeys() ForeignKeys {
+	return ColumnsForeignKeys(t)
+}
+
+// Triggers return nil, table doesn't support triggers
+func (t TableString) Triggers() Triggers {
+	return nil
+}
+
+// Validate check values of columns according to their metadata before writing
+func (t TableString) Validate(columns []string, values []any) error {
+	return ValidateValues(t, columns, values)
+}
+
 // ReReadColumn renew properties of column 'name'
 func (t TableString) ReReadColumn(ctx context.Context, name string) Column {
 	panic("implement me")
@@ -0,0 +1,100 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// updateTrigger creates trigger of statement 'create trigger' or recreates it if its definition is changed
+func (p *ParserCfgDDL) updateTrigger(ddl string) bool {
+	s, ok := p.statement(ddl).(*CreateTriggerStmt)
+	if !ok {
+		return false
+	}
+
+	if s.Table != p.Name() {
+		p.err = errors.Errorf(errWrongTableName.Error(), s.Table, "trigger")
+		return false
+	}
+
+	p.declaredTriggers = append(p.declaredTriggers, s.Name)
+
+	old := p.Triggers().Find(s.Name)
+	switch {
+	case old == nil:
+		p.runDDL(ddl)
+
+	case triggerChanged(old, s):
+		logInfo(preDB_CONFIG, p.filename,
+			fmt.Sprintf("trigger '%s' has new definition '%s' (old ='%s')", s.Name, ddl, old.Definition), p.line)
+		// statements run in one transaction, so DB keeps old trigger if new DDL is wrong
+		p.runDDL(fmt.Sprintf(tplDropTrigger, s.Name, p.Name()) + ";\n" + ddl)
+	}
+
+	return true
+}
+
+// triggerChanged compares definition of trigger from DB (pg_get_triggerdef) with statement of DDL
+func triggerChanged(old *Trigger, s *CreateTriggerStmt) bool {
+	list, err := ParseDDL(old.Definition)
+	if err != nil || len(list) != 1 {
+		return true
+	}
+
+	stored, ok := list[0].(*CreateTriggerStmt)
+	if !ok {
+		return true
+	}
+
+	sorted := func(list []string) []string {
+		res := slices.Clone(list)
+		slices.Sort(res)
+
+		return res
+	}
+	// pg_get_triggerdef quotes every argument of function
+	args := func(list []string) []string {
+		res := make([]string, len(list))
+		for i, arg := range list {
+			res[i] = strings.Trim(arg, "'")
+		}
+
+		return res
+	}
+
+	return stored.Constraint != s.Constraint ||
+		stored.Timing != s.Timing ||
+		!slices.Equal(sorted(stored.Events), sorted(s.Events)) ||
+		!slices.Equal(sorted(stored.Columns), sorted(s.Columns)) ||
+		stored.ForEachRow != s.ForEachRow ||
+		normalizeIndexExpr(stored.When) != normalizeIndexExpr(s.When) ||
+		stored.Function != s.Function ||
+		!slices.Equal(args(stored.Args), args(s.Args))
+}
+
+// dropAbsentTriggers drops triggers of table which DDL doesn't declare if setting DROP_ABSENT_TRIGGERS is on
+func (p *ParserCfgDDL) dropAbsentTriggers() {
+	drop := p.cfgOn(DROP_ABSENT_TRIGGERS)
+	for _, trigger := range p.Triggers() {
+		if slices.Contains(p.declaredTriggers, trigger.Name) {
+			continue
+		}
+
+		if !drop {
+			logWarning(preDB_CONFIG, p.filename,
+				fmt.Sprintf("trigger '%s' isn't present in DDL, it's kept (setting DropAbsentTriggers is off)", trigger.Name),
+				p.line)
+			continue
+		}
+
+		logInfo(preDB_CONFIG, p.filename, fmt.Sprintf("trigger '%s' isn't present in DDL", trigger.Name), p.line)
+		p.runDDL(fmt.Sprintf(tplDropTrigger, trigger.Name, p.Name()))
+	}
+}
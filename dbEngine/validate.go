@@ -0,0 +1,289 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgtype"
+	"github.com/pkg/errors"
+
+	"github.com/ruslanBik4/gotools/typesExt"
+)
+
+// errors of values validation
+var (
+	ErrValueRequired  = errors.New("value is required")
+	ErrValueTooLong   = errors.New("value is too long")
+	ErrValueWrongType = errors.New("value has wrong type")
+	ErrValueNotEnum   = errors.New("value isn't member of enum")
+	ErrValueNotArray  = errors.New("value must be array")
+	ErrValueIsArray   = errors.New("value mustn't be array")
+)
+
+// ErrValidation consists of errors of validation by names of columns
+type ErrValidation map[string]error
+
+// Error implement error interface
+func (err ErrValidation) Error() string {
+	names := make([]string, 0, len(err))
+	for name := range err {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	list := make([]string, len(names))
+	for i, name := range names {
+		list[i] = fmt.Sprintf("%s: %v", name, err[name])
+	}
+
+	return "validation failed: " + strings.Join(list, "; ")
+}
+
+// Unwrap return errors of all columns
+func (err ErrValidation) Unwrap() []error {
+	list := make([]error, 0, len(err))
+	for _, e := range err {
+		list = append(list, e)
+	}
+
+	return list
+}
+
+// IsErrorValidation return errors of columns if err is result of validation
+func IsErrorValidation(err error) (ErrValidation, bool) {
+	var errValidation ErrValidation
+	if errors.As(err, &errValidation) {
+		return errValidation, true
+	}
+
+	return nil, false
+}
+
+// ValidateValues check values of columns of table according to metadata of columns:
+// required, max length, type, members of enum & arrays
+// return ErrValidation or nil
+func ValidateValues(table Table, columns []string, values []any) error {
+	if len(columns) != len(values) {
+		return NewErrWrongArgsLen(table.Name(), columns, values)
+	}
+
+	errValidation := make(ErrValidation)
+	for i, name := range columns {
+		col := table.FindColumn(name)
+		if col == nil {
+			errValidation[name] = NewErrNotFoundColumn(table.Name(), name)
+			continue
+		}
+
+		if err := ValidateValue(col, values[i]); err != nil {
+			errValidation[name] = err
+		}
+	}
+
+	if len(errValidation) > 0 {
+		return errValidation
+	}
+
+	return nil
+}
+
+// ValidateArgs check values of written columns if option ValidateBeforeWrite was set,
+// isInsert means that required columns which are absent in query are wrong too
+func (b *SQLBuilder) ValidateArgs(isInsert bool) error {
+	if !b.validate || b.Table == nil {
+		return nil
+	}
+
+	names := b.ColumnNames()
+	if len(b.Args) < len(names) {
+		return NewErrWrongArgsLen(b.Table.Name(), names, b.Args)
+	}
+
+	err := ValidateValues(b.Table, names, b.Args[:len(names)])
+	if !isInsert {
+		return err
+	}
+
+	errValidation, ok := IsErrorValidation(err)
+	if err != nil && !ok {
+		return err
+	}
+
+	for _, col := range b.Table.Columns() {
+		if col.Required() && !col.AutoIncrement() && !slices.Contains(names, col.Name()) {
+			if errValidation == nil {
+				errValidation = make(ErrValidation)
+			}
+			errValidation[col.Name()] = ErrValueRequired
+		}
+	}
+
+	if len(errValidation) > 0 {
+		return errValidation
+	}
+
+	return nil
+}
+
+// ValidateValue check value according to metadata of column
+func ValidateValue(col Column, value any) error {
+	if isNilValue(value) {
+		if col.Required() && !col.AutoIncrement() {
+			return ErrValueRequired
+		}
+
+		return nil
+	}
+
+	switch value.(type) {
+	case pgtype.Value, driver.Valuer:
+		// such values are encoding by themselves
+		return nil
+	}
+
+	arr, isArrayCol := col.(interface{ IsArray() bool })
+	if isArrayCol && arr.IsArray() {
+		if s, ok := value.(string); ok {
+			if !strings.HasPrefix(strings.TrimSpace(s), "{") {
+				return ErrValueNotArray
+			}
+
+			return nil
+		}
+
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return ErrValueNotArray
+		}
+
+		elemCol := col
+		if a, ok := col.(interface{ Elem() Column }); ok {
+			elemCol = a.Elem()
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if err := validateScalar(elemCol, v.Index(i).Interface()); err != nil {
+				return errors.Wrapf(err, "element %d", i)
+			}
+		}
+
+		return nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		switch col.BasicType() {
+		case types.String, types.UnsafePointer, types.UntypedNil:
+			// bytea, json & user types may take slices
+		default:
+			return ErrValueIsArray
+		}
+	}
+
+	return validateScalar(col, value)
+}
+
+func validateScalar(col Column, value any) error {
+	if isNilValue(value) {
+		return nil
+	}
+
+	value = reflect.Indirect(reflect.ValueOf(value)).Interface()
+	if err := validateType(col.BasicType(), value); err != nil {
+		return err
+	}
+
+	if s, ok := value.(string); ok {
+		if maxLen := col.CharacterMaximumLength(); maxLen > 0 && utf8.RuneCountInString(s) > maxLen {
+			return errors.Wrapf(ErrValueTooLong, "%d chars, max %d", utf8.RuneCountInString(s), maxLen)
+		}
+
+		if udt := col.UserDefinedType(); udt != nil && len(udt.Enumerates) > 0 && !slices.Contains(udt.Enumerates, s) {
+			return errors.Wrapf(ErrValueNotEnum, "'%s' not in %v", s, udt.Enumerates)
+		}
+	}
+
+	return nil
+}
+
+func validateType(kind types.BasicKind, value any) error {
+	v := reflect.ValueOf(value)
+	s, isString := value.(string)
+	ok := true
+	switch kind {
+	case types.Bool:
+		if isString {
+			_, err := strconv.ParseBool(s)
+			ok = err == nil
+		} else {
+			ok = v.Kind() == reflect.Bool
+		}
+
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+		switch {
+		case isString:
+			_, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+			ok = err == nil
+		case v.CanInt(), v.CanUint():
+		case v.CanFloat():
+			ok = v.Float() == float64(int64(v.Float()))
+		default:
+			ok = false
+		}
+
+	case types.Float32, types.Float64:
+		switch {
+		case isString:
+			_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			ok = err == nil
+		default:
+			ok = v.CanInt() || v.CanUint() || v.CanFloat()
+		}
+
+	case types.String:
+		switch value.(type) {
+		case string, []byte, []rune, [16]byte, fmt.Stringer:
+		default:
+			ok = false
+		}
+
+	case typesExt.TStruct:
+		switch value.(type) {
+		case time.Time, string:
+		default:
+			// ranges & points are struct too
+			ok = v.Kind() == reflect.Struct
+		}
+	}
+
+	if !ok {
+		return errors.Wrapf(ErrValueWrongType, "%T for %s", value, typesExt.StringTypeKinds(kind))
+	}
+
+	return nil
+}
+
+func isNilValue(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Author: Ruslan Bikchentaev. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dbEngine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type enumColumn struct {
+	*StringColumn
+}
+
+func (c enumColumn) UserDefinedType() *Types {
+	return &Types{Name: "status", Enumerates: []string{"new", "done"}}
+}
+
+func TestValidateValues(t *testing.T) {
+	table := NewTableString("tasks", "", []Column{
+		NewNumberColumn("id", "", true),
+		NewStringColumn("title", "", true, 10),
+		NewBoolColumn("is_active", "", false),
+		NewTimeColumn("created_at", "", "timestamptz", false),
+		NewNumericColumn("price", "", false, 10, 2),
+		NewArrayColumn("tags", "", false, NewStringColumn("tags", "", false, 5)),
+		enumColumn{NewStringColumn("status", "", false)},
+	}, nil, nil)
+
+	err := ValidateValues(table,
+		[]string{"id", "title", "is_active", "created_at", "price", "tags", "status"},
+		[]any{1, "short", "true", time.Now(), "10.5", []string{"a", "b"}, "done"})
+	require.NoError(t, err)
+
+	var title *string
+	err = table.Validate(
+		[]string{"id", "title", "is_active", "created_at", "price", "tags", "status", "unknown"},
+		[]any{1.5, title, 1, 15, []float64{1}, "a", "canceled", 0})
+	errValidation, ok := IsErrorValidation(err)
+	require.True(t, ok, err)
+	assert.Len(t, errValidation, 8)
+	assert.ErrorIs(t, errValidation["id"], ErrValueWrongType)
+	assert.ErrorIs(t, errValidation["title"], ErrValueRequired)
+	assert.ErrorIs(t, errValidation["is_active"], ErrValueWrongType)
+	assert.ErrorIs(t, errValidation["created_at"], ErrValueWrongType)
+	assert.ErrorIs(t, errValidation["price"], ErrValueIsArray)
+	assert.ErrorIs(t, errValidation["tags"], ErrValueNotArray)
+	assert.ErrorIs(t, errValidation["status"], ErrValueNotEnum)
+	assert.IsType(t, &ErrNotFoundColumn{}, errValidation["unknown"])
+	assert.ErrorIs(t, err, ErrValueNotEnum)
+
+	err = table.Validate([]string{"title", "tags"}, []any{"too long title", []string{"ok", "too long"}})
+	errValidation, ok = IsErrorValidation(err)
+	require.True(t, ok, err)
+	assert.ErrorIs(t, errValidation["title"], ErrValueTooLong)
+	assert.ErrorIs(t, errValidation["tags"], ErrValueTooLong)
+
+	assert.IsType(t, &ErrWrongArgsLen{}, table.Validate([]string{"id"}, nil))
+}
+
+func TestSQLBuilder_ValidateArgs(t *testing.T) {
+	table := NewTableString("tasks", "", []Column{
+		NewNumberColumn("id", "", true),
+		NewStringColumn("title", "", true, 10),
+	}, nil, nil)
+
+	b, err := NewSQLBuilder(table, Columns("title"), Values(nil))
+	require.NoError(t, err)
+	assert.NoError(t, b.ValidateArgs(true), "validation is off by default")
+
+	b, err = NewSQLBuilder(table, Columns("title"), Where("id"), Values("new", 1), ValidateBeforeWrite())
+	require.NoError(t, err)
+	assert.NoError(t, b.ValidateArgs(false))
+
+	errValidation, ok := IsErrorValidation(b.ValidateArgs(true))
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation{"id": ErrValueRequired}, errValidation)
+}
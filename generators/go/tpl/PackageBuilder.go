@@ -53,6 +53,7 @@ func (c *PackageBuilder) PrepareTable(table dbEngine.Table) *Table {
 	c.Imports = maps.Collect(func(yield func(string, struct{}) bool) {
 		for _, name := range []string{
 			"fmt",
+			"io",
 			"slices",
 			"sync",
 			"time",
@@ -106,10 +107,30 @@ func (c *PackageBuilder) PrepareTable(table dbEngine.Table) *Table {
 		}
 	})
 
-	return NewTable(name, table.Name(), table.Comment(), table.(*psql.Table).Type, columns, c.SortImports(), properties)
+	t := NewTable(name, table.Name(), table.Comment(), table.(*psql.Table).Type, columns, c.SortImports(), properties)
+	t.relations = c.tableRelations(table)
+
+	return t
 	//_, err = fmt.Fprintf(f, footer, name, caseRefFields, caseColFields, table.Name(), c.initValues)
 }
 
+// tableRelations return comments about foreign keys of table & tables which reference it
+func (c *PackageBuilder) tableRelations(table dbEngine.Table) string {
+	relations := ""
+	for _, fk := range table.ForeignKeys() {
+		relations += fmt.Sprintf("\n// foreign key %s (%s) references %s (%s)",
+			fk.Name, strings.Join(fk.Columns, ", "), fk.Parent, strings.Join(fk.ParentColumns, ", "))
+	}
+
+	if c.DB != nil {
+		if refs := c.ReferencingTables(table.Name()); len(refs) > 0 {
+			relations += "\n// referenced by " + strings.Join(refs, ", ")
+		}
+	}
+
+	return relations
+}
+
 func (c *PackageBuilder) GetFuncForDecode(tAttr *dbEngine.TypesAttr, ind int) string {
 	tName, name := tAttr.Type, tAttr.Name
 	switch _, isTypes := c.DB.Types[strings.ToLower(tName)]; {
@@ -143,6 +164,10 @@ func (c *PackageBuilder) GetFuncForDecode(tAttr *dbEngine.TypesAttr, ind int) st
 }
 
 func (c *PackageBuilder) udtToReturnType(udtName string) string {
+	if typeReturn, ok := c.registeredType(udtName); ok {
+		return typeReturn
+	}
+
 	toType := psql.UdtNameToType(udtName, c.DB.Types, c.Tables)
 	switch toType {
 	case types.UnsafePointer:
@@ -227,6 +252,19 @@ func (c *PackageBuilder) ChkTypes(col dbEngine.Column, propName string) (string,
 			}
 		}
 	}
+	if typeCol, ok := c.registeredType(col.Type()); ok {
+		switch {
+		// pgtype values & slices handle NULL themselves
+		case typeCol != "string":
+			defValue = nil
+		case col.IsNullable():
+			typeCol = "sql.NullString"
+			c.addImport(moduloSql)
+		}
+
+		return typeCol, defValue
+	}
+
 	typeCol := strings.TrimSpace(typesExt.Basic(bTypeCol).String())
 	isArray := strings.HasPrefix(col.Type(), "_") || strings.HasSuffix(col.Type(), "[]")
 
@@ -286,6 +324,20 @@ func (c *PackageBuilder) ChkTypes(col dbEngine.Column, propName string) (string,
 	return typeCol, defValue
 }
 
+// registeredType return GoType of type registered by dbEngine.RegisterType
+func (c *PackageBuilder) registeredType(udtName string) (string, bool) {
+	t, ok := dbEngine.LookupType(udtName)
+	if !ok || t.GoType == "" {
+		return "", false
+	}
+
+	if strings.Contains(t.GoType, "pgtype.") {
+		c.addImport(moduloPgType)
+	}
+
+	return t.GoType, true
+}
+
 func (c *PackageBuilder) ChkDataType(typeCol string) (*pgtype.DataType, bool) {
 	return psql.ChkDataType(context.TODO(), c.DB, typeCol)
 }
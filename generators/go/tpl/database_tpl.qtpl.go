@@ -350,7 +350,7 @@ func (d *Database) PsqlConn() *psql.Conn {
 	return (d.Conn).(*psql.Conn)
 }
 
-// SaveDataToTable
+// SaveDataToTable copies records of gob stream (see psql.ExportGob) into table
 func (d *Database) SaveDataToTable(ctx context.Context, table string, r io.Reader, columns ... string) (int64, error) {
 	switch table {
 `)
@@ -376,9 +376,21 @@ func (d *Database) SaveDataToTable(ctx context.Context, table string, r io.Reade
 			return -1, err
 		}
 
-		err = gob.NewDecoder(r).Decode(&t.DoCopyPoll)
-		if err != nil {
-			return -1, err
+		dec := gob.NewDecoder(r)
+		for {
+			var records []*`)
+//line database_tpl.qtpl:265
+			qw422016.E().S(strcase.ToCamel(name))
+//line database_tpl.qtpl:265
+			qw422016.N().S(`Fields
+			err = dec.Decode(&records)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return -1, err
+			}
+			t.DoCopyPoll = append(t.DoCopyPoll, records...)
 		}
 
 		t.doCopyPoolCount = len(t.DoCopyPoll)
@@ -386,82 +398,82 @@ func (d *Database) SaveDataToTable(ctx context.Context, table string, r io.Reade
 
 		return t.doCopy(ctx)
 	`)
-//line database_tpl.qtpl:272
+//line database_tpl.qtpl:279
 		}
-//line database_tpl.qtpl:272
+//line database_tpl.qtpl:279
 		qw422016.N().S(`
 `)
-//line database_tpl.qtpl:273
+//line database_tpl.qtpl:280
 	}
-//line database_tpl.qtpl:273
+//line database_tpl.qtpl:280
 	qw422016.N().S(`	default:
 		return -1, dbEngine.NewErrNotFoundTable(table)
 	}
 }
 `)
-//line database_tpl.qtpl:278
+//line database_tpl.qtpl:285
 	for _, name := range listTables {
-//line database_tpl.qtpl:278
+//line database_tpl.qtpl:285
 		StreamCreateTableConstructor(qw422016, strcase.ToCamel(name), name)
-//line database_tpl.qtpl:278
+//line database_tpl.qtpl:285
 	}
-//line database_tpl.qtpl:279
+//line database_tpl.qtpl:286
 	for _, name := range listRoutines {
-//line database_tpl.qtpl:279
+//line database_tpl.qtpl:286
 		c.StreamCreateRoutinesInvoker(qw422016, c.Routines[name].(*psql.Routine), name)
-//line database_tpl.qtpl:279
+//line database_tpl.qtpl:286
 	}
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 }
 
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 func (c *PackageBuilder) WriteCreateDatabase(qq422016 qtio422016.Writer, title string, imports, listTables, listRoutines []string) {
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	c.StreamCreateDatabase(qw422016, title, imports, listTables, listRoutines)
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	qt422016.ReleaseWriter(qw422016)
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 }
 
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 func (c *PackageBuilder) CreateDatabase(title string, imports, listTables, listRoutines []string) string {
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	qb422016 := qt422016.AcquireByteBuffer()
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	c.WriteCreateDatabase(qb422016, title, imports, listTables, listRoutines)
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	qs422016 := string(qb422016.B)
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	qt422016.ReleaseByteBuffer(qb422016)
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 	return qs422016
-//line database_tpl.qtpl:280
+//line database_tpl.qtpl:287
 }
 
-//line database_tpl.qtpl:282
+//line database_tpl.qtpl:289
 func (c *PackageBuilder) StreamCreateTypeInterface(qw422016 *qt422016.Writer, t dbEngine.Types, typeName, name, typeCol string) {
-//line database_tpl.qtpl:283
+//line database_tpl.qtpl:290
 	if len(t.Enumerates) == 0 && len(t.Attr) > 0 && t.Attr[0].Name != "domain" {
-//line database_tpl.qtpl:283
+//line database_tpl.qtpl:290
 		qw422016.N().S(`// `)
-//line database_tpl.qtpl:284
+//line database_tpl.qtpl:291
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:284
+//line database_tpl.qtpl:291
 		qw422016.N().S(` create new instance of type `)
-//line database_tpl.qtpl:284
+//line database_tpl.qtpl:291
 		qw422016.E().S(name)
-//line database_tpl.qtpl:284
+//line database_tpl.qtpl:291
 		qw422016.N().S(`
 //  add Rows interface
 type `)
-//line database_tpl.qtpl:286
+//line database_tpl.qtpl:293
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:286
+//line database_tpl.qtpl:293
 		qw422016.N().S(` struct {
     `)
-//line database_tpl.qtpl:288
+//line database_tpl.qtpl:295
 		maxName := len(slices.MaxFunc(t.Attr, func(a, b dbEngine.TypesAttr) int {
 			return len(a.Name) - len(b.Name)
 		}).Name)
@@ -469,96 +481,96 @@ type `)
 			return len(a.Type) - len(b.Type)
 		}).Type)
 
-//line database_tpl.qtpl:294
+//line database_tpl.qtpl:301
 		qw422016.N().S(`
 `)
-//line database_tpl.qtpl:295
+//line database_tpl.qtpl:302
 		for _, attr := range t.Attr {
-//line database_tpl.qtpl:295
+//line database_tpl.qtpl:302
 			qw422016.N().S(`	`)
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 			qw422016.N().S(fmt.Sprintf("%-*s\t\t%-*s\t `json:\"%s", maxName, strcase.ToCamel(attr.Name), maxType, attr.Type, attr.Name))
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 			if !attr.NotOmited() {
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 				qw422016.N().S(`,omitempty`)
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 			}
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 			qw422016.N().S(`"`)
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 			qw422016.N().S("`")
-//line database_tpl.qtpl:296
+//line database_tpl.qtpl:303
 			qw422016.N().S(`
 `)
-//line database_tpl.qtpl:297
+//line database_tpl.qtpl:304
 		}
-//line database_tpl.qtpl:298
+//line database_tpl.qtpl:305
 		if t.Type == 'r' {
-//line database_tpl.qtpl:298
+//line database_tpl.qtpl:305
 			qw422016.N().S(`	LowerType pgtype.BoundType
 	UpperType pgtype.BoundType
 `)
-//line database_tpl.qtpl:301
+//line database_tpl.qtpl:308
 		}
-//line database_tpl.qtpl:301
+//line database_tpl.qtpl:308
 		qw422016.N().S(`}
 
 // New implement ValueDecoder[T any] interface
 func (dst *`)
-//line database_tpl.qtpl:305
+//line database_tpl.qtpl:312
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:305
+//line database_tpl.qtpl:312
 		qw422016.N().S(`) New() *`)
-//line database_tpl.qtpl:305
+//line database_tpl.qtpl:312
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:305
+//line database_tpl.qtpl:312
 		qw422016.N().S(`{
 	return &`)
-//line database_tpl.qtpl:306
+//line database_tpl.qtpl:313
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:306
+//line database_tpl.qtpl:313
 		qw422016.N().S(`{}
 }
 
 // DecodeText implement pgtype.TextDecoder interface
 func (dst *`)
-//line database_tpl.qtpl:310
+//line database_tpl.qtpl:317
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:310
+//line database_tpl.qtpl:317
 		qw422016.N().S(`) DecodeText(ci *pgtype.ConnInfo, src []byte) error {
 	*dst = `)
-//line database_tpl.qtpl:311
+//line database_tpl.qtpl:318
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:311
+//line database_tpl.qtpl:318
 		qw422016.N().S(`{}
 	if len(src) == 0 {
 		return nil
 	}
 	`)
-//line database_tpl.qtpl:315
+//line database_tpl.qtpl:322
 		if t.Type == 'r' {
-//line database_tpl.qtpl:315
+//line database_tpl.qtpl:322
 			qw422016.N().S(`
 	utr, err := pgtype.ParseUntypedTextRange(gotools.BytesToString(src))
 	if err != nil {
 		return err
 	}
 	`)
-//line database_tpl.qtpl:320
+//line database_tpl.qtpl:327
 		} else {
-//line database_tpl.qtpl:320
+//line database_tpl.qtpl:327
 			qw422016.N().S(`
 	c := pgtype.NewCompositeTextScanner(ci, src)
 `)
-//line database_tpl.qtpl:322
+//line database_tpl.qtpl:329
 		}
-//line database_tpl.qtpl:322
+//line database_tpl.qtpl:329
 		qw422016.N().S(`
 `)
-//line database_tpl.qtpl:324
+//line database_tpl.qtpl:331
 		if t.Type == 'r' {
-//line database_tpl.qtpl:324
+//line database_tpl.qtpl:331
 			qw422016.N().S(`    	dst.LowerType = utr.LowerType
     	dst.UpperType = utr.UpperType
 
@@ -578,77 +590,77 @@ func (dst *`)
     		}
     	}
 `)
-//line database_tpl.qtpl:343
+//line database_tpl.qtpl:350
 		} else {
-//line database_tpl.qtpl:344
+//line database_tpl.qtpl:351
 			for _, attr := range t.Attr {
-//line database_tpl.qtpl:344
+//line database_tpl.qtpl:351
 				qw422016.N().S(`	`)
-//line database_tpl.qtpl:345
+//line database_tpl.qtpl:352
 				if strings.HasPrefix(attr.Type, "pgtype.") || strings.HasPrefix(attr.Name, "psql.") {
-//line database_tpl.qtpl:345
+//line database_tpl.qtpl:352
 					qw422016.N().S(`
 	c.ScanDecoder`)
-//line database_tpl.qtpl:346
+//line database_tpl.qtpl:353
 				} else {
-//line database_tpl.qtpl:346
+//line database_tpl.qtpl:353
 					qw422016.N().S(`c.ScanValue`)
-//line database_tpl.qtpl:346
+//line database_tpl.qtpl:353
 				}
-//line database_tpl.qtpl:346
+//line database_tpl.qtpl:353
 				qw422016.N().S(`(&dst.`)
-//line database_tpl.qtpl:346
+//line database_tpl.qtpl:353
 				qw422016.E().S(strcase.ToCamel(attr.Name))
-//line database_tpl.qtpl:346
+//line database_tpl.qtpl:353
 				qw422016.N().S(`)
 	if c.Err() != nil {
 		return c.Err()
 	}
 `)
-//line database_tpl.qtpl:350
+//line database_tpl.qtpl:357
 			}
-//line database_tpl.qtpl:351
+//line database_tpl.qtpl:358
 		}
-//line database_tpl.qtpl:351
+//line database_tpl.qtpl:358
 		qw422016.N().S(`
 	return nil
 }
 
 // DecodeBinary implement pgtype.BinaryDecoder interface
 func (dst *`)
-//line database_tpl.qtpl:357
+//line database_tpl.qtpl:364
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:357
+//line database_tpl.qtpl:364
 		qw422016.N().S(`) DecodeBinary(ci *pgtype.ConnInfo, src []byte) error {
 	*dst = `)
-//line database_tpl.qtpl:358
+//line database_tpl.qtpl:365
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:358
+//line database_tpl.qtpl:365
 		qw422016.N().S(`{}
 	if len(src) == 0 {
 		return nil
 	}
 
 `)
-//line database_tpl.qtpl:363
+//line database_tpl.qtpl:370
 		if t.Type == 'r' {
-//line database_tpl.qtpl:363
+//line database_tpl.qtpl:370
 			qw422016.N().S(`	utr, err := pgtype.ParseUntypedBinaryRange(src)
 	if err != nil {
 		return err
 	}
 `)
-//line database_tpl.qtpl:368
+//line database_tpl.qtpl:375
 		} else {
-//line database_tpl.qtpl:368
+//line database_tpl.qtpl:375
 			qw422016.N().S(`	c := pgtype.NewCompositeBinaryScanner(ci, src)
 	countFields := c.FieldCount()
 `)
-//line database_tpl.qtpl:371
+//line database_tpl.qtpl:378
 		}
-//line database_tpl.qtpl:372
+//line database_tpl.qtpl:379
 		if t.Type == 'r' {
-//line database_tpl.qtpl:372
+//line database_tpl.qtpl:379
 			qw422016.N().S(`    	dst.LowerType = utr.LowerType
     	dst.UpperType = utr.UpperType
 
@@ -668,74 +680,74 @@ func (dst *`)
     		}
     	}
 `)
-//line database_tpl.qtpl:391
+//line database_tpl.qtpl:398
 		} else {
-//line database_tpl.qtpl:392
+//line database_tpl.qtpl:399
 			for i, attr := range t.Attr {
-//line database_tpl.qtpl:392
+//line database_tpl.qtpl:399
 				qw422016.N().S(`	//	    rich end of elements
 	if countFields < `)
-//line database_tpl.qtpl:394
+//line database_tpl.qtpl:401
 				qw422016.N().D(i + 1)
-//line database_tpl.qtpl:394
+//line database_tpl.qtpl:401
 				qw422016.N().S(` || !c.Next() {
 		return nil
 	}
 	if err := `)
-//line database_tpl.qtpl:397
+//line database_tpl.qtpl:404
 				if strings.HasPrefix(attr.Name, "pgtype.") || strings.HasPrefix(attr.Name, "psql.") {
-//line database_tpl.qtpl:397
+//line database_tpl.qtpl:404
 					qw422016.N().S(`&dst.`)
-//line database_tpl.qtpl:397
+//line database_tpl.qtpl:404
 					qw422016.E().S(strcase.ToCamel(attr.Name))
-//line database_tpl.qtpl:397
+//line database_tpl.qtpl:404
 					qw422016.N().S(`.DecodeBinary(ci, c.Bytes())
 `)
-//line database_tpl.qtpl:398
+//line database_tpl.qtpl:405
 				} else {
-//line database_tpl.qtpl:398
+//line database_tpl.qtpl:405
 					qw422016.N().S(`ci.Scan(c.OID(), pgtype.BinaryFormatCode, c.Bytes(), &dst.`)
-//line database_tpl.qtpl:398
+//line database_tpl.qtpl:405
 					qw422016.E().S(strcase.ToCamel(attr.Name))
-//line database_tpl.qtpl:398
+//line database_tpl.qtpl:405
 					qw422016.N().S(`)`)
-//line database_tpl.qtpl:398
+//line database_tpl.qtpl:405
 				}
-//line database_tpl.qtpl:398
+//line database_tpl.qtpl:405
 				qw422016.N().S(`; err != nil {
 		logs.ErrorLog(err, "`)
-//line database_tpl.qtpl:399
+//line database_tpl.qtpl:406
 				qw422016.E().S(typeName)
-//line database_tpl.qtpl:399
+//line database_tpl.qtpl:406
 				qw422016.N().S(`.`)
-//line database_tpl.qtpl:399
+//line database_tpl.qtpl:406
 				qw422016.E().S(strcase.ToCamel(attr.Name))
-//line database_tpl.qtpl:399
+//line database_tpl.qtpl:406
 				qw422016.N().S(`")
 		return err
 	}
 `)
-//line database_tpl.qtpl:402
+//line database_tpl.qtpl:409
 			}
-//line database_tpl.qtpl:403
+//line database_tpl.qtpl:410
 		}
-//line database_tpl.qtpl:403
+//line database_tpl.qtpl:410
 		qw422016.N().S(`
 	return nil
 }
 
 // Scan implement sql.Scanner interface
 func (dst *`)
-//line database_tpl.qtpl:409
+//line database_tpl.qtpl:416
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:409
+//line database_tpl.qtpl:416
 		qw422016.N().S(`) Scan(src any) error {
 	switch src := src.(type) {
 	case nil:
 		*dst = `)
-//line database_tpl.qtpl:412
+//line database_tpl.qtpl:419
 		qw422016.E().S(typeName)
-//line database_tpl.qtpl:412
+//line database_tpl.qtpl:419
 		qw422016.N().S(`{}
 		return nil
 	case string:
@@ -747,65 +759,65 @@ func (dst *`)
 	}
 }
 `)
-//line database_tpl.qtpl:422
+//line database_tpl.qtpl:429
 	}
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 }
 
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 func (c *PackageBuilder) WriteCreateTypeInterface(qq422016 qtio422016.Writer, t dbEngine.Types, typeName, name, typeCol string) {
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	c.StreamCreateTypeInterface(qw422016, t, typeName, name, typeCol)
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	qt422016.ReleaseWriter(qw422016)
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 }
 
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 func (c *PackageBuilder) CreateTypeInterface(t dbEngine.Types, typeName, name, typeCol string) string {
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	qb422016 := qt422016.AcquireByteBuffer()
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	c.WriteCreateTypeInterface(qb422016, t, typeName, name, typeCol)
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	qs422016 := string(qb422016.B)
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	qt422016.ReleaseByteBuffer(qb422016)
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 	return qs422016
-//line database_tpl.qtpl:423
+//line database_tpl.qtpl:430
 }
 
 // end CreateTypeInterface
 //
 
-//line database_tpl.qtpl:426
+//line database_tpl.qtpl:433
 func StreamCreateTableConstructor(qw422016 *qt422016.Writer, goName, name string) {
-//line database_tpl.qtpl:426
+//line database_tpl.qtpl:433
 	qw422016.N().S(`// New`)
-//line database_tpl.qtpl:427
+//line database_tpl.qtpl:434
 	qw422016.E().S(goName)
-//line database_tpl.qtpl:427
+//line database_tpl.qtpl:434
 	qw422016.N().S(` create new instance of table `)
-//line database_tpl.qtpl:427
+//line database_tpl.qtpl:434
 	qw422016.E().S(goName)
-//line database_tpl.qtpl:427
+//line database_tpl.qtpl:434
 	qw422016.N().S(`
 func (d *Database) New`)
-//line database_tpl.qtpl:428
+//line database_tpl.qtpl:435
 	qw422016.E().S(goName)
-//line database_tpl.qtpl:428
+//line database_tpl.qtpl:435
 	qw422016.N().S(`(ctx context.Context) (*`)
-//line database_tpl.qtpl:428
+//line database_tpl.qtpl:435
 	qw422016.E().S(goName)
-//line database_tpl.qtpl:428
+//line database_tpl.qtpl:435
 	qw422016.N().S(`, error) {
 	switch table, err := New`)
-//line database_tpl.qtpl:429
+//line database_tpl.qtpl:436
 	qw422016.E().S(goName)
-//line database_tpl.qtpl:429
+//line database_tpl.qtpl:436
 	qw422016.N().S(`(d.DB); err.(type) {
 	case nil:
 		return table, nil
@@ -813,9 +825,9 @@ func (d *Database) New`)
 	// no found on Database - get data of table from Conn
 	case dbEngine.ErrNotFoundTable:
 		table, err := New`)
-//line database_tpl.qtpl:435
+//line database_tpl.qtpl:442
 	qw422016.E().S(goName)
-//line database_tpl.qtpl:435
+//line database_tpl.qtpl:442
 	qw422016.N().S(`FromConn(ctx, d.PsqlConn())
 		if err != nil {
 			return nil, err
@@ -828,31 +840,31 @@ func (d *Database) New`)
 	}
 }
 `)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 }
 
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 func WriteCreateTableConstructor(qq422016 qtio422016.Writer, goName, name string) {
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	StreamCreateTableConstructor(qw422016, goName, name)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	qt422016.ReleaseWriter(qw422016)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 }
 
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 func CreateTableConstructor(goName, name string) string {
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	qb422016 := qt422016.AcquireByteBuffer()
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	WriteCreateTableConstructor(qb422016, goName, name)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	qs422016 := string(qb422016.B)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	qt422016.ReleaseByteBuffer(qb422016)
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 	return qs422016
-//line database_tpl.qtpl:446
+//line database_tpl.qtpl:453
 }
@@ -8,6 +8,8 @@ type Table struct {
 	imports    []string
 	typ        string
 	properties map[string]string
+	// comments about foreign keys of table
+	relations string
 }
 
 func NewTable(name, dbName, comment, typ string, columns, imports []string, properties map[string]string) *Table {
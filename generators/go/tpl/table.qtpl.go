@@ -44,7 +44,11 @@ func (t *Table) StreamTable(qw422016 *qt422016.Writer, title string) {
 //line table.qtpl:12
 	qw422016.E().S(t.comment)
 //line table.qtpl:12
-	qw422016.N().S(`'
+	qw422016.N().S(`'`)
+//line table.qtpl:12
+	qw422016.N().S(t.relations)
+//line table.qtpl:12
+	qw422016.N().S(`
 type `)
 //line table.qtpl:13
 	qw422016.E().S(t.name)
@@ -210,8 +214,29 @@ func (t *`)
 	t.doCopyErr = nil
 	t.poolDuration = d
 
+	// flush pending records when connection closes,
+	// goroutine of ticker is stopped before, so it doesn't copy records during flushing
+	stop, done := make(chan struct{}), make(chan struct{})
+	var stopOnce sync.Once
+	t.RegisterCopyPool(func(ctx context.Context) error {
+		stopOnce.Do(func() { close(stop) })
+		<-done
+
+		failRecords, err := t.FlashPoolAndReset(ctx)
+		if len(failRecords) > 0 {
+			return fmt.Errorf("%d records of '`)
+//line table.qtpl:112
+	qw422016.E().S(t.dbName)
+//line table.qtpl:112
+	qw422016.N().S(`' were not flushed: %w", len(failRecords), err)
+		}
+
+		return err
+	})
+
 	// run DoCopy every 'd' ms
 	go func() {
+		defer close(done)
 		defer t.ticket.Stop()
 		for {
 			select {
@@ -222,19 +247,23 @@ func (t *`)
 				}
 			case <-ctx.Done():
 				return
+			case <-t.Done():
+				return
+			case <-stop:
+				return
 			}
 		}
 	}()
 }
 // AddToPoolCopy add 'record' into copy pool
 func (t *`)
-//line table.qtpl:119
+//line table.qtpl:140
 	qw422016.E().S(t.name)
-//line table.qtpl:119
+//line table.qtpl:140
 	qw422016.N().S(`) AddToPoolCopy(ctx context.Context, record ... *`)
-//line table.qtpl:119
+//line table.qtpl:140
 	qw422016.E().S(t.name)
-//line table.qtpl:119
+//line table.qtpl:140
 	qw422016.N().S(`Fields) error {
 	if t.doCopyErr != nil {
 		return t.doCopyErr
@@ -263,13 +292,13 @@ func (t *`)
 // FlashPoolAndReset inserted all record from Poll as is
 // returns slice of records which were be not inserted
 func (t *`)
-//line table.qtpl:146
+//line table.qtpl:167
 	qw422016.E().S(t.name)
-//line table.qtpl:146
+//line table.qtpl:167
 	qw422016.N().S(`) FlashPoolAndReset(ctx context.Context) ([]*`)
-//line table.qtpl:146
+//line table.qtpl:167
 	qw422016.E().S(t.name)
-//line table.qtpl:146
+//line table.qtpl:167
 	qw422016.N().S(`Fields, error) {
 	t.ticket.Reset(t.poolDuration)
 	_, err := t.doCopy(ctx)
@@ -287,22 +316,22 @@ func (t *`)
 // InsertPoolAndReset inserted all record from Poll as is
 // returns slice of records which were be not inserted
 func (t *`)
-//line table.qtpl:162
+//line table.qtpl:183
 	qw422016.E().S(t.name)
-//line table.qtpl:162
+//line table.qtpl:183
 	qw422016.N().S(`) InsertPoolAndReset(ctx context.Context) []*`)
-//line table.qtpl:162
+//line table.qtpl:183
 	qw422016.E().S(t.name)
-//line table.qtpl:162
+//line table.qtpl:183
 	qw422016.N().S(`Fields {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
 	columns := t.doCopyPoolColumns
 	failRecords := make([]*`)
-//line table.qtpl:167
+//line table.qtpl:188
 	qw422016.E().S(t.name)
-//line table.qtpl:167
+//line table.qtpl:188
 	qw422016.N().S(`Fields,0)
 	allInserted := int64(0)
 
@@ -329,81 +358,81 @@ func (t *`)
 	return failRecords
 }
 // `)
-//line table.qtpl:192
+//line table.qtpl:213
 	qw422016.E().S(t.name)
-//line table.qtpl:192
+//line table.qtpl:213
 	qw422016.N().S(`Fields data object for '`)
-//line table.qtpl:192
+//line table.qtpl:213
 	qw422016.E().S(t.name)
-//line table.qtpl:192
+//line table.qtpl:213
 	qw422016.N().S(`' columns
 type `)
-//line table.qtpl:193
+//line table.qtpl:214
 	qw422016.E().S(t.name)
-//line table.qtpl:193
+//line table.qtpl:214
 	qw422016.N().S(`Fields struct {
 	// columns of table %s
 `)
-//line table.qtpl:195
+//line table.qtpl:216
 	for _, name := range t.columns {
-//line table.qtpl:195
+//line table.qtpl:216
 		qw422016.N().S(`	`)
-//line table.qtpl:196
+//line table.qtpl:217
 		qw422016.N().S(fmt.Sprintf(colFormat, strcase.ToCamel(name), t.properties[name], strings.ToLower(name)))
-//line table.qtpl:196
+//line table.qtpl:217
 		qw422016.N().S(`
 `)
-//line table.qtpl:197
+//line table.qtpl:218
 	}
-//line table.qtpl:197
+//line table.qtpl:218
 	qw422016.N().S(`}
 // New%sFields create new instance & fill struct fill for avoid panic
 func New`)
-//line table.qtpl:200
+//line table.qtpl:221
 	qw422016.E().S(t.name)
-//line table.qtpl:200
+//line table.qtpl:221
 	qw422016.N().S(`Fields() *`)
-//line table.qtpl:200
+//line table.qtpl:221
 	qw422016.E().S(t.name)
-//line table.qtpl:200
+//line table.qtpl:221
 	qw422016.N().S(`Fields{
 	return &`)
-//line table.qtpl:201
+//line table.qtpl:222
 	qw422016.E().S(t.name)
-//line table.qtpl:201
+//line table.qtpl:222
 	qw422016.N().S(`Fields{
 		// init properties `)
-//line table.qtpl:202
+//line table.qtpl:223
 	qw422016.E().S(t.typ)
-//line table.qtpl:202
+//line table.qtpl:223
 	qw422016.N().S(` initValues
 	}
 }
 // RefColValue return referral of column
 func (r *`)
-//line table.qtpl:206
+//line table.qtpl:227
 	qw422016.E().S(t.name)
-//line table.qtpl:206
+//line table.qtpl:227
 	qw422016.N().S(`Fields) RefColValue(name string) any{
 	switch name {
 	`)
-//line table.qtpl:208
+//line table.qtpl:229
 	for _, name := range t.columns {
-//line table.qtpl:208
+//line table.qtpl:229
 		qw422016.N().S(`case "`)
-//line table.qtpl:208
+//line table.qtpl:229
 		qw422016.E().S(name)
-//line table.qtpl:208
+//line table.qtpl:229
 		qw422016.N().S(`":
         return &r.`)
-//line table.qtpl:209
+//line table.qtpl:230
 		qw422016.E().S(strcase.ToCamel(name))
-//line table.qtpl:209
+//line table.qtpl:230
 		qw422016.N().S(`
     `)
-//line table.qtpl:210
+//line table.qtpl:231
 	}
-//line table.qtpl:210
+//line table.qtpl:231
 	qw422016.N().S(`
    	default:
 		return nil
@@ -411,29 +440,29 @@ func (r *`)
 }
 // ColValue return value of column
 func (r *`)
-//line table.qtpl:216
+//line table.qtpl:237
 	qw422016.E().S(t.name)
-//line table.qtpl:216
+//line table.qtpl:237
 	qw422016.N().S(`Fields) ColValue(name string) any{
 	switch name {
 	`)
-//line table.qtpl:218
+//line table.qtpl:239
 	for _, name := range t.columns {
-//line table.qtpl:218
+//line table.qtpl:239
 		qw422016.N().S(`case "`)
-//line table.qtpl:218
+//line table.qtpl:239
 		qw422016.E().S(name)
-//line table.qtpl:218
+//line table.qtpl:239
 		qw422016.N().S(`":
         return r.`)
-//line table.qtpl:219
+//line table.qtpl:240
 		qw422016.E().S(strcase.ToCamel(name))
-//line table.qtpl:219
+//line table.qtpl:240
 		qw422016.N().S(`
     `)
-//line table.qtpl:220
+//line table.qtpl:241
 	}
-//line table.qtpl:220
+//line table.qtpl:241
 	qw422016.N().S(`
    	default:
 		return nil
@@ -441,9 +470,9 @@ func (r *`)
 }
 // GetFields implement dbEngine.RowScanner interface
 func (r *`)
-//line table.qtpl:226
+//line table.qtpl:247
 	qw422016.E().S(t.name)
-//line table.qtpl:226
+//line table.qtpl:247
 	qw422016.N().S(`Fields) GetFields(columns []dbEngine.Column) []any {
 	v := make([]any, len(columns))
 	for i, col := range columns {
@@ -454,46 +483,46 @@ func (r *`)
 }
 // GetValue implement httpgo.RouteDTO interface
 func (r *`)
-//line table.qtpl:235
+//line table.qtpl:256
 	qw422016.E().S(t.name)
-//line table.qtpl:235
+//line table.qtpl:256
 	qw422016.N().S(`Fields) GetValue() any {
 	return r
 }
 // NewValue implement httpgo.RouteDTO interface
 func (r *`)
-//line table.qtpl:239
+//line table.qtpl:260
 	qw422016.E().S(t.name)
-//line table.qtpl:239
+//line table.qtpl:260
 	qw422016.N().S(`Fields) NewValue() any {
 	return New`)
-//line table.qtpl:240
+//line table.qtpl:261
 	qw422016.E().S(t.name)
-//line table.qtpl:240
+//line table.qtpl:261
 	qw422016.N().S(`Fields()
 }
 // NewRecord return new row of table
 func (t *`)
-//line table.qtpl:243
+//line table.qtpl:264
 	qw422016.E().S(t.name)
-//line table.qtpl:243
+//line table.qtpl:264
 	qw422016.N().S(`) NewRecord() *`)
-//line table.qtpl:243
+//line table.qtpl:264
 	qw422016.E().S(t.name)
-//line table.qtpl:243
+//line table.qtpl:264
 	qw422016.N().S(`Fields{
    t.Record = New`)
-//line table.qtpl:244
+//line table.qtpl:265
 	qw422016.E().S(t.name)
-//line table.qtpl:244
+//line table.qtpl:265
 	qw422016.N().S(`Fields()
 	return t.Record
 }
 // GetFields implement dbEngine.RowScanner interface
 func (t *`)
-//line table.qtpl:248
+//line table.qtpl:269
 	qw422016.E().S(t.name)
-//line table.qtpl:248
+//line table.qtpl:269
 	qw422016.N().S(`) GetFields(columns []dbEngine.Column) []any {
 	if len(columns) == 0 {
 		columns = t.Columns()
@@ -503,13 +532,13 @@ func (t *`)
 }
 // SelectSelfScanEach exec request to DB & populate record & call each for each row of query
 func (t *`)
-//line table.qtpl:256
+//line table.qtpl:277
 	qw422016.E().S(t.name)
-//line table.qtpl:256
+//line table.qtpl:277
 	qw422016.N().S(`) SelectSelfScanEach(ctx context.Context, each func(record *`)
-//line table.qtpl:256
+//line table.qtpl:277
 	qw422016.E().S(t.name)
-//line table.qtpl:256
+//line table.qtpl:277
 	qw422016.N().S(`Fields) error, Options ...dbEngine.BuildSqlOptions) error {
 	return t.SelectAndScanEach(ctx,
 			func() error {
@@ -522,13 +551,13 @@ func (t *`)
 }
 // SelectAll run sql according to Options & return slice of record
 func (t *`)
-//line table.qtpl:267
+//line table.qtpl:288
 	qw422016.E().S(t.name)
-//line table.qtpl:267
+//line table.qtpl:288
 	qw422016.N().S(`) SelectAll(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (res []*`)
-//line table.qtpl:267
+//line table.qtpl:288
 	qw422016.E().S(t.name)
-//line table.qtpl:267
+//line table.qtpl:288
 	qw422016.N().S(`Fields, err error) {
 	err = t.SelectAndScanEach(ctx,
 			func() error {
@@ -542,11 +571,30 @@ func (t *`)
 
 	return res, nil
 }
+// Export writes rows of '`)
+//line table.qtpl:301
+	qw422016.E().S(t.dbName)
+//line table.qtpl:301
+	qw422016.N().S(`' into w according to Options, gob stream may be read by SaveDataToTable
+func (t *`)
+//line table.qtpl:302
+	qw422016.E().S(t.name)
+//line table.qtpl:302
+	qw422016.N().S(`) Export(ctx context.Context, w io.Writer, format psql.ExportFormat, Options ...dbEngine.BuildSqlOptions) (int64, error) {
+	return t.ExportRecords(ctx, w, format,
+			func() dbEngine.RowScanner {
+				return New`)
+//line table.qtpl:305
+	qw422016.E().S(t.name)
+//line table.qtpl:305
+	qw422016.N().S(`Fields()
+			}, Options ... )
+}
 // Insert new record into table
 func (t *`)
-//line table.qtpl:281
+//line table.qtpl:309
 	qw422016.E().S(t.name)
-//line table.qtpl:281
+//line table.qtpl:309
 	qw422016.N().S(`) Insert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
 	if len(Options) == 0 {
 		v := make([]any, 0, len(t.Columns()))
@@ -570,17 +618,18 @@ func (t *`)
 }
 // Update record of table according to Options
 func (t *`)
-//line table.qtpl:303
+//line table.qtpl:331
 	qw422016.E().S(t.name)
-//line table.qtpl:303
+//line table.qtpl:331
 	qw422016.N().S(`) Update(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
 	if len(Options) == 0 {
 		v := make([]any, 0, len(t.Columns()))
 		priV := make([]any, 0)
 		columns := make([]string, 0, len(t.Columns()))
 		priColumns := make([]string, 0, len(t.Columns()))
+		keys := dbEngine.KeyColumns(t.Table)
 		for _, col := range t.Columns() {
-			if col.Primary() {
+			if slices.Contains(keys, col.Name()) {
 				priColumns = append( priColumns, col.Name() )
 				priV = append(priV, t.Record.ColValue( col.Name() ))
 				continue
@@ -603,17 +652,18 @@ func (t *`)
 
 // Upsert insert new Record into table according to Options or update if this record exists
 func (t *`)
-//line table.qtpl:332
+//line table.qtpl:361
 	qw422016.E().S(t.name)
-//line table.qtpl:332
+//line table.qtpl:361
 	qw422016.N().S(`) Upsert(ctx context.Context, Options ...dbEngine.BuildSqlOptions) (int64, error) {
 	if len(Options) == 0 {
 		v := make([]any, 0, len(t.Columns()))
 		priV := make([]any, 0)
 		columns := make([]string, 0, len(t.Columns()))
 		priColumns := make([]string, 0, len(t.Columns()))
+		keys := dbEngine.KeyColumns(t.Table)
 		for _, col := range t.Columns() {
-			if col.Primary() {
+			if slices.Contains(keys, col.Name()) {
 				priColumns = append( priColumns, col.Name() )
 				priV = append(priV, t.Record.ColValue( col.Name() ))
 			}
@@ -638,9 +688,9 @@ func (t *`)
 }
 
 func (t *`)
-//line table.qtpl:363
+//line table.qtpl:393
 	qw422016.E().S(t.name)
-//line table.qtpl:363
+//line table.qtpl:393
 	qw422016.N().S(`) doCopy(ctx context.Context) (int64, error) {
 	if len(t.DoCopyPoll) == 0 {
 		return -1, nil
@@ -669,31 +719,31 @@ func (t *`)
 	return i, nil
 }
 `)
-//line table.qtpl:390
+//line table.qtpl:420
 }
 
-//line table.qtpl:390
+//line table.qtpl:420
 func (t *Table) WriteTable(qq422016 qtio422016.Writer, title string) {
-//line table.qtpl:390
+//line table.qtpl:420
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line table.qtpl:390
+//line table.qtpl:420
 	t.StreamTable(qw422016, title)
-//line table.qtpl:390
+//line table.qtpl:420
 	qt422016.ReleaseWriter(qw422016)
-//line table.qtpl:390
+//line table.qtpl:420
 }
 
-//line table.qtpl:390
+//line table.qtpl:420
 func (t *Table) Table(title string) string {
-//line table.qtpl:390
+//line table.qtpl:420
 	qb422016 := qt422016.AcquireByteBuffer()
-//line table.qtpl:390
+//line table.qtpl:420
 	t.WriteTable(qb422016, title)
-//line table.qtpl:390
+//line table.qtpl:420
 	qs422016 := string(qb422016.B)
-//line table.qtpl:390
+//line table.qtpl:420
 	qt422016.ReleaseByteBuffer(qb422016)
-//line table.qtpl:390
+//line table.qtpl:420
 	return qs422016
-//line table.qtpl:390
+//line table.qtpl:420
 }